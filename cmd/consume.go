@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ChargePi/chargeflow/internal/consumer"
+	"github.com/ChargePi/chargeflow/internal/consumer/buses/kafka_bus"
+	"github.com/ChargePi/chargeflow/internal/consumer/buses/mqtt_bus"
+	"github.com/ChargePi/chargeflow/internal/consumer/buses/nats_bus"
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+const (
+	busKafka = "kafka"
+	busNats  = "nats"
+	busMqtt  = "mqtt"
+)
+
+var consumeSchemasFolder = ""
+
+var consumeCmd = &cobra.Command{
+	Use:   "consume",
+	Short: "Continuously validate OCPP frames consumed from Kafka, NATS or MQTT",
+	Long: `Consume raw OCPP frames from a message bus, validate them and publish a JSON verdict per frame to an output topic.
+The charge point ID, the direction and the OCPP version of each frame are read from the message headers (Kafka record headers,
+NATS headers or MQTT v5 user properties). Responses are paired with requests of the same charge point.`,
+	Example: `  # Validate the frames on a Kafka topic, publishing the verdicts to another topic
+  chargeflow consume --bus kafka --brokers localhost:9092 --topics ocpp-frames --output-topic ocpp-verdicts --group chargeflow
+
+  # Validate the frames published on NATS subjects
+  chargeflow consume --bus nats --url nats://localhost:4222 --topics 'ocpp.frames.>' --output-topic ocpp.verdicts
+
+  # Validate the frames published on MQTT topics, only reporting invalid frames
+  chargeflow --version 2.0.1 consume --bus mqtt --url mqtt://localhost:1883 --topics 'ocpp/frames/#' --output-topic ocpp/verdicts --only-invalid`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := zap.L()
+		ctx := cmd.Context()
+
+		topics := viper.GetStringSlice("consume.topics")
+		if len(topics) == 0 {
+			return errors.New("at least one topic is required")
+		}
+
+		octx := ocpp.OcppContext{
+			Version: configuredVersion(),
+			Vendor:  vendor,
+			Model:   model,
+		}
+
+		overwrite := consumeSchemasFolder != ""
+
+		consumeRegistry, err := buildValidationRegistry(logger, overwrite)
+		if err != nil {
+			return err
+		}
+
+		for _, version := range servedVersions {
+			if err := registerVersionSchemas(ctx, logger, version, consumeRegistry); err != nil {
+				return err
+			}
+		}
+
+		if overwrite {
+			if err := registerSchemasFromDir(ctx, logger, consumeRegistry, octx, consumeSchemasFolder); err != nil {
+				return err
+			}
+		}
+
+		bus, err := newBus(logger, viper.GetString("consume.bus"), topics)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := bus.Close(); err != nil {
+				logger.Warn("Unable to close the message bus", zap.Error(err))
+			}
+		}()
+
+		consumerOpts := []consumer.Option{
+			consumer.WithChargePointIdHeader(viper.GetString("consume.charge-point-header")),
+			consumer.WithDirectionHeader(viper.GetString("consume.direction-header")),
+			consumer.WithVersionHeader(viper.GetString("consume.version-header")),
+			consumer.WithOnlyInvalid(viper.GetBool("consume.only-invalid")),
+		}
+
+		validatorOpts, err := validatorOptions()
+		if err != nil {
+			return err
+		}
+
+		metricsAddr := viper.GetString("consume.metrics-addr")
+		if metricsAddr == "" {
+			service := validation.NewService(logger, consumeRegistry, validatorOpts)
+			return consumer.NewConsumer(logger, service, bus, octx, consumerOpts...).Run(ctx)
+		}
+
+		m := newMetrics(consumeRegistry)
+		service := validation.NewService(logger, consumeRegistry, validatorOpts, validation.WithObserver(m))
+
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.Go(func() error {
+			return serveMetrics(groupCtx, logger, metricsAddr, m)
+		})
+		group.Go(func() error {
+			return consumer.NewConsumer(logger, service, bus, octx, consumerOpts...).Run(groupCtx)
+		})
+
+		return group.Wait()
+	},
+}
+
+// newBus connects to the configured message bus.
+func newBus(logger *zap.Logger, busType string, topics []string) (consumer.Bus, error) {
+	outputTopic := viper.GetString("consume.output-topic")
+	group := viper.GetString("consume.group")
+	url := viper.GetString("consume.url")
+
+	switch busType {
+	case busKafka:
+		return kafka_bus.NewBus(logger, viper.GetStringSlice("consume.brokers"), topics,
+			kafka_bus.WithConsumerGroup(group),
+			kafka_bus.WithOutputTopic(outputTopic),
+			kafka_bus.WithFromBeginning(viper.GetBool("consume.from-beginning")),
+		)
+	case busNats:
+		if url == "" {
+			url = "nats://127.0.0.1:4222"
+		}
+
+		return nats_bus.NewBus(logger, url, topics,
+			nats_bus.WithQueueGroup(group),
+			nats_bus.WithOutputSubject(outputTopic),
+		)
+	case busMqtt:
+		if url == "" {
+			url = "mqtt://127.0.0.1:1883"
+		}
+
+		return mqtt_bus.NewBus(logger, url, topics,
+			mqtt_bus.WithClientID(viper.GetString("consume.client-id")),
+			mqtt_bus.WithOutputTopic(outputTopic),
+		)
+	default:
+		return nil, errors.Errorf("unsupported message bus %q, supported are: kafka, nats, mqtt", busType)
+	}
+}
+
+func init() {
+	consumeCmd.Flags().String("bus", busKafka, "Message bus to consume from: kafka, nats or mqtt")
+	consumeCmd.Flags().StringSlice("brokers", []string{"localhost:9092"}, "Kafka seed brokers")
+	consumeCmd.Flags().String("url", "", "NATS or MQTT server URL (defaults to nats://127.0.0.1:4222 and mqtt://127.0.0.1:1883)")
+	consumeCmd.Flags().StringSlice("topics", nil, "Topics (Kafka, MQTT) or subjects (NATS) to consume OCPP frames from. Wildcards are supported by NATS and MQTT")
+	consumeCmd.Flags().String("output-topic", "", "Topic or subject to publish the verdicts to. Verdicts are only logged when empty")
+	consumeCmd.Flags().String("group", "", "Kafka consumer group or NATS queue group")
+	consumeCmd.Flags().String("client-id", "chargeflow", "MQTT client ID")
+	consumeCmd.Flags().Bool("from-beginning", false, "Consume Kafka topics from the earliest offset instead of only new records")
+	consumeCmd.Flags().Bool("only-invalid", false, "Only publish verdicts of frames that are not valid")
+	consumeCmd.Flags().String("charge-point-header", "charge-point-id", "Header holding the charge point ID. Falls back to the Kafka record key")
+	consumeCmd.Flags().String("direction-header", "direction", "Header holding the direction of the frame")
+	consumeCmd.Flags().String("version-header", "ocpp-version", "Header holding the OCPP version of the frame. Falls back to --version")
+	consumeCmd.Flags().StringVarP(&consumeSchemasFolder, "schemas", "a", "", "Path to additional OCPP schemas folder")
+	consumeCmd.Flags().String("metrics-addr", "", "Address to serve Prometheus metrics on (e.g. ':9100'). Disabled when empty")
+
+	for _, flag := range []string{
+		"bus", "brokers", "url", "topics", "output-topic", "group", "client-id", "from-beginning",
+		"only-invalid", "charge-point-header", "direction-header", "version-header", "metrics-addr",
+	} {
+		_ = viper.BindPFlag("consume."+flag, consumeCmd.Flags().Lookup(flag))
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+// Exit codes of the CLI, so CI pipelines can tell invalid input apart from a failing tool.
+const (
+	ExitValid      = 0
+	ExitInvalid    = 1
+	ExitUnparsable = 2
+	ExitToolError  = 3
+)
+
+// ExitError is returned when the command completed, but the result should be reported with a non-zero exit code.
+type ExitError struct {
+	Code int
+	err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code for the error returned by Execute. Errors other than ExitError are tool errors.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitValid
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return ExitToolError
+}
+
+// exitCodeForOutcome returns the exit code for the outcome of evaluating a report against the thresholds.
+func exitCodeForOutcome(outcome report.Outcome) int {
+	switch outcome {
+	case report.OutcomeUnparsable:
+		return ExitUnparsable
+	case report.OutcomeInvalid:
+		return ExitInvalid
+	default:
+		return ExitValid
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitValid, ExitCode(nil))
+	assert.Equal(t, ExitToolError, ExitCode(errors.New("file not found")))
+	assert.Equal(t, ExitInvalid, ExitCode(errors.Wrap(&ExitError{Code: ExitInvalid, err: errors.New("invalid")}, "executing root command")))
+	assert.Equal(t, ExitUnparsable, ExitCode(&ExitError{Code: ExitUnparsable, err: errors.New("unparsable")}))
+}
+
+func Test_loadThresholds(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("fail-on", []string{"invalid", "unparsable"})
+		viper.Set("max-invalid-percent", 0.0)
+		viper.Set("fail-on-action", []string{})
+	})
+
+	viper.Set("fail-on", []string{"invalid"})
+	viper.Set("max-invalid-percent", 5.0)
+	viper.Set("fail-on-action", []string{"BootNotification"})
+
+	thresholds, err := loadThresholds()
+	require.NoError(t, err)
+	assert.Equal(t, report.Thresholds{
+		FailOnInvalid:     true,
+		MaxInvalidPercent: 5,
+		FailOnActions:     []string{"BootNotification"},
+	}, thresholds)
+
+	viper.Set("fail-on", []string{"everything"})
+	_, err = loadThresholds()
+	assert.Error(t, err)
+
+	viper.Set("fail-on", []string{"none"})
+	viper.Set("max-invalid-percent", 120.0)
+	_, err = loadThresholds()
+	assert.Error(t, err)
+}
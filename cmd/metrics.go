@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/metrics"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/remote_registry"
+)
+
+// newMetrics creates the validation metrics, including the schema cache metrics when using a remote registry.
+func newMetrics(registry schema_registry.SchemaRegistry) *metrics.Metrics {
+	m := metrics.NewMetrics()
+
+	if remoteRegistry, ok := registry.(*remote_registry.SchemaRegistry); ok {
+		if cache, ok := remoteRegistry.Cache().(*remote_registry.MemoryCache); ok {
+			m.RegisterCache(cache)
+		}
+	}
+
+	return m
+}
+
+// serveMetrics serves the metrics on GET /metrics until the context is canceled. Used by long-running modes
+// that don't expose an HTTP API of their own.
+func serveMetrics(ctx context.Context, logger *zap.Logger, addr string, m *metrics.Metrics) error {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", m.Handler())
+
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "unable to listen on %s", addr)
+	}
+
+	logger.Info("Serving metrics", zap.String("address", listener.Addr().String()))
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return errors.Wrap(err, "metrics server failed")
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return httpServer.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Work with validation reports",
+	Long:  `Commands for working with JSON validation reports, created with validate -o report.json.`,
+}
+
+// readReport reads a JSON validation report from the file.
+func readReport(path string) (*report.Report, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read report %s", path)
+	}
+
+	var r report.Report
+	if err = json.Unmarshal(b, &r); err != nil {
+		return nil, errors.Wrapf(err, "%s is not a JSON report", path)
+	}
+
+	return &r, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportDiffCmd)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+var reportDiffCmd = &cobra.Command{
+	Use:   "diff <old.json> <new.json>",
+	Short: "Show the failures introduced and resolved between two JSON reports",
+	Long: `Compares two JSON validation reports, e.g. of the same flow recorded with two firmware versions.
+Failures are matched by direction, action and error rather than by unique ID, as unique IDs differ between recordings.`,
+	Example: `  chargeflow validate -f firmware-1.2.txt -o old.json
+  chargeflow validate -f firmware-1.3.txt -o new.json
+  chargeflow report diff old.json new.json
+
+  # Fail the pipeline if new failures were introduced
+  chargeflow report diff old.json new.json --fail-on-new`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		previous, err := readReport(args[0])
+		if err != nil {
+			return err
+		}
+
+		current, err := readReport(args[1])
+		if err != nil {
+			return err
+		}
+
+		diff := report.Compare(previous, current)
+
+		if viper.GetBool("report_diff.json") {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			if err = encoder.Encode(diff); err != nil {
+				return errors.Wrap(err, "failed to write diff")
+			}
+		} else {
+			writeDiff(cmd.OutOrStdout(), diff)
+		}
+
+		if viper.GetBool("report_diff.fail-on-new") && len(diff.Introduced) > 0 {
+			return &ExitError{Code: ExitInvalid, err: errors.Errorf("%d new failures were introduced", len(diff.Introduced))}
+		}
+
+		return nil
+	},
+}
+
+// writeDiff writes a human-readable summary of the diff.
+func writeDiff(w io.Writer, diff report.Diff) {
+	if !diff.HasChanges() {
+		_, _ = fmt.Fprintln(w, "No failures were introduced or resolved.")
+		return
+	}
+
+	writeFailures := func(title, sign string, failures []report.Failure) {
+		if len(failures) == 0 {
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, "%s (%d):\n", title, len(failures))
+		for _, failure := range failures {
+			subject := failure.Kind
+			if failure.Action != "" {
+				subject = fmt.Sprintf("%s %s", failure.Action, failure.Kind)
+			}
+
+			_, _ = fmt.Fprintf(w, "  %s [%s] %s\n", sign, subject, failure.Error)
+			_, _ = fmt.Fprintf(w, "      %s\n", strings.Join(failure.Occurrences, ", "))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	writeFailures("Introduced failures", "+", diff.Introduced)
+	writeFailures("Resolved failures", "-", diff.Resolved)
+}
+
+func init() {
+	reportDiffCmd.Flags().Bool("json", false, "Write the diff as JSON")
+	reportDiffCmd.Flags().Bool("fail-on-new", false, "Exit with code 1 if any failures were introduced")
+
+	_ = viper.BindPFlag("report_diff.json", reportDiffCmd.Flags().Lookup("json"))
+	_ = viper.BindPFlag("report_diff.fail-on-new", reportDiffCmd.Flags().Lookup("fail-on-new"))
+}
@@ -27,36 +27,67 @@ var rootCmd = &cobra.Command{
 	Long:    ``,
 	Version: serviceVersion,
 	Run:     func(cmd *cobra.Command, args []string) {},
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		if viper.GetBool("debug") {
 			l, _ := zap.NewDevelopment()
 			zap.ReplaceGlobals(l)
 		}
+
+		// Validate the version upfront, so the commands can rely on configuredVersion
+		_, err := ocpp.ParseVersion(viper.GetString("ocpp.version"))
+		return err
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(validate)
 	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(consumeCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(rulesCmd)
 }
 
 // setDefaults sets the default values for the configuration.
 func setDefaults() {
 	viper.SetDefault("ocpp.version", ocpp.V16.String())
+	viper.SetDefault("ocpp.security-extension", true)
 	viper.SetDefault("debug", false)
 }
 
 func rootFlags() {
 	// Add flag for OCPP version
-	rootCmd.PersistentFlags().StringP("version", "v", ocpp.V16.String(), "OCPP version to use (1.6, 2.0.1 or 2.1)")
+	rootCmd.PersistentFlags().StringP("version", "v", ocpp.V16.String(), "OCPP version to use (1.5, 1.6, 2.0.1 or 2.1)")
+	rootCmd.PersistentFlags().Bool("security-extension", true, "Include the OCPP 1.6 Security Extension actions")
 	rootCmd.PersistentFlags().BoolP("debug", "d", false, "Enable debug mode")
 	rootCmd.PersistentFlags().StringVarP(&vendor, "vendor", "V", "", "Charging-station vendor for vendor/model-specific schema selection")
 	rootCmd.PersistentFlags().StringVarP(&model, "model", "m", "", "Charging-station model for vendor/model-specific schema selection")
 
+	rootCmd.PersistentFlags().StringSlice("enable-rule", nil, "IDs of optional semantic rules to check, see 'chargeflow rules'")
+	rootCmd.PersistentFlags().StringSlice("disable-rule", nil, "IDs of semantic rules to skip, see 'chargeflow rules'")
+	rootCmd.PersistentFlags().String("rules-file", "", "Path to a YAML file with user-defined CEL rules")
+	rootCmd.PersistentFlags().String("device-model", "", "Path to a YAML file with custom OCPP 2.0.1 components and variables")
+	rootCmd.PersistentFlags().StringSlice("configuration-keys", nil, "Paths to YAML files with vendor-specific OCPP 1.6 configuration keys")
+	rootCmd.PersistentFlags().StringSlice("certificates", nil, "Paths to PEM files with known certificates that certificate hash data is checked against")
+
 	_ = viper.BindPFlag("ocpp.version", rootCmd.PersistentFlags().Lookup("version"))
+	_ = viper.BindPFlag("ocpp.security-extension", rootCmd.PersistentFlags().Lookup("security-extension"))
 	_ = viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
 	_ = viper.BindPFlag("vendor", rootCmd.PersistentFlags().Lookup("vendor"))
 	_ = viper.BindPFlag("model", rootCmd.PersistentFlags().Lookup("model"))
+	_ = viper.BindPFlag("rules.enable", rootCmd.PersistentFlags().Lookup("enable-rule"))
+	_ = viper.BindPFlag("rules.disable", rootCmd.PersistentFlags().Lookup("disable-rule"))
+	_ = viper.BindPFlag("rules.file", rootCmd.PersistentFlags().Lookup("rules-file"))
+	_ = viper.BindPFlag("device-model.file", rootCmd.PersistentFlags().Lookup("device-model"))
+	_ = viper.BindPFlag("configuration-keys.files", rootCmd.PersistentFlags().Lookup("configuration-keys"))
+	_ = viper.BindPFlag("certificates.files", rootCmd.PersistentFlags().Lookup("certificates"))
+}
+
+// configuredVersion returns the OCPP version set with --version, which accepts aliases like "2.0.1" or "ocpp2.1".
+// The version is validated before any command runs.
+func configuredVersion() ocpp.Version {
+	version, _ := ocpp.ParseVersion(viper.GetString("ocpp.version"))
+	return version
 }
 
 func Execute(ctx context.Context) error {
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/certificates"
+	"github.com/ChargePi/chargeflow/pkg/configkeys"
+	"github.com/ChargePi/chargeflow/pkg/devicemodel"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List the semantic rules messages are checked against",
+	Long: `Lists the built-in semantic rules that are checked in addition to the OCPP schemas,
+followed by the user-defined rules of the --rules-file, if set.
+Rules can be disabled with --disable-rule, optional rules are enabled with --enable-rule.`,
+	Example: `  chargeflow rules
+  chargeflow rules --rules-file rules.yaml
+  chargeflow --version 1.6 validate -f messages.txt --disable-rule ocpp16.stack-level`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		custom, err := customRules()
+		if err != nil {
+			return err
+		}
+
+		table := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(table, "ID\tVERSIONS\tDEFAULT\tDESCRIPTION")
+
+		for _, rule := range slices.Concat(validator.BuiltinRules(), custom) {
+			versions := make([]string, 0, len(rule.Versions))
+			for _, version := range rule.Versions {
+				versions = append(versions, version.Name())
+			}
+
+			if len(versions) == 0 {
+				versions = append(versions, "all")
+			}
+
+			state := "enabled"
+			if rule.Optional {
+				state = "disabled"
+			}
+
+			_, _ = fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", rule.ID, strings.Join(versions, ", "), state, rule.Description)
+		}
+
+		return table.Flush()
+	},
+}
+
+// customRules returns the user-defined rules of the --rules-file, if set.
+func customRules() ([]validator.Rule, error) {
+	file := viper.GetString("rules.file")
+	if file == "" {
+		return nil, nil
+	}
+
+	return validator.LoadRulesFile(file)
+}
+
+// enabledRules returns the optional rules to enable: the rules set with --enable-rule, and the rules checking the
+// keys of the --configuration-keys and the components and variables of the --device-model.
+func enabledRules() []string {
+	enabled := viper.GetStringSlice("rules.enable")
+	if len(viper.GetStringSlice("configuration-keys.files")) > 0 {
+		enabled = append(enabled, "ocpp16.configuration-keys")
+	}
+
+	if viper.GetString("device-model.file") != "" {
+		enabled = append(enabled, "ocpp201.device-model-names")
+	}
+
+	return enabled
+}
+
+// validatorOptions returns the service option configuring the validator: adding the rules of the --rules-file,
+// enabling and disabling the rules set with --enable-rule and --disable-rule, the components and variables of the
+// --device-model, the keys of the --configuration-keys, the --certificates and the --security-extension.
+func validatorOptions() (validation.Option, error) {
+	enabled := enabledRules()
+	disabled := viper.GetStringSlice("rules.disable")
+
+	custom, err := customRules()
+	if err != nil {
+		return nil, err
+	}
+
+	deviceModel := devicemodel.Standard()
+	if file := viper.GetString("device-model.file"); file != "" {
+		if err := deviceModel.LoadFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	configurationKeys := configkeys.Standard()
+	for _, file := range viper.GetStringSlice("configuration-keys.files") {
+		if err := configurationKeys.LoadFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	pool := certificates.NewPool()
+	for _, file := range viper.GetStringSlice("certificates.files") {
+		if err := pool.LoadFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	known := slices.Concat(validator.BuiltinRules(), custom)
+	for _, id := range slices.Concat(enabled, disabled) {
+		if !slices.ContainsFunc(known, func(rule validator.Rule) bool { return rule.ID == id }) {
+			return nil, errors.Errorf("unknown rule %q, see 'chargeflow rules' for the available rules", id)
+		}
+	}
+
+	return validation.WithValidatorOptions(
+		validator.WithRules(custom...),
+		validator.WithEnabledRules(enabled...),
+		validator.WithDisabledRules(disabled...),
+		validator.WithSecurityExtension(viper.GetBool("ocpp.security-extension")),
+		validator.WithDeviceModel(deviceModel),
+		validator.WithConfigurationKeys(configurationKeys),
+		validator.WithCertificates(pool),
+	), nil
+}
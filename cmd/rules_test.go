@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validatorOptions(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("rules.enable", []string{})
+		viper.Set("rules.disable", []string{})
+	})
+
+	viper.Set("rules.enable", []string{"ocpp16.id-tag-expiry"})
+	viper.Set("rules.disable", []string{"ocpp16.stack-level"})
+	option, err := validatorOptions()
+	require.NoError(t, err)
+	assert.NotNil(t, option)
+
+	viper.Set("rules.disable", []string{"ocpp16.unknown"})
+	_, err = validatorOptions()
+	assert.ErrorContains(t, err, `unknown rule "ocpp16.unknown"`)
+}
+
+func Test_validatorOptions_RulesFile(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("rules.file", "")
+		viper.Set("rules.disable", []string{})
+	})
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("rules:\n  - name: acme.interval\n    expression: 'true'\n"), 0o600))
+
+	viper.Set("rules.file", path)
+	viper.Set("rules.disable", []string{"acme.interval"})
+	_, err := validatorOptions()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("rules:\n  - name: acme.interval\n"), 0o600))
+	_, err = validatorOptions()
+	assert.ErrorContains(t, err, "expression is required")
+}
+
+func Test_validatorOptions_DeviceModel(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("device-model.file", "")
+	})
+
+	path := filepath.Join(t.TempDir(), "device-model.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("components:\n  - name: AcmeCtrlr\n    variables:\n      - { name: Mode, dataType: string }\n"), 0o600))
+
+	assert.NotContains(t, enabledRules(), "ocpp201.device-model-names")
+
+	// Unknown components and variables are only reported when a device model is supplied
+	viper.Set("device-model.file", path)
+	assert.Contains(t, enabledRules(), "ocpp201.device-model-names")
+	_, err := validatorOptions()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("components:\n  - name: AcmeCtrlr\n    variables:\n      - { name: Mode, dataType: text }\n"), 0o600))
+	_, err = validatorOptions()
+	assert.ErrorContains(t, err, `invalid dataType "text"`)
+}
+
+func Test_validatorOptions_ConfigurationKeys(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("configuration-keys.files", []string{})
+	})
+
+	path := filepath.Join(t.TempDir(), "acme.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("vendor: ACME\nkeys:\n  - { name: AcmeLedMode, type: string }\n"), 0o600))
+
+	assert.NotContains(t, enabledRules(), "ocpp16.configuration-keys")
+
+	// Unknown keys are only reported when a catalog is supplied
+	viper.Set("configuration-keys.files", []string{path})
+	assert.Contains(t, enabledRules(), "ocpp16.configuration-keys")
+	_, err := validatorOptions()
+	require.NoError(t, err)
+
+	viper.Set("configuration-keys.files", []string{path, filepath.Join(t.TempDir(), "missing.yaml")})
+	_, err = validatorOptions()
+	assert.ErrorContains(t, err, "failed to read configuration key catalog")
+}
+
+func Test_validatorOptions_Certificates(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("certificates.files", []string{})
+	})
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ACME Root CA"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "root.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	viper.Set("certificates.files", []string{path})
+	_, err = validatorOptions()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+	_, err = validatorOptions()
+	assert.ErrorContains(t, err, "failed to parse certificates")
+}
@@ -19,10 +19,16 @@ type registerConfig struct {
 	SchemaFile string
 	SchemaDir  string
 	Action     string
+	VendorId   string
+	MessageId  string
 	Vendor     string
 	Model      string
 }
 
+// dataTransferDir is the directory of a schema directory holding the schemas of DataTransfer data, in a
+// subdirectory per vendorId, e.g. "DataTransfer/com.acme/SetLevelRequest.json".
+const dataTransferDir = "DataTransfer"
+
 var registerCfg registerConfig
 
 var register = &cobra.Command{
@@ -30,10 +36,15 @@ var register = &cobra.Command{
 	Short: "Register schemas on a remote schema registry",
 	Long: `Register OCPP schemas on a remote schema registry.
 You can register a single schema file or all schemas from a directory.
-The schema file names should match the OCPP action names (e.g., "BootNotificationRequest.json" or "BootNotificationResponse.json").`,
+The schema file names should match the OCPP action names (e.g., "BootNotificationRequest.json" or "BootNotificationResponse.json").
+Schemas of DataTransfer data are registered with --vendor-id and --message-id, or from the DataTransfer directory
+of a schema directory (e.g., "DataTransfer/com.acme/SetLevelRequest.json").`,
 	Example: `  # Register a single schema file
   chargeflow schema --url http://localhost:8081 --version 1.6 register --file BootNotificationRequest.json --action BootNotificationRequest
 
+  # Register a schema of the data of DataTransfer requests of a vendor
+  chargeflow schema --url http://localhost:8081 --version 1.6 register --file SetLevel.json --action DataTransferRequest --vendor-id com.acme --message-id SetLevel
+
   # Register all schemas from a directory
   chargeflow schema --url http://localhost:8081 --version 1.6 register --dir ./schemas
 
@@ -59,6 +70,10 @@ The schema file names should match the OCPP action names (e.g., "BootNotificatio
 			return errors.New("--action is required when using --file")
 		}
 
+		if cfg.SchemaDir != "" && (cfg.VendorId != "" || cfg.MessageId != "") {
+			return errors.New("--vendor-id and --message-id can only be used with --file")
+		}
+
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -66,7 +81,7 @@ The schema file names should match the OCPP action names (e.g., "BootNotificatio
 
 		cfg := loadRegisterConfig()
 		octx := ocpp.OcppContext{
-			Version: ocpp.Version(viper.GetString("ocpp.version")),
+			Version: configuredVersion(),
 			Vendor:  cfg.Vendor,
 			Model:   cfg.Model,
 		}
@@ -80,7 +95,12 @@ The schema file names should match the OCPP action names (e.g., "BootNotificatio
 
 		switch {
 		case cfg.SchemaFile != "":
-			return registerSingleSchema(ctx, logger, remoteRegistry, octx, cfg.SchemaFile, cfg.Action)
+			return registerSingleSchema(ctx, logger, remoteRegistry, schema_registry.CreateSchemaRequest{
+				OcppContext: octx,
+				Action:      cfg.Action,
+				VendorId:    cfg.VendorId,
+				MessageId:   cfg.MessageId,
+			}, cfg.SchemaFile)
 		default:
 			return registerSchemasFromDir(ctx, logger, remoteRegistry, octx, cfg.SchemaDir)
 		}
@@ -91,15 +111,17 @@ func registerSingleSchema(
 	ctx context.Context,
 	logger *zap.Logger,
 	registry schema_registry.SchemaRegistry,
-	octx ocpp.OcppContext,
-	filePath, action string,
+	req schema_registry.CreateSchemaRequest,
+	filePath string,
 ) error {
 	logger = logger.With(
 		zap.String("file", filePath),
-		zap.String("action", action),
-		zap.String("vendor", octx.Vendor),
-		zap.String("model", octx.Model),
-		zap.String("version", octx.Version.String()),
+		zap.String("action", req.Action),
+		zap.String("vendorId", req.VendorId),
+		zap.String("messageId", req.MessageId),
+		zap.String("vendor", req.OcppContext.Vendor),
+		zap.String("model", req.OcppContext.Model),
+		zap.String("version", req.OcppContext.Version.Name()),
 	)
 
 	logger.Info("Registering schema")
@@ -109,12 +131,9 @@ func registerSingleSchema(
 		return errors.Wrapf(err, "failed to read schema file: %s", filePath)
 	}
 
-	if err := registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{
-		OcppContext: octx,
-		Action:      action,
-		Schema:      schemaData,
-	}); err != nil {
-		return errors.Wrapf(err, "failed to register schema for action %s", action)
+	req.Schema = schemaData
+	if err := registry.RegisterSchema(ctx, req); err != nil {
+		return errors.Wrapf(err, "failed to register schema for action %s", req.Action)
 	}
 
 	logger.Info("Successfully registered schema")
@@ -132,7 +151,7 @@ func registerSchemasFromDir(
 		zap.String("directory", dir),
 		zap.String("vendor", octx.Vendor),
 		zap.String("model", octx.Model),
-		zap.String("version", octx.Version.String()),
+		zap.String("version", octx.Version.Name()),
 	)
 
 	logger.Info("Registering schemas from directory")
@@ -147,6 +166,11 @@ func registerSchemasFromDir(
 
 	for _, entry := range entries {
 		if entry.IsDir() {
+			if entry.Name() == dataTransferDir {
+				successful, failed := registerDataTransferSchemas(ctx, logger, registry, octx, filepath.Join(dir, entry.Name()))
+				successCount += successful
+				errorCount += failed
+			}
 			continue
 		}
 
@@ -198,11 +222,73 @@ func registerSchemasFromDir(
 	return nil
 }
 
+// registerDataTransferSchemas registers the schemas of DataTransfer data from a directory with a subdirectory per
+// vendorId. The file names are the messageId suffixed with the direction, e.g. "SetLevelRequest.json". The schemas
+// named "Request.json" and "Response.json" apply to the DataTransfers of the vendor without a more specific schema.
+// It returns the number of schemas that were registered and that failed.
+func registerDataTransferSchemas(
+	ctx context.Context,
+	logger *zap.Logger,
+	registry schema_registry.SchemaRegistry,
+	octx ocpp.OcppContext,
+	dir string,
+) (int, int) {
+	vendors, err := os.ReadDir(dir)
+	if err != nil {
+		logger.Error("Failed to read DataTransfer directory", zap.String("directory", dir), zap.Error(err))
+		return 0, 1
+	}
+
+	successCount, errorCount := 0, 0
+	for _, vendor := range vendors {
+		if !vendor.IsDir() {
+			continue
+		}
+
+		vendorDir := filepath.Join(dir, vendor.Name())
+		files, err := os.ReadDir(vendorDir)
+		if err != nil {
+			logger.Error("Failed to read DataTransfer directory", zap.String("directory", vendorDir), zap.Error(err))
+			errorCount++
+			continue
+		}
+
+		for _, file := range files {
+			name, isJson := strings.CutSuffix(file.Name(), ".json")
+			if file.IsDir() || !isJson {
+				continue
+			}
+
+			req := schema_registry.CreateSchemaRequest{OcppContext: octx, VendorId: vendor.Name()}
+			if messageId, found := strings.CutSuffix(name, "Request"); found {
+				req.Action, req.MessageId = schema_registry.DataTransferRequest, messageId
+			} else if messageId, found := strings.CutSuffix(name, "Response"); found {
+				req.Action, req.MessageId = schema_registry.DataTransferResponse, messageId
+			} else {
+				logger.Debug("Skipping DataTransfer schema without a Request or Response suffix", zap.String("file", file.Name()))
+				continue
+			}
+
+			if err := registerSingleSchema(ctx, logger, registry, req, filepath.Join(vendorDir, file.Name())); err != nil {
+				logger.Error("Failed to register schema", zap.Error(err))
+				errorCount++
+				continue
+			}
+
+			successCount++
+		}
+	}
+
+	return successCount, errorCount
+}
+
 func loadRegisterConfig() registerConfig {
 	return registerConfig{
 		SchemaFile: viper.GetString("schema.register.file"),
 		SchemaDir:  viper.GetString("schema.register.dir"),
 		Action:     viper.GetString("schema.register.action"),
+		VendorId:   viper.GetString("schema.register.vendor-id"),
+		MessageId:  viper.GetString("schema.register.message-id"),
 		Vendor:     viper.GetString("vendor"),
 		Model:      viper.GetString("model"),
 	}
@@ -213,7 +299,12 @@ func init() {
 	register.Flags().StringVar(&registerCfg.SchemaDir, "dir", "", "Path to a directory containing schema files to register")
 	register.Flags().StringVarP(&registerCfg.Action, "action", "a", "", "OCPP action name (required when using --file, e.g., 'BootNotificationRequest')")
 
+	register.Flags().StringVar(&registerCfg.VendorId, "vendor-id", "", "vendorId of the DataTransfer messages whose data the schema describes")
+	register.Flags().StringVar(&registerCfg.MessageId, "message-id", "", "messageId of the DataTransfer messages whose data the schema describes")
+
 	_ = viper.BindPFlag("schema.register.file", register.Flags().Lookup("file"))
 	_ = viper.BindPFlag("schema.register.dir", register.Flags().Lookup("dir"))
 	_ = viper.BindPFlag("schema.register.action", register.Flags().Lookup("action"))
+	_ = viper.BindPFlag("schema.register.vendor-id", register.Flags().Lookup("vendor-id"))
+	_ = viper.BindPFlag("schema.register.message-id", register.Flags().Lookup("message-id"))
 }
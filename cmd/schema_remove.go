@@ -72,7 +72,7 @@ You can remove a single schema by action name, derive the action from a file nam
 
 		cfg := loadRemoveConfig()
 		octx := ocpp.OcppContext{
-			Version: ocpp.Version(viper.GetString("ocpp.version")),
+			Version: configuredVersion(),
 			Vendor:  vendor,
 			Model:   model,
 		}
@@ -107,7 +107,7 @@ func removeSingleSchema(
 		zap.String("action", action),
 		zap.String("vendor", octx.Vendor),
 		zap.String("model", octx.Model),
-		zap.String("version", octx.Version.String()),
+		zap.String("version", octx.Version.Name()),
 	)
 	logger.Info("Removing schema")
 
@@ -133,7 +133,7 @@ func removeSchemasFromDir(
 		zap.String("directory", dir),
 		zap.String("vendor", octx.Vendor),
 		zap.String("model", octx.Model),
-		zap.String("version", octx.Version.String()),
+		zap.String("version", octx.Version.Name()),
 	)
 	logger.Info("Removing schemas matching directory")
 
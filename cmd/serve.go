@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ChargePi/chargeflow/internal/grpc_server"
+	"github.com/ChargePi/chargeflow/internal/server"
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schemas"
+)
+
+// servedVersions lists the OCPP versions whose bundled schemas are loaded by long-running modes,
+// so that a single process can validate traffic for any of them.
+var servedVersions = schemas.Versions()
+
+var serveSchemasFolder = ""
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP (and optionally gRPC) API for validating OCPP messages",
+	Long: `Run an HTTP API exposing the validation service, and optionally the chargeflow.v1.ChargeflowValidator gRPC service.
+All embedded OCPP schemas are compiled once on startup, so the server can validate messages for any supported OCPP version.`,
+	Example: `  # Start the server on the default address
+  chargeflow serve
+
+  # Start both the HTTP and the gRPC API
+  chargeflow serve --addr :8080 --grpc-addr :9090
+
+  # Start the server with additional vendor-specific schemas
+  chargeflow --version 1.6 --vendor Acme --model FastCharger serve --addr :8081 --schemas ./vendor-schemas`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := zap.L()
+		ctx := cmd.Context()
+
+		overwrite := serveSchemasFolder != ""
+
+		serveRegistry, err := buildValidationRegistry(logger, overwrite)
+		if err != nil {
+			return err
+		}
+
+		for _, version := range servedVersions {
+			if err := registerVersionSchemas(ctx, logger, version, serveRegistry); err != nil {
+				return err
+			}
+		}
+
+		if overwrite {
+			octx := ocpp.OcppContext{
+				Version: configuredVersion(),
+				Vendor:  vendor,
+				Model:   model,
+			}
+			if err := registerSchemasFromDir(ctx, logger, serveRegistry, octx, serveSchemasFolder); err != nil {
+				return err
+			}
+		}
+
+		serverOpts := []server.Option{
+			server.WithAddress(viper.GetString("serve.addr")),
+			server.WithMaxBodySize(viper.GetInt64("serve.max-body-size")),
+			server.WithReadTimeout(viper.GetDuration("serve.read-timeout")),
+		}
+
+		validatorOpts, err := validatorOptions()
+		if err != nil {
+			return err
+		}
+
+		serviceOpts := []validation.Option{validatorOpts}
+		if viper.GetBool("serve.metrics") {
+			m := newMetrics(serveRegistry)
+			serviceOpts = append(serviceOpts, validation.WithObserver(m))
+			serverOpts = append(serverOpts, server.WithMetricsHandler(m.Handler()))
+		}
+
+		service := validation.NewService(logger, serveRegistry, serviceOpts...)
+		httpServer := server.NewServer(logger, service, serveRegistry, serverOpts...)
+
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.Go(func() error {
+			return httpServer.Run(groupCtx)
+		})
+
+		if grpcAddr := viper.GetString("serve.grpc-addr"); grpcAddr != "" {
+			grpcServer := grpc_server.NewServer(
+				logger,
+				service,
+				grpc_server.WithAddress(grpcAddr),
+				grpc_server.WithMaxMessageSize(int(viper.GetInt64("serve.max-body-size"))),
+			)
+			group.Go(func() error {
+				return grpcServer.Run(groupCtx)
+			})
+		}
+
+		return group.Wait()
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", ":8080", "Address to listen on")
+	serveCmd.Flags().String("grpc-addr", "", "Address to listen on for the gRPC API (e.g. ':9090'). The gRPC API is disabled when empty")
+	serveCmd.Flags().Int64("max-body-size", 10<<20, "Maximum request body size in bytes")
+	serveCmd.Flags().Duration("read-timeout", 30*time.Second, "Maximum duration for reading a request")
+	serveCmd.Flags().StringVarP(&serveSchemasFolder, "schemas", "a", "", "Path to additional OCPP schemas folder")
+	serveCmd.Flags().Bool("metrics", true, "Expose Prometheus metrics on /metrics")
+
+	_ = viper.BindPFlag("serve.addr", serveCmd.Flags().Lookup("addr"))
+	_ = viper.BindPFlag("serve.grpc-addr", serveCmd.Flags().Lookup("grpc-addr"))
+	_ = viper.BindPFlag("serve.max-body-size", serveCmd.Flags().Lookup("max-body-size"))
+	_ = viper.BindPFlag("serve.read-timeout", serveCmd.Flags().Lookup("read-timeout"))
+	_ = viper.BindPFlag("serve.metrics", serveCmd.Flags().Lookup("metrics"))
+}
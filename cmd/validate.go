@@ -2,163 +2,95 @@ package cmd
 
 import (
 	"context"
-	"embed"
-	"path/filepath"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/term"
 
 	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
 	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/remote_registry"
 
+	"github.com/ChargePi/chargeflow/internal/tail"
 	"github.com/ChargePi/chargeflow/internal/validation"
 	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/report"
 	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/schemas"
 )
 
-var (
-	registry schema_registry.SchemaRegistry
+var registry schema_registry.SchemaRegistry
 
-	// OCPP 1.6 schemas
-	//
-	//go:embed schemas/ocpp_16/*
-	ocpp16Schemas embed.FS
+var additionalOcppSchemasFolder = ""
 
-	// OCPP 1.6 Security Extension schemas
-	//
-	//go:embed schemas/ocpp_16_security/*
-	ocpp16Security embed.FS
-
-	//go:embed schemas/ocpp_201/*
-	ocpp201Schemas embed.FS
-
-	//go:embed schemas/ocpp_21/*
-	ocpp21Schemas embed.FS
-)
-
-var (
-	additionalOcppSchemasFolder = ""
-
-	// supportedOutputFormats lists allowed output file formats for the CLI report writer.
-	supportedOutputFormats = map[string]bool{".json": true, ".csv": true, ".txt": true}
-)
-
-const dirPrefix = "schemas/ocpp_"
-
-// registerSchemas registers all schemas from the embedded filesystem for a specific OCPP version.
-func registerSchemas(
+// registerVersionSchemas registers all bundled schemas belonging to a specific OCPP version.
+// Unknown versions are ignored, leaving the registry untouched.
+func registerVersionSchemas(
 	ctx context.Context,
 	logger *zap.Logger,
-	embeddedDir embed.FS,
 	version ocpp.Version,
 	registry schema_registry.SchemaRegistry,
 ) error {
-	logger.Debug("Registering OCPP schemas", zap.String("version", version.String()))
-
-	dirPath := dirPrefix + strings.ReplaceAll(version.String(), ".", "")
-
-	// Exception for OCPP 1.6 Security Extension schemas
-	if embeddedDir == ocpp16Security {
-		dirPath = dirPrefix + strings.ReplaceAll(version.String(), ".", "") + "_security"
-	}
-
-	dir, err := embeddedDir.ReadDir(dirPath)
-	if err != nil {
-		return errors.Wrapf(err, "unable to read OCPP schemas directory for version: %s", version.String())
-	}
-
-	for _, file := range dir {
-		if !file.IsDir() {
-			name := file.Name()
-			logger.Debug("Registering OCPP schema file", zap.String("file", name))
-
-			// Open and read the schema file
-			schemaData, err := embeddedDir.ReadFile(filepath.Join(dirPath, name))
-			if err != nil {
-				return errors.Wrapf(err, "unable to read OCPP schema file: %s", name)
-			}
-
-			// Note: Assuming that the file name is equivalent to the action name
-			// Improvement: Could extract the action name.
-			// Also could determine the OCPP version from the schema ID.
+	logger.Debug("Registering OCPP schemas", zap.String("version", version.Name()))
+	return schemas.Register(ctx, registry, version, schemas.WithSecurityExtension(viper.GetBool("ocpp.security-extension")))
+}
 
-			action, _ := strings.CutSuffix(name, ".json")
-			err = registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{
-				OcppContext: ocpp.OcppContext{Version: version},
-				Action:      action,
-				Schema:      schemaData,
-			})
-			if err != nil {
-				return errors.Wrapf(err, "unable to register OCPP schema: %s", name)
-			}
-		}
+// buildValidationRegistry creates the schema registry used for validation, based on the
+// configured registry type.
+func buildValidationRegistry(logger *zap.Logger, overwrite bool) (schema_registry.SchemaRegistry, error) {
+	switch viper.GetString("schema.registry.type") {
+	case "remote":
+		url := viper.GetString("schema.registry.url")
+		return remote_registry.NewRemoteSchemaRegistry(url, logger)
+	default:
+		return file_registry.NewFileSchemaRegistry(
+			logger,
+			file_registry.WithOverwrite(overwrite),
+		), nil
 	}
-
-	return nil
 }
 
 var validate = &cobra.Command{
-	Use:          "validate",
-	Short:        "Validate the OCPP message(s) against the registered OCPP schemas",
-	Long:         `Validate the OCPP message(s) against the registered OCPP schema(s).`,
-	Example:      "chargeflow --version 1.6 validate '[2, \"123456\", \"BootNotification\", {\"chargePointVendor\": \"TestVendor\", \"chargePointModel\": \"TestModel\"}]'",
+	Use:   "validate",
+	Short: "Validate the OCPP message(s) against the registered OCPP schemas",
+	Long:  `Validate the OCPP message(s) against the registered OCPP schema(s).`,
+	Example: `  chargeflow --version 1.6 validate '[2, "123456", "BootNotification", {"chargePointVendor": "TestVendor", "chargePointModel": "TestModel"}]'
+
+  # Show colorized results grouped per message
+  chargeflow --version 1.6 validate -f csms.log --output-format pretty
+
+  # Follow a live CSMS log, validating messages as they are appended
+  chargeflow --version 1.6 validate -f csms.log --follow`,
 	Args:         cobra.RangeArgs(0, 1),
 	SilenceUsage: true,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		ocppVersion := viper.GetString("ocpp.version")
 		logger := zap.L()
-		registryType := viper.GetString("schema.registry.type")
 
 		overwrite := additionalOcppSchemasFolder != ""
 
 		var err error
-		switch registryType {
-		case "remote":
-			url := viper.GetString("schema.registry.url")
-			registry, err = remote_registry.NewRemoteSchemaRegistry(url, logger)
-			if err != nil {
-				return err
-			}
-		default:
-			registry = file_registry.NewFileSchemaRegistry(
-				logger,
-				file_registry.WithOverwrite(overwrite),
-			)
+		registry, err = buildValidationRegistry(logger, overwrite)
+		if err != nil {
+			return err
 		}
 
 		// Populate the schema registry with OCPP schemas
 		ctx := cmd.Context()
 
-		switch ocppVersion {
-		case ocpp.V16.String():
-			err = registerSchemas(ctx, logger, ocpp16Schemas, ocpp.V16, registry)
-			if err != nil {
-				return err
-			}
-
-			err = registerSchemas(ctx, logger, ocpp16Security, ocpp.V16, registry)
-			if err != nil {
-				return err
-			}
-		case ocpp.V20.String():
-			err = registerSchemas(ctx, logger, ocpp201Schemas, ocpp.V20, registry)
-			if err != nil {
-				return err
-			}
-		case ocpp.V21.String():
-			err = registerSchemas(ctx, logger, ocpp21Schemas, ocpp.V21, registry)
-			if err != nil {
-				return err
-			}
+		err = registerVersionSchemas(ctx, logger, configuredVersion(), registry)
+		if err != nil {
+			return err
 		}
 
 		if overwrite {
-			ocppVersion := viper.GetString("ocpp.version")
-			version := ocpp.Version(ocppVersion)
-			err = registerSchemasFromDir(ctx, logger, registry, ocpp.OcppContext{Version: version, Vendor: vendor, Model: model}, additionalOcppSchemasFolder)
+			err = registerSchemasFromDir(ctx, logger, registry, ocpp.OcppContext{Version: configuredVersion(), Vendor: vendor, Model: model}, additionalOcppSchemasFolder)
 			if err != nil {
 				return err
 			}
@@ -167,38 +99,86 @@ var validate = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ocppVersion := viper.GetString("ocpp.version")
 		file := viper.GetString("file")
 		output := viper.GetString("output")
 
 		logger := zap.L()
 		logger = logger.WithOptions(zap.WithCaller(false), zap.AddStacktrace(zap.FatalLevel))
 
-		service := validation.NewService(logger, registry)
-
 		var message string
 		if len(args) > 0 {
 			message = args[0]
 		}
 
+		console, err := loadConsoleMode()
+		if err != nil {
+			return err
+		}
+
+		validatorOpts, err := validatorOptions()
+		if err != nil {
+			return err
+		}
+
+		if viper.GetBool("follow") {
+			if file == "" || message != "" || output != "" {
+				return errors.New("--follow requires the --file flag and cannot be combined with a message argument or --output")
+			}
+
+			if console != validation.ConsoleLogs {
+				return errors.New("--follow only supports the logs output format")
+			}
+
+			return followFile(cmd.Context(), logger, file, validatorOpts)
+		}
+
+		// In the other console modes, the results are the output, so they're not logged as well.
+		// Failures of the tool itself are still returned as errors.
+		if console != validation.ConsoleLogs && !viper.GetBool("debug") {
+			logger = zap.NewNop()
+		}
+
+		if console == validation.ConsoleQuiet {
+			cmd.SilenceErrors = true
+		}
+
+		service := validation.NewService(
+			logger,
+			registry,
+			validation.WithStdout(cmd.OutOrStdout()),
+			validation.WithColor(useColor()),
+			validatorOpts,
+		)
+
 		if file == "" && message == "" {
 			return errors.New("no message provided to validate, please provide a message as a command line argument or use the --file flag to read from a file")
 		}
 
+		thresholds, err := loadThresholds()
+		if err != nil {
+			return err
+		}
+
+		format := viper.GetString("format")
+		if format != "" && output == "" {
+			return errors.New("--format requires the --output flag")
+		}
+
 		if output != "" {
-			ext := strings.ToLower(filepath.Ext(output))
-			if !supportedOutputFormats[ext] {
-				return errors.Errorf("unsupported output format '%s', supported: .json, .csv, .txt", ext)
+			if _, err := validation.ResolveFormat(output, format); err != nil {
+				return errors.Errorf("%s, supported formats: %s", err, strings.Join(validation.Formats, ", "))
 			}
 		}
 
 		req := validation.Request{
 			OcppContext: ocpp.OcppContext{
-				Version: ocpp.Version(ocppVersion),
+				Version: configuredVersion(),
 				Vendor:  vendor,
 				Model:   model,
 			},
-			Output: output,
+			Output:  output,
+			Format:  format,
+			Console: console,
 		}
 
 		if message != "" {
@@ -207,18 +187,149 @@ var validate = &cobra.Command{
 			req.File = file
 		}
 
-		_, err := service.Validate(req)
-		return err
+		validationReport, err := service.Validate(req)
+		if err != nil {
+			return err
+		}
+
+		outcome, reasons := thresholds.Evaluate(validationReport)
+		if outcome == report.OutcomePassed {
+			return nil
+		}
+
+		return &ExitError{
+			Code: exitCodeForOutcome(outcome),
+			err:  errors.Errorf("validation failed: %s", strings.Join(reasons, "; ")),
+		}
 	},
 }
 
+// loadThresholds loads the thresholds the validation report is evaluated against.
+func loadThresholds() (report.Thresholds, error) {
+	thresholds := report.Thresholds{
+		MaxInvalidPercent: viper.GetFloat64("max-invalid-percent"),
+		FailOnActions:     viper.GetStringSlice("fail-on-action"),
+	}
+
+	if thresholds.MaxInvalidPercent < 0 || thresholds.MaxInvalidPercent > 100 {
+		return thresholds, errors.New("--max-invalid-percent must be between 0 and 100")
+	}
+
+	for _, failOn := range viper.GetStringSlice("fail-on") {
+		switch failOn {
+		case "invalid":
+			thresholds.FailOnInvalid = true
+		case "unparsable":
+			thresholds.FailOnUnparsable = true
+		case "none":
+		default:
+			return thresholds, errors.Errorf("unknown --fail-on value %q, supported values: invalid, unparsable, none", failOn)
+		}
+	}
+
+	return thresholds, nil
+}
+
+// loadConsoleMode returns how the validation results are written to the console. The --quiet and --json
+// flags are shorthands for the respective output formats.
+func loadConsoleMode() (string, error) {
+	quiet := viper.GetBool("quiet")
+	asJson := viper.GetBool("json")
+
+	switch {
+	case quiet && asJson:
+		return "", errors.New("--quiet and --json cannot be combined")
+	case quiet:
+		return validation.ConsoleQuiet, nil
+	case asJson:
+		return validation.ConsoleJSON, nil
+	}
+
+	mode := viper.GetString("output-format")
+	if !slices.Contains(validation.ConsoleModes, mode) {
+		return "", errors.Errorf("unknown --output-format value %q, supported values: %s", mode, strings.Join(validation.ConsoleModes, ", "))
+	}
+
+	return mode, nil
+}
+
+// useColor reports whether the results should be colorized: only when writing to a terminal and unless
+// disabled with the NO_COLOR environment variable (https://no-color.org).
+func useColor() bool {
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return false
+	}
+
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// followFile validates the messages appended to the file until the command is interrupted.
+func followFile(ctx context.Context, logger *zap.Logger, file string, validatorOpts validation.Option) error {
+	octx := ocpp.OcppContext{
+		Version: configuredVersion(),
+		Vendor:  vendor,
+		Model:   model,
+	}
+
+	tailOpts := []tail.Option{
+		tail.WithFromEnd(viper.GetBool("follow-from-end")),
+		tail.WithPollInterval(viper.GetDuration("follow-poll-interval")),
+	}
+
+	metricsAddr := viper.GetString("follow-metrics-addr")
+	if metricsAddr == "" {
+		return validation.NewService(logger, registry, validatorOpts).Follow(ctx, octx, file, tailOpts...)
+	}
+
+	m := newMetrics(registry)
+	service := validation.NewService(logger, registry, validatorOpts, validation.WithObserver(m))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		return serveMetrics(groupCtx, logger, metricsAddr, m)
+	})
+	group.Go(func() error {
+		return service.Follow(groupCtx, octx, file, tailOpts...)
+	})
+
+	return group.Wait()
+}
+
 func init() {
 	validate.Flags().StringVarP(&additionalOcppSchemasFolder, "schemas", "a", "", "Path to additional OCPP schemas folder")
 	validate.Flags().StringP("response-type", "r", "", "Response type to validate against (e.g. 'BootNotificationResponse'). Currently needed if you want to validate a single response message. ")
 	validate.Flags().StringP("file", "f", "", "Path to a file containing the OCPP message to validate. If this flag is set, the message will be read from the file instead of the command line argument.")
-	validate.Flags().StringP("output", "o", "", "Path to write validation report. Supports .json, .csv and .txt extensions.")
+	validate.Flags().StringP("output", "o", "", "Path to write validation report. Supports .json, .csv, .txt, .xml (JUnit), .sarif, .html and .md extensions.")
+	validate.Flags().String("format", "", "Report format, overriding the output extension: json, csv, txt, junit, sarif, html or markdown")
+	validate.Flags().String("output-format", validation.ConsoleLogs, "How the results are written to the console: logs, pretty, json or quiet")
+	validate.Flags().BoolP("quiet", "q", false, "Don't write the results to the console, only exit with the exit code (same as --output-format quiet)")
+	validate.Flags().Bool("json", false, "Write the JSON report to stdout (same as --output-format json)")
+	validate.Flags().StringSlice("fail-on", []string{"invalid", "unparsable"}, "Which failures fail the validation: invalid, unparsable or none")
+	validate.Flags().Float64("max-invalid-percent", 0, "Percentage of invalid requests and responses that is tolerated before the validation fails")
+	validate.Flags().StringSlice("fail-on-action", nil, "Actions that must always be valid, e.g. 'BootNotification'. Any invalid message of these actions fails the validation")
+	validate.Flags().BoolP("follow", "F", false, "Follow the file like 'tail -F', validating messages as they are appended (alias: --watch)")
+	validate.Flags().Bool("from-end", false, "When following, skip the existing content of the file")
+	validate.Flags().Duration("poll-interval", 250*time.Millisecond, "When following, how often to check the file for changes")
+	validate.Flags().String("metrics-addr", "", "When following, address to serve Prometheus metrics on (e.g. ':9100'). Disabled when empty")
+	validate.Flags().SetNormalizeFunc(func(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "watch" {
+			name = "follow"
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	_ = viper.BindPFlag("response-type", validate.Flags().Lookup("response-type"))
 	_ = viper.BindPFlag("file", validate.Flags().Lookup("file"))
 	_ = viper.BindPFlag("output", validate.Flags().Lookup("output"))
+	_ = viper.BindPFlag("format", validate.Flags().Lookup("format"))
+	_ = viper.BindPFlag("output-format", validate.Flags().Lookup("output-format"))
+	_ = viper.BindPFlag("quiet", validate.Flags().Lookup("quiet"))
+	_ = viper.BindPFlag("json", validate.Flags().Lookup("json"))
+	_ = viper.BindPFlag("fail-on", validate.Flags().Lookup("fail-on"))
+	_ = viper.BindPFlag("max-invalid-percent", validate.Flags().Lookup("max-invalid-percent"))
+	_ = viper.BindPFlag("fail-on-action", validate.Flags().Lookup("fail-on-action"))
+	_ = viper.BindPFlag("follow", validate.Flags().Lookup("follow"))
+	_ = viper.BindPFlag("follow-from-end", validate.Flags().Lookup("from-end"))
+	_ = viper.BindPFlag("follow-poll-interval", validate.Flags().Lookup("poll-interval"))
+	_ = viper.BindPFlag("follow-metrics-addr", validate.Flags().Lookup("metrics-addr"))
 }
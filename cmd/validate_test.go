@@ -6,23 +6,27 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
 	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ChargePi/chargeflow/internal/validation"
 	"github.com/ChargePi/chargeflow/pkg/ocpp"
 )
 
 var (
 	validOcppRequest     = "[2, \"1234567890\", \"Authorize\", {\"idTag\": \"1234567890\"}]"
 	validOcppResponse    = "[3, \"1234567890\", {\"idTagInfo\": {\"status\": \"Accepted\"}}]"
-	validOcpp201Request  = "[2, \"1234567890\", \"Authorize\", {\"idTag\": \"1234567890\"}]"
-	validOcpp201Response = "[3, \"1234567890\", {\"idTagInfo\": {\"status\": \"Accepted\"}}]"
+	validOcpp201Request  = "[2, \"1234567890\", \"Authorize\", {\"idToken\": {\"idToken\": \"1234567890\", \"type\": \"ISO14443\"}}]"
+	validOcpp201Response = "[3, \"1234567890\", {\"idTokenInfo\": {\"status\": \"Accepted\"}}]"
 	validOcpp21Request   = "[2, \"1234567890\", \"UsePriorityCharging\", {\"transactionId\": \"12345\", \"activate\": true}]"
 	validOcpp21Response  = "[3, \"1234567890\", {\"status\": \"NoProfile\"}]"
 )
@@ -68,6 +72,9 @@ func Test_registerAdditionalSchemas(t *testing.T) {
 		},
 	}
 
+	// Don't override the --version flag of other tests
+	t.Cleanup(func() { viper.Set("ocpp.version", nil) })
+
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			viper.Set("ocpp.version", test.defaultOcppVersion)
@@ -92,10 +99,43 @@ func Test_registerAdditionalSchemas(t *testing.T) {
 	}
 }
 
+func Test_registerDataTransferSchemas(t *testing.T) {
+	r := require.New(t)
+	fileRegistry := file_registry.NewFileSchemaRegistry(zap.L())
+
+	tempDir := t.TempDir()
+	vendorDir := filepath.Join(tempDir, dataTransferDir, "com.acme")
+	r.NoError(os.MkdirAll(vendorDir, 0o755))
+	r.NoError(os.WriteFile(filepath.Join(vendorDir, "SetLevelRequest.json"), []byte(`{"type": "object"}`), 0o600))
+	r.NoError(os.WriteFile(filepath.Join(vendorDir, "Response.json"), []byte(`{"type": "object"}`), 0o600))
+	r.NoError(os.WriteFile(filepath.Join(vendorDir, "notes.json"), []byte(`{"type": "object"}`), 0o600))
+
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+	r.NoError(registerSchemasFromDir(context.Background(), zap.L(), fileRegistry, octx, tempDir))
+
+	_, found := fileRegistry.GetSchema(context.Background(), schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel"})
+	assert.True(t, found)
+	_, found = fileRegistry.GetSchema(context.Background(), schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferResponse", VendorId: "com.acme"})
+	assert.True(t, found)
+
+	actions, err := fileRegistry.ListSchemas(context.Background(), schema_registry.ListSchemasRequest{OcppContext: octx})
+	r.NoError(err)
+	assert.Equal(t, []string{"DataTransferRequest:com.acme:SetLevel", "DataTransferResponse:com.acme"}, actions)
+}
+
 func Test_Validate(t *testing.T) {
 	l, _ := zap.NewProduction()
 	zap.ReplaceGlobals(l)
 
+	// Set up the root command like Execute does
+	setDefaults()
+	rootFlags()
+
+	// Files usually end with a newline, which must not be counted as an unparsable message
+	trailingNewlineFile := filepath.Join(t.TempDir(), "traffic.log")
+	content := "[2,\"1\",\"Heartbeat\",{}]\n[3,\"1\",{\"currentTime\":\"2024-01-01T00:00:00Z\"}]\n"
+	require.NoError(t, os.WriteFile(trailingNewlineFile, []byte(content), 0o600))
+
 	tests := []struct {
 		name        string
 		args        []string
@@ -145,9 +185,10 @@ func Test_Validate(t *testing.T) {
 			},
 		},
 		{
-			name:  "Invalid OCPP message",
-			args:  []string{"{\"invalid\": \"message\"}"},
-			flags: map[string]string{},
+			name:        "Invalid OCPP message",
+			args:        []string{"{\"invalid\": \"message\"}"},
+			flags:       map[string]string{},
+			expectedErr: errors.New("1 messages could not be parsed"),
 		},
 		{
 			name: "Invalid OCPP version",
@@ -155,26 +196,37 @@ func Test_Validate(t *testing.T) {
 			flags: map[string]string{
 				"version": "invalid_version",
 			},
+			expectedErr: errors.New(`invalid OCPP version "invalid_version"`),
 		},
 		{
 			name:  "Provided response without response-type",
 			args:  []string{validOcppResponse},
 			flags: map[string]string{},
 		},
+		{
+			name: "File ending with a newline",
+			flags: map[string]string{
+				"file": trailingNewlineFile,
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			// Setup: Set command arguments and flags
-			args := test.args
+			// Setup: Set command arguments and flags, flags keep their values between executions
+			require.NoError(t, rootCmd.PersistentFlags().Set("version", ocpp.V16.String()))
+			require.NoError(t, validate.Flags().Set("response-type", ""))
+			require.NoError(t, validate.Flags().Set("file", ""))
+
+			args := append([]string{"validate"}, test.args...)
 			for flag, value := range test.flags {
 				args = append(args, fmt.Sprintf("--%s=%s", flag, value))
 			}
 
-			validate.SetArgs(args)
+			rootCmd.SetArgs(args)
 
 			// Execute the command
-			err := validate.Execute()
+			err := rootCmd.Execute()
 			if test.expectedErr != nil {
 				assert.ErrorContains(t, err, test.expectedErr.Error())
 			} else {
@@ -182,4 +234,53 @@ func Test_Validate(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Follow with the default flags", func(t *testing.T) {
+		require.NoError(t, rootCmd.PersistentFlags().Set("version", ocpp.V16.String()))
+		t.Cleanup(func() {
+			_ = validate.Flags().Set("follow", "false")
+			_ = validate.Flags().Set("file", "")
+		})
+
+		// Follow the file until the context is done
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		// Cobra only passes the context to subcommands without one, which the previous executions already set
+		validate.SetContext(ctx)
+		rootCmd.SetArgs([]string{"validate", "--file", trailingNewlineFile, "--follow"})
+		assert.NoError(t, rootCmd.Execute())
+	})
+}
+
+func Test_loadConsoleMode(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("output-format", validation.ConsoleLogs)
+		viper.Set("quiet", false)
+		viper.Set("json", false)
+	})
+
+	viper.Set("output-format", validation.ConsolePretty)
+	mode, err := loadConsoleMode()
+	require.NoError(t, err)
+	assert.Equal(t, validation.ConsolePretty, mode)
+
+	viper.Set("json", true)
+	mode, err = loadConsoleMode()
+	require.NoError(t, err)
+	assert.Equal(t, validation.ConsoleJSON, mode)
+
+	viper.Set("quiet", true)
+	_, err = loadConsoleMode()
+	assert.Error(t, err)
+
+	viper.Set("json", false)
+	mode, err = loadConsoleMode()
+	require.NoError(t, err)
+	assert.Equal(t, validation.ConsoleQuiet, mode)
+
+	viper.Set("quiet", false)
+	viper.Set("output-format", "table")
+	_, err = loadConsoleMode()
+	assert.Error(t, err)
 }
@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package mock_schema_registry
+
+import (
+	"context"
+
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockSchemaLister creates a new instance of MockSchemaLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSchemaLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSchemaLister {
+	mock := &MockSchemaLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSchemaLister is an autogenerated mock type for the SchemaLister type
+type MockSchemaLister struct {
+	mock.Mock
+}
+
+type MockSchemaLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSchemaLister) EXPECT() *MockSchemaLister_Expecter {
+	return &MockSchemaLister_Expecter{mock: &_m.Mock}
+}
+
+// ListSchemas provides a mock function for the type MockSchemaLister
+func (_mock *MockSchemaLister) ListSchemas(ctx context.Context, req schema_registry.ListSchemasRequest) ([]string, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSchemas")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, schema_registry.ListSchemasRequest) ([]string, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, schema_registry.ListSchemasRequest) []string); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, schema_registry.ListSchemasRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSchemaLister_ListSchemas_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSchemas'
+type MockSchemaLister_ListSchemas_Call struct {
+	*mock.Call
+}
+
+// ListSchemas is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req schema_registry.ListSchemasRequest
+func (_e *MockSchemaLister_Expecter) ListSchemas(ctx interface{}, req interface{}) *MockSchemaLister_ListSchemas_Call {
+	return &MockSchemaLister_ListSchemas_Call{Call: _e.mock.On("ListSchemas", ctx, req)}
+}
+
+func (_c *MockSchemaLister_ListSchemas_Call) Run(run func(ctx context.Context, req schema_registry.ListSchemasRequest)) *MockSchemaLister_ListSchemas_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 schema_registry.ListSchemasRequest
+		if args[1] != nil {
+			arg1 = args[1].(schema_registry.ListSchemasRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSchemaLister_ListSchemas_Call) Return(strings []string, err error) *MockSchemaLister_ListSchemas_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockSchemaLister_ListSchemas_Call) RunAndReturn(run func(ctx context.Context, req schema_registry.ListSchemasRequest) ([]string, error)) *MockSchemaLister_ListSchemas_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,830 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: chargeflow/v1/validator.proto
+
+package chargeflowv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MessageType int32
+
+const (
+	MessageType_MESSAGE_TYPE_UNSPECIFIED       MessageType = 0
+	MessageType_MESSAGE_TYPE_CALL              MessageType = 2
+	MessageType_MESSAGE_TYPE_CALL_RESULT       MessageType = 3
+	MessageType_MESSAGE_TYPE_CALL_ERROR        MessageType = 4
+	MessageType_MESSAGE_TYPE_CALL_RESULT_ERROR MessageType = 5
+	MessageType_MESSAGE_TYPE_SEND              MessageType = 6
+)
+
+// Enum value maps for MessageType.
+var (
+	MessageType_name = map[int32]string{
+		0: "MESSAGE_TYPE_UNSPECIFIED",
+		2: "MESSAGE_TYPE_CALL",
+		3: "MESSAGE_TYPE_CALL_RESULT",
+		4: "MESSAGE_TYPE_CALL_ERROR",
+		5: "MESSAGE_TYPE_CALL_RESULT_ERROR",
+		6: "MESSAGE_TYPE_SEND",
+	}
+	MessageType_value = map[string]int32{
+		"MESSAGE_TYPE_UNSPECIFIED":       0,
+		"MESSAGE_TYPE_CALL":              2,
+		"MESSAGE_TYPE_CALL_RESULT":       3,
+		"MESSAGE_TYPE_CALL_ERROR":        4,
+		"MESSAGE_TYPE_CALL_RESULT_ERROR": 5,
+		"MESSAGE_TYPE_SEND":              6,
+	}
+)
+
+func (x MessageType) Enum() *MessageType {
+	p := new(MessageType)
+	*p = x
+	return p
+}
+
+func (x MessageType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageType) Descriptor() protoreflect.EnumDescriptor {
+	return file_chargeflow_v1_validator_proto_enumTypes[0].Descriptor()
+}
+
+func (MessageType) Type() protoreflect.EnumType {
+	return &file_chargeflow_v1_validator_proto_enumTypes[0]
+}
+
+func (x MessageType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageType.Descriptor instead.
+func (MessageType) EnumDescriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{0}
+}
+
+type OcppContext struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Vendor        string                 `protobuf:"bytes,2,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OcppContext) Reset() {
+	*x = OcppContext{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OcppContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OcppContext) ProtoMessage() {}
+
+func (x *OcppContext) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OcppContext.ProtoReflect.Descriptor instead.
+func (*OcppContext) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OcppContext) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *OcppContext) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *OcppContext) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type ValidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OcppContext   *OcppContext           `protobuf:"bytes,1,opt,name=ocpp_context,json=ocppContext,proto3" json:"ocpp_context,omitempty"`
+	Messages      []string               `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateRequest) Reset() {
+	*x = ValidateRequest{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateRequest) ProtoMessage() {}
+
+func (x *ValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateRequest.ProtoReflect.Descriptor instead.
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ValidateRequest) GetOcppContext() *OcppContext {
+	if x != nil {
+		return x.OcppContext
+	}
+	return nil
+}
+
+func (x *ValidateRequest) GetMessages() []string {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *Report                `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateResponse) GetReport() *Report {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type Report struct {
+	state               protoimpl.MessageState    `protogen:"open.v1"`
+	InvalidMessages     map[string]*MessageErrors `protobuf:"bytes,1,rep,name=invalid_messages,json=invalidMessages,proto3" json:"invalid_messages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	NonParsableMessages map[string]*Errors        `protobuf:"bytes,2,rep,name=non_parsable_messages,json=nonParsableMessages,proto3" json:"non_parsable_messages,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Statistics          *Statistics               `protobuf:"bytes,3,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Report) Reset() {
+	*x = Report{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Report) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Report) ProtoMessage() {}
+
+func (x *Report) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Report.ProtoReflect.Descriptor instead.
+func (*Report) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Report) GetInvalidMessages() map[string]*MessageErrors {
+	if x != nil {
+		return x.InvalidMessages
+	}
+	return nil
+}
+
+func (x *Report) GetNonParsableMessages() map[string]*Errors {
+	if x != nil {
+		return x.NonParsableMessages
+	}
+	return nil
+}
+
+func (x *Report) GetStatistics() *Statistics {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+type MessageErrors struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       []string               `protobuf:"bytes,1,rep,name=request,proto3" json:"request,omitempty"`
+	Response      []string               `protobuf:"bytes,2,rep,name=response,proto3" json:"response,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageErrors) Reset() {
+	*x = MessageErrors{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageErrors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageErrors) ProtoMessage() {}
+
+func (x *MessageErrors) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageErrors.ProtoReflect.Descriptor instead.
+func (*MessageErrors) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *MessageErrors) GetRequest() []string {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *MessageErrors) GetResponse() []string {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type Errors struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Errors        []string               `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Errors) Reset() {
+	*x = Errors{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Errors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Errors) ProtoMessage() {}
+
+func (x *Errors) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Errors.ProtoReflect.Descriptor instead.
+func (*Errors) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Errors) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type Statistics struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ValidRequests      int64                  `protobuf:"varint,1,opt,name=valid_requests,json=validRequests,proto3" json:"valid_requests,omitempty"`
+	ValidResponses     int64                  `protobuf:"varint,2,opt,name=valid_responses,json=validResponses,proto3" json:"valid_responses,omitempty"`
+	InvalidRequests    int64                  `protobuf:"varint,3,opt,name=invalid_requests,json=invalidRequests,proto3" json:"invalid_requests,omitempty"`
+	InvalidResponses   int64                  `protobuf:"varint,4,opt,name=invalid_responses,json=invalidResponses,proto3" json:"invalid_responses,omitempty"`
+	UnparsableMessages int64                  `protobuf:"varint,5,opt,name=unparsable_messages,json=unparsableMessages,proto3" json:"unparsable_messages,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Statistics) Reset() {
+	*x = Statistics{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistics) ProtoMessage() {}
+
+func (x *Statistics) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistics.ProtoReflect.Descriptor instead.
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Statistics) GetValidRequests() int64 {
+	if x != nil {
+		return x.ValidRequests
+	}
+	return 0
+}
+
+func (x *Statistics) GetValidResponses() int64 {
+	if x != nil {
+		return x.ValidResponses
+	}
+	return 0
+}
+
+func (x *Statistics) GetInvalidRequests() int64 {
+	if x != nil {
+		return x.InvalidRequests
+	}
+	return 0
+}
+
+func (x *Statistics) GetInvalidResponses() int64 {
+	if x != nil {
+		return x.InvalidResponses
+	}
+	return 0
+}
+
+func (x *Statistics) GetUnparsableMessages() int64 {
+	if x != nil {
+		return x.UnparsableMessages
+	}
+	return 0
+}
+
+type ValidateStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OcppContext   *OcppContext           `protobuf:"bytes,1,opt,name=ocpp_context,json=ocppContext,proto3" json:"ocpp_context,omitempty"`
+	ChargePointId string                 `protobuf:"bytes,2,opt,name=charge_point_id,json=chargePointId,proto3" json:"charge_point_id,omitempty"`
+	Frame         string                 `protobuf:"bytes,3,opt,name=frame,proto3" json:"frame,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateStreamRequest) Reset() {
+	*x = ValidateStreamRequest{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateStreamRequest) ProtoMessage() {}
+
+func (x *ValidateStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateStreamRequest.ProtoReflect.Descriptor instead.
+func (*ValidateStreamRequest) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidateStreamRequest) GetOcppContext() *OcppContext {
+	if x != nil {
+		return x.OcppContext
+	}
+	return nil
+}
+
+func (x *ValidateStreamRequest) GetChargePointId() string {
+	if x != nil {
+		return x.ChargePointId
+	}
+	return ""
+}
+
+func (x *ValidateStreamRequest) GetFrame() string {
+	if x != nil {
+		return x.Frame
+	}
+	return ""
+}
+
+type ValidateStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChargePointId string                 `protobuf:"bytes,1,opt,name=charge_point_id,json=chargePointId,proto3" json:"charge_point_id,omitempty"`
+	UniqueId      string                 `protobuf:"bytes,2,opt,name=unique_id,json=uniqueId,proto3" json:"unique_id,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	MessageType   MessageType            `protobuf:"varint,4,opt,name=message_type,json=messageType,proto3,enum=chargeflow.v1.MessageType" json:"message_type,omitempty"`
+	Parsable      bool                   `protobuf:"varint,5,opt,name=parsable,proto3" json:"parsable,omitempty"`
+	Valid         bool                   `protobuf:"varint,6,opt,name=valid,proto3" json:"valid,omitempty"`
+	Errors        []string               `protobuf:"bytes,7,rep,name=errors,proto3" json:"errors,omitempty"`
+	Sequence      []*SequenceResult      `protobuf:"bytes,8,rep,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateStreamResponse) Reset() {
+	*x = ValidateStreamResponse{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateStreamResponse) ProtoMessage() {}
+
+func (x *ValidateStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateStreamResponse.ProtoReflect.Descriptor instead.
+func (*ValidateStreamResponse) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ValidateStreamResponse) GetChargePointId() string {
+	if x != nil {
+		return x.ChargePointId
+	}
+	return ""
+}
+
+func (x *ValidateStreamResponse) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+func (x *ValidateStreamResponse) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ValidateStreamResponse) GetMessageType() MessageType {
+	if x != nil {
+		return x.MessageType
+	}
+	return MessageType_MESSAGE_TYPE_UNSPECIFIED
+}
+
+func (x *ValidateStreamResponse) GetParsable() bool {
+	if x != nil {
+		return x.Parsable
+	}
+	return false
+}
+
+func (x *ValidateStreamResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateStreamResponse) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ValidateStreamResponse) GetSequence() []*SequenceResult {
+	if x != nil {
+		return x.Sequence
+	}
+	return nil
+}
+
+type SequenceResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UniqueId      string                 `protobuf:"bytes,1,opt,name=unique_id,json=uniqueId,proto3" json:"unique_id,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Errors        []string               `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SequenceResult) Reset() {
+	*x = SequenceResult{}
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SequenceResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SequenceResult) ProtoMessage() {}
+
+func (x *SequenceResult) ProtoReflect() protoreflect.Message {
+	mi := &file_chargeflow_v1_validator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SequenceResult.ProtoReflect.Descriptor instead.
+func (*SequenceResult) Descriptor() ([]byte, []int) {
+	return file_chargeflow_v1_validator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SequenceResult) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+func (x *SequenceResult) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *SequenceResult) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+var File_chargeflow_v1_validator_proto protoreflect.FileDescriptor
+
+const file_chargeflow_v1_validator_proto_rawDesc = "" +
+	"\n" +
+	"\x1dchargeflow/v1/validator.proto\x12\rchargeflow.v1\"U\n" +
+	"\vOcppContext\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x16\n" +
+	"\x06vendor\x18\x02 \x01(\tR\x06vendor\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\"l\n" +
+	"\x0fValidateRequest\x12=\n" +
+	"\focpp_context\x18\x01 \x01(\v2\x1a.chargeflow.v1.OcppContextR\vocppContext\x12\x1a\n" +
+	"\bmessages\x18\x02 \x03(\tR\bmessages\"A\n" +
+	"\x10ValidateResponse\x12-\n" +
+	"\x06report\x18\x01 \x01(\v2\x15.chargeflow.v1.ReportR\x06report\"\xbf\x03\n" +
+	"\x06Report\x12U\n" +
+	"\x10invalid_messages\x18\x01 \x03(\v2*.chargeflow.v1.Report.InvalidMessagesEntryR\x0finvalidMessages\x12b\n" +
+	"\x15non_parsable_messages\x18\x02 \x03(\v2..chargeflow.v1.Report.NonParsableMessagesEntryR\x13nonParsableMessages\x129\n" +
+	"\n" +
+	"statistics\x18\x03 \x01(\v2\x19.chargeflow.v1.StatisticsR\n" +
+	"statistics\x1a`\n" +
+	"\x14InvalidMessagesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
+	"\x05value\x18\x02 \x01(\v2\x1c.chargeflow.v1.MessageErrorsR\x05value:\x028\x01\x1a]\n" +
+	"\x18NonParsableMessagesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.chargeflow.v1.ErrorsR\x05value:\x028\x01\"E\n" +
+	"\rMessageErrors\x12\x18\n" +
+	"\arequest\x18\x01 \x03(\tR\arequest\x12\x1a\n" +
+	"\bresponse\x18\x02 \x03(\tR\bresponse\" \n" +
+	"\x06Errors\x12\x16\n" +
+	"\x06errors\x18\x01 \x03(\tR\x06errors\"\xe5\x01\n" +
+	"\n" +
+	"Statistics\x12%\n" +
+	"\x0evalid_requests\x18\x01 \x01(\x03R\rvalidRequests\x12'\n" +
+	"\x0fvalid_responses\x18\x02 \x01(\x03R\x0evalidResponses\x12)\n" +
+	"\x10invalid_requests\x18\x03 \x01(\x03R\x0finvalidRequests\x12+\n" +
+	"\x11invalid_responses\x18\x04 \x01(\x03R\x10invalidResponses\x12/\n" +
+	"\x13unparsable_messages\x18\x05 \x01(\x03R\x12unparsableMessages\"\x94\x01\n" +
+	"\x15ValidateStreamRequest\x12=\n" +
+	"\focpp_context\x18\x01 \x01(\v2\x1a.chargeflow.v1.OcppContextR\vocppContext\x12&\n" +
+	"\x0fcharge_point_id\x18\x02 \x01(\tR\rchargePointId\x12\x14\n" +
+	"\x05frame\x18\x03 \x01(\tR\x05frame\"\xb9\x02\n" +
+	"\x16ValidateStreamResponse\x12&\n" +
+	"\x0fcharge_point_id\x18\x01 \x01(\tR\rchargePointId\x12\x1b\n" +
+	"\tunique_id\x18\x02 \x01(\tR\buniqueId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12=\n" +
+	"\fmessage_type\x18\x04 \x01(\x0e2\x1a.chargeflow.v1.MessageTypeR\vmessageType\x12\x1a\n" +
+	"\bparsable\x18\x05 \x01(\bR\bparsable\x12\x14\n" +
+	"\x05valid\x18\x06 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06errors\x18\a \x03(\tR\x06errors\x129\n" +
+	"\bsequence\x18\b \x03(\v2\x1d.chargeflow.v1.SequenceResultR\bsequence\"]\n" +
+	"\x0eSequenceResult\x12\x1b\n" +
+	"\tunique_id\x18\x01 \x01(\tR\buniqueId\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06errors\x18\x03 \x03(\tR\x06errors*\xb8\x01\n" +
+	"\vMessageType\x12\x1c\n" +
+	"\x18MESSAGE_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11MESSAGE_TYPE_CALL\x10\x02\x12\x1c\n" +
+	"\x18MESSAGE_TYPE_CALL_RESULT\x10\x03\x12\x1b\n" +
+	"\x17MESSAGE_TYPE_CALL_ERROR\x10\x04\x12\"\n" +
+	"\x1eMESSAGE_TYPE_CALL_RESULT_ERROR\x10\x05\x12\x15\n" +
+	"\x11MESSAGE_TYPE_SEND\x10\x062\xc5\x01\n" +
+	"\x13ChargeflowValidator\x12K\n" +
+	"\bValidate\x12\x1e.chargeflow.v1.ValidateRequest\x1a\x1f.chargeflow.v1.ValidateResponse\x12a\n" +
+	"\x0eValidateStream\x12$.chargeflow.v1.ValidateStreamRequest\x1a%.chargeflow.v1.ValidateStreamResponse(\x010\x01BEZCgithub.com/ChargePi/chargeflow/gen/proto/chargeflow/v1;chargeflowv1b\x06proto3"
+
+var (
+	file_chargeflow_v1_validator_proto_rawDescOnce sync.Once
+	file_chargeflow_v1_validator_proto_rawDescData []byte
+)
+
+func file_chargeflow_v1_validator_proto_rawDescGZIP() []byte {
+	file_chargeflow_v1_validator_proto_rawDescOnce.Do(func() {
+		file_chargeflow_v1_validator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_chargeflow_v1_validator_proto_rawDesc), len(file_chargeflow_v1_validator_proto_rawDesc)))
+	})
+	return file_chargeflow_v1_validator_proto_rawDescData
+}
+
+var file_chargeflow_v1_validator_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_chargeflow_v1_validator_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_chargeflow_v1_validator_proto_goTypes = []any{
+	(MessageType)(0),               // 0: chargeflow.v1.MessageType
+	(*OcppContext)(nil),            // 1: chargeflow.v1.OcppContext
+	(*ValidateRequest)(nil),        // 2: chargeflow.v1.ValidateRequest
+	(*ValidateResponse)(nil),       // 3: chargeflow.v1.ValidateResponse
+	(*Report)(nil),                 // 4: chargeflow.v1.Report
+	(*MessageErrors)(nil),          // 5: chargeflow.v1.MessageErrors
+	(*Errors)(nil),                 // 6: chargeflow.v1.Errors
+	(*Statistics)(nil),             // 7: chargeflow.v1.Statistics
+	(*ValidateStreamRequest)(nil),  // 8: chargeflow.v1.ValidateStreamRequest
+	(*ValidateStreamResponse)(nil), // 9: chargeflow.v1.ValidateStreamResponse
+	(*SequenceResult)(nil),         // 10: chargeflow.v1.SequenceResult
+	nil,                            // 11: chargeflow.v1.Report.InvalidMessagesEntry
+	nil,                            // 12: chargeflow.v1.Report.NonParsableMessagesEntry
+}
+var file_chargeflow_v1_validator_proto_depIdxs = []int32{
+	1,  // 0: chargeflow.v1.ValidateRequest.ocpp_context:type_name -> chargeflow.v1.OcppContext
+	4,  // 1: chargeflow.v1.ValidateResponse.report:type_name -> chargeflow.v1.Report
+	11, // 2: chargeflow.v1.Report.invalid_messages:type_name -> chargeflow.v1.Report.InvalidMessagesEntry
+	12, // 3: chargeflow.v1.Report.non_parsable_messages:type_name -> chargeflow.v1.Report.NonParsableMessagesEntry
+	7,  // 4: chargeflow.v1.Report.statistics:type_name -> chargeflow.v1.Statistics
+	1,  // 5: chargeflow.v1.ValidateStreamRequest.ocpp_context:type_name -> chargeflow.v1.OcppContext
+	0,  // 6: chargeflow.v1.ValidateStreamResponse.message_type:type_name -> chargeflow.v1.MessageType
+	10, // 7: chargeflow.v1.ValidateStreamResponse.sequence:type_name -> chargeflow.v1.SequenceResult
+	5,  // 8: chargeflow.v1.Report.InvalidMessagesEntry.value:type_name -> chargeflow.v1.MessageErrors
+	6,  // 9: chargeflow.v1.Report.NonParsableMessagesEntry.value:type_name -> chargeflow.v1.Errors
+	2,  // 10: chargeflow.v1.ChargeflowValidator.Validate:input_type -> chargeflow.v1.ValidateRequest
+	8,  // 11: chargeflow.v1.ChargeflowValidator.ValidateStream:input_type -> chargeflow.v1.ValidateStreamRequest
+	3,  // 12: chargeflow.v1.ChargeflowValidator.Validate:output_type -> chargeflow.v1.ValidateResponse
+	9,  // 13: chargeflow.v1.ChargeflowValidator.ValidateStream:output_type -> chargeflow.v1.ValidateStreamResponse
+	12, // [12:14] is the sub-list for method output_type
+	10, // [10:12] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_chargeflow_v1_validator_proto_init() }
+func file_chargeflow_v1_validator_proto_init() {
+	if File_chargeflow_v1_validator_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chargeflow_v1_validator_proto_rawDesc), len(file_chargeflow_v1_validator_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_chargeflow_v1_validator_proto_goTypes,
+		DependencyIndexes: file_chargeflow_v1_validator_proto_depIdxs,
+		EnumInfos:         file_chargeflow_v1_validator_proto_enumTypes,
+		MessageInfos:      file_chargeflow_v1_validator_proto_msgTypes,
+	}.Build()
+	File_chargeflow_v1_validator_proto = out.File
+	file_chargeflow_v1_validator_proto_goTypes = nil
+	file_chargeflow_v1_validator_proto_depIdxs = nil
+}
@@ -0,0 +1,154 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: chargeflow/v1/validator.proto
+
+package chargeflowv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ChargeflowValidator_Validate_FullMethodName       = "/chargeflow.v1.ChargeflowValidator/Validate"
+	ChargeflowValidator_ValidateStream_FullMethodName = "/chargeflow.v1.ChargeflowValidator/ValidateStream"
+)
+
+// ChargeflowValidatorClient is the client API for ChargeflowValidator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChargeflowValidatorClient interface {
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	ValidateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ValidateStreamRequest, ValidateStreamResponse], error)
+}
+
+type chargeflowValidatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChargeflowValidatorClient(cc grpc.ClientConnInterface) ChargeflowValidatorClient {
+	return &chargeflowValidatorClient{cc}
+}
+
+func (c *chargeflowValidatorClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, ChargeflowValidator_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chargeflowValidatorClient) ValidateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ValidateStreamRequest, ValidateStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChargeflowValidator_ServiceDesc.Streams[0], ChargeflowValidator_ValidateStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ValidateStreamRequest, ValidateStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChargeflowValidator_ValidateStreamClient = grpc.BidiStreamingClient[ValidateStreamRequest, ValidateStreamResponse]
+
+// ChargeflowValidatorServer is the server API for ChargeflowValidator service.
+// All implementations must embed UnimplementedChargeflowValidatorServer
+// for forward compatibility.
+type ChargeflowValidatorServer interface {
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	ValidateStream(grpc.BidiStreamingServer[ValidateStreamRequest, ValidateStreamResponse]) error
+	mustEmbedUnimplementedChargeflowValidatorServer()
+}
+
+// UnimplementedChargeflowValidatorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChargeflowValidatorServer struct{}
+
+func (UnimplementedChargeflowValidatorServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedChargeflowValidatorServer) ValidateStream(grpc.BidiStreamingServer[ValidateStreamRequest, ValidateStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ValidateStream not implemented")
+}
+func (UnimplementedChargeflowValidatorServer) mustEmbedUnimplementedChargeflowValidatorServer() {}
+func (UnimplementedChargeflowValidatorServer) testEmbeddedByValue()                             {}
+
+// UnsafeChargeflowValidatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChargeflowValidatorServer will
+// result in compilation errors.
+type UnsafeChargeflowValidatorServer interface {
+	mustEmbedUnimplementedChargeflowValidatorServer()
+}
+
+func RegisterChargeflowValidatorServer(s grpc.ServiceRegistrar, srv ChargeflowValidatorServer) {
+	// If the following call pancis, it indicates UnimplementedChargeflowValidatorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChargeflowValidator_ServiceDesc, srv)
+}
+
+func _ChargeflowValidator_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChargeflowValidatorServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChargeflowValidator_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChargeflowValidatorServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChargeflowValidator_ValidateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChargeflowValidatorServer).ValidateStream(&grpc.GenericServerStream[ValidateStreamRequest, ValidateStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChargeflowValidator_ValidateStreamServer = grpc.BidiStreamingServer[ValidateStreamRequest, ValidateStreamResponse]
+
+// ChargeflowValidator_ServiceDesc is the grpc.ServiceDesc for ChargeflowValidator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChargeflowValidator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chargeflow.v1.ChargeflowValidator",
+	HandlerType: (*ChargeflowValidatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Validate",
+			Handler:    _ChargeflowValidator_Validate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ValidateStream",
+			Handler:       _ChargeflowValidator_ValidateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "chargeflow/v1/validator.proto",
+}
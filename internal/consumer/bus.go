@@ -0,0 +1,23 @@
+package consumer
+
+import "context"
+
+// Message is a single message received from a message bus.
+type Message struct {
+	// Topic (or subject) the message was received on.
+	Topic string
+	// Key of the message. Only Kafka messages have a key.
+	Key string
+	// Headers of the message: Kafka record headers, NATS headers or MQTT v5 user properties.
+	Headers map[string]string
+	Payload []byte
+}
+
+// Bus is a message bus OCPP frames are consumed from and validation verdicts are published to.
+type Bus interface {
+	// Consume receives messages until the context is canceled, calling handle for each of them in order.
+	Consume(ctx context.Context, handle func(ctx context.Context, message Message)) error
+	// Publish publishes a message to the output topic. Buses without an output topic discard the message.
+	Publish(ctx context.Context, message Message) error
+	Close() error
+}
@@ -0,0 +1,109 @@
+package kafka_bus
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/consumer"
+)
+
+// Bus consumes OCPP frames from Kafka (or any Kafka-compatible broker, such as Redpanda) topics.
+type Bus struct {
+	logger *zap.Logger
+	client *kgo.Client
+	config options
+}
+
+func NewBus(logger *zap.Logger, brokers []string, topics []string, opts ...Option) (*Bus, error) {
+	config := options{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	offset := kgo.NewOffset().AtEnd()
+	if config.fromBeginning {
+		offset = kgo.NewOffset().AtStart()
+	}
+
+	clientOpts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.ConsumeTopics(topics...),
+		kgo.ConsumeResetOffset(offset),
+	}
+
+	if config.group != "" {
+		clientOpts = append(clientOpts, kgo.ConsumerGroup(config.group))
+	}
+
+	client, err := kgo.NewClient(clientOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create Kafka client")
+	}
+
+	return &Bus{
+		logger: logger.Named("kafka_bus"),
+		client: client,
+		config: config,
+	}, nil
+}
+
+func (b *Bus) Consume(ctx context.Context, handle func(ctx context.Context, message consumer.Message)) error {
+	for {
+		fetches := b.client.PollFetches(ctx)
+		if ctx.Err() != nil || fetches.IsClientClosed() {
+			return nil
+		}
+
+		for _, fetchErr := range fetches.Errors() {
+			b.logger.Warn("Unable to fetch records",
+				zap.String("topic", fetchErr.Topic),
+				zap.Int32("partition", fetchErr.Partition),
+				zap.Error(fetchErr.Err),
+			)
+		}
+
+		fetches.EachRecord(func(record *kgo.Record) {
+			headers := make(map[string]string, len(record.Headers))
+			for _, header := range record.Headers {
+				headers[header.Key] = string(header.Value)
+			}
+
+			handle(ctx, consumer.Message{
+				Topic:   record.Topic,
+				Key:     string(record.Key),
+				Headers: headers,
+				Payload: record.Value,
+			})
+		})
+	}
+}
+
+func (b *Bus) Publish(ctx context.Context, message consumer.Message) error {
+	if b.config.outputTopic == "" {
+		return nil
+	}
+
+	record := &kgo.Record{
+		Topic: b.config.outputTopic,
+		Key:   []byte(message.Key),
+		Value: message.Payload,
+	}
+
+	for key, value := range message.Headers {
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+	}
+
+	if err := b.client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return errors.Wrap(err, "unable to produce record")
+	}
+
+	return nil
+}
+
+func (b *Bus) Close() error {
+	b.client.Close()
+	return nil
+}
@@ -0,0 +1,115 @@
+package kafka_bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/redpanda"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/consumer"
+)
+
+type kafkaBusIntegrationTestSuite struct {
+	suite.Suite
+	redpandaContainer *redpanda.Container
+	broker            string
+}
+
+func (s *kafkaBusIntegrationTestSuite) SetupSuite() {
+	testcontainers.SkipIfProviderIsNotHealthy(s.T())
+	ctx := context.Background()
+
+	// Redpanda is used as a lightweight stand-in for Kafka
+	redpandaContainer, err := redpanda.Run(ctx, "docker.redpanda.com/redpandadata/redpanda:v23.1.7", redpanda.WithAutoCreateTopics())
+	s.Require().NoError(err, "Failed to start Redpanda container")
+	s.redpandaContainer = redpandaContainer
+
+	s.broker, err = redpandaContainer.KafkaSeedBroker(ctx)
+	s.Require().NoError(err, "Failed to get Kafka seed broker")
+}
+
+func (s *kafkaBusIntegrationTestSuite) TearDownSuite() {
+	if s.redpandaContainer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		s.NoError(s.redpandaContainer.Terminate(ctx), "Failed to terminate Redpanda container")
+	}
+}
+
+func (s *kafkaBusIntegrationTestSuite) TestConsumeAndPublish() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	producer, err := kgo.NewClient(kgo.SeedBrokers(s.broker), kgo.AllowAutoTopicCreation())
+	s.Require().NoError(err)
+	defer producer.Close()
+
+	err = producer.ProduceSync(ctx, &kgo.Record{
+		Topic:   "ocpp-frames",
+		Key:     []byte("CP1"),
+		Value:   []byte(`[2, "1", "Heartbeat", {}]`),
+		Headers: []kgo.RecordHeader{{Key: "direction", Value: []byte("inbound")}},
+	}).FirstErr()
+	s.Require().NoError(err)
+
+	bus, err := NewBus(zap.NewNop(), []string{s.broker}, []string{"ocpp-frames"},
+		WithConsumerGroup("chargeflow-test"),
+		WithOutputTopic("ocpp-verdicts"),
+		WithFromBeginning(true),
+	)
+	s.Require().NoError(err)
+	defer bus.Close()
+
+	received := make(chan consumer.Message, 1)
+	consumeCtx, stopConsuming := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Consume(consumeCtx, func(_ context.Context, message consumer.Message) {
+			select {
+			case received <- message:
+			default:
+			}
+		})
+	}()
+
+	select {
+	case message := <-received:
+		s.Equal("ocpp-frames", message.Topic)
+		s.Equal("CP1", message.Key)
+		s.Equal("inbound", message.Headers["direction"])
+		s.JSONEq(`[2, "1", "Heartbeat", {}]`, string(message.Payload))
+	case <-ctx.Done():
+		s.FailNow("timed out waiting for the frame")
+	}
+
+	stopConsuming()
+	s.Require().NoError(<-done)
+
+	err = bus.Publish(ctx, consumer.Message{Key: "CP1", Headers: map[string]string{"charge-point-id": "CP1"}, Payload: []byte(`{"valid": true}`)})
+	s.Require().NoError(err)
+
+	verdicts, err := kgo.NewClient(
+		kgo.SeedBrokers(s.broker),
+		kgo.ConsumeTopics("ocpp-verdicts"),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	s.Require().NoError(err)
+	defer verdicts.Close()
+
+	fetches := verdicts.PollRecords(ctx, 1)
+	s.Require().NoError(fetches.Err())
+	records := fetches.Records()
+	s.Require().Len(records, 1)
+	s.Equal("CP1", string(records[0].Key))
+	s.JSONEq(`{"valid": true}`, string(records[0].Value))
+}
+
+func TestKafkaBusIntegration(t *testing.T) {
+	suite.Run(t, new(kafkaBusIntegrationTestSuite))
+}
@@ -0,0 +1,31 @@
+package kafka_bus
+
+type options struct {
+	group         string
+	outputTopic   string
+	fromBeginning bool
+}
+
+type Option func(*options)
+
+// WithConsumerGroup consumes the topics as part of a consumer group, committing the consumed offsets.
+func WithConsumerGroup(group string) Option {
+	return func(o *options) {
+		o.group = group
+	}
+}
+
+// WithOutputTopic sets the topic the verdicts are published to. Verdicts are discarded if empty.
+func WithOutputTopic(topic string) Option {
+	return func(o *options) {
+		o.outputTopic = topic
+	}
+}
+
+// WithFromBeginning consumes the topics from the earliest offset, instead of only new records.
+// For consumer groups, it only applies if the group has no committed offsets yet.
+func WithFromBeginning(fromBeginning bool) Option {
+	return func(o *options) {
+		o.fromBeginning = fromBeginning
+	}
+}
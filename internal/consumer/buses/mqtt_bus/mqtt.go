@@ -0,0 +1,164 @@
+package mqtt_bus
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/consumer"
+)
+
+// bufferSize is the number of received messages buffered while a message is being validated.
+const bufferSize = 1024
+
+// disconnectTimeout is how long Close waits for the connection to be closed gracefully.
+const disconnectTimeout = 5 * time.Second
+
+// Bus consumes OCPP frames from MQTT v5 topics. Frame metadata is read from the MQTT v5 user properties.
+type Bus struct {
+	logger     *zap.Logger
+	connection *autopaho.ConnectionManager
+	messages   chan consumer.Message
+	cancel     context.CancelFunc
+	config     options
+}
+
+func NewBus(logger *zap.Logger, serverUrl string, topics []string, opts ...Option) (*Bus, error) {
+	config := options{
+		clientID: "chargeflow",
+		qos:      1,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	server, err := url.Parse(serverUrl)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid MQTT server URL")
+	}
+
+	logger = logger.Named("mqtt_bus")
+	messages := make(chan consumer.Message, bufferSize)
+	// The connection outlives the context passed to Consume, so that the verdicts can still be published.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	subscriptions := make([]paho.SubscribeOptions, 0, len(topics))
+	for _, topic := range topics {
+		subscriptions = append(subscriptions, paho.SubscribeOptions{Topic: topic, QoS: config.qos})
+	}
+
+	clientConfig := autopaho.ClientConfig{
+		ServerUrls:                    []*url.URL{server},
+		KeepAlive:                     30,
+		CleanStartOnInitialConnection: true,
+		OnConnectionUp: func(connection *autopaho.ConnectionManager, _ *paho.Connack) {
+			// Subscribe on every (re)connection, since the session is not persisted.
+			go func() {
+				if _, err := connection.Subscribe(ctx, &paho.Subscribe{Subscriptions: subscriptions}); err != nil {
+					logger.Error("Unable to subscribe to topics", zap.Strings("topics", topics), zap.Error(err))
+				}
+			}()
+		},
+		OnConnectError: func(err error) {
+			logger.Warn("Unable to connect to the MQTT server", zap.Error(err))
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: config.clientID,
+			OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+				func(received paho.PublishReceived) (bool, error) {
+					headers := map[string]string{}
+					if received.Packet.Properties != nil {
+						for _, property := range received.Packet.Properties.User {
+							headers[property.Key] = property.Value
+						}
+					}
+
+					select {
+					case messages <- consumer.Message{
+						Topic:   received.Packet.Topic,
+						Headers: headers,
+						Payload: received.Packet.Payload,
+					}:
+					case <-ctx.Done():
+					}
+
+					return true, nil
+				},
+			},
+		},
+	}
+
+	connection, err := autopaho.NewConnection(ctx, clientConfig)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrap(err, "unable to create MQTT connection")
+	}
+
+	return &Bus{
+		logger:     logger,
+		connection: connection,
+		messages:   messages,
+		cancel:     cancel,
+		config:     config,
+	}, nil
+}
+
+func (b *Bus) Consume(ctx context.Context, handle func(ctx context.Context, message consumer.Message)) error {
+	if err := b.connection.AwaitConnection(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return errors.Wrap(err, "unable to connect to the MQTT server")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message := <-b.messages:
+			handle(ctx, message)
+		}
+	}
+}
+
+func (b *Bus) Publish(ctx context.Context, message consumer.Message) error {
+	if b.config.outputTopic == "" {
+		return nil
+	}
+
+	properties := &paho.PublishProperties{}
+	for key, value := range message.Headers {
+		properties.User.Add(key, value)
+	}
+
+	_, err := b.connection.Publish(ctx, &paho.Publish{
+		Topic:      b.config.outputTopic,
+		QoS:        b.config.qos,
+		Payload:    message.Payload,
+		Properties: properties,
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to publish message")
+	}
+
+	return nil
+}
+
+func (b *Bus) Close() error {
+	defer b.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), disconnectTimeout)
+	defer cancel()
+
+	if err := b.connection.Disconnect(ctx); err != nil {
+		return errors.Wrap(err, "unable to disconnect from the MQTT server")
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package mqtt_bus
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	mqtt "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/consumer"
+)
+
+type mqttBusTestSuite struct {
+	suite.Suite
+	broker   *mqtt.Server
+	listener *listeners.TCP
+}
+
+func (s *mqttBusTestSuite) SetupSuite() {
+	s.broker = mqtt.New(&mqtt.Options{})
+	s.Require().NoError(s.broker.AddHook(new(auth.AllowHook), nil))
+
+	s.listener = listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	s.Require().NoError(s.broker.AddListener(s.listener))
+	s.Require().NoError(s.broker.Serve())
+}
+
+func (s *mqttBusTestSuite) TearDownSuite() {
+	s.NoError(s.broker.Close())
+}
+
+// newClient connects a plain MQTT v5 client to the broker, forwarding received messages to the returned channel.
+func (s *mqttBusTestSuite) newClient(ctx context.Context, clientID string) (*paho.Client, chan *paho.Publish) {
+	conn, err := net.Dial("tcp", s.listener.Address())
+	s.Require().NoError(err)
+
+	received := make(chan *paho.Publish, 1)
+	client := paho.NewClient(paho.ClientConfig{
+		Conn: conn,
+		OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+			func(publish paho.PublishReceived) (bool, error) {
+				received <- publish.Packet
+				return true, nil
+			},
+		},
+	})
+
+	_, err = client.Connect(ctx, &paho.Connect{ClientID: clientID, CleanStart: true, KeepAlive: 30})
+	s.Require().NoError(err)
+
+	return client, received
+}
+
+func (s *mqttBusTestSuite) TestConsumeAndPublish() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bus, err := NewBus(zap.NewNop(), "mqtt://"+s.listener.Address(), []string{"ocpp/frames/#"},
+		WithClientID("chargeflow-test"),
+		WithOutputTopic("ocpp/verdicts"),
+	)
+	s.Require().NoError(err)
+	defer bus.Close()
+
+	client, verdicts := s.newClient(ctx, "test-client")
+	defer client.Disconnect(&paho.Disconnect{})
+
+	_, err = client.Subscribe(ctx, &paho.Subscribe{Subscriptions: []paho.SubscribeOptions{{Topic: "ocpp/verdicts", QoS: 1}}})
+	s.Require().NoError(err)
+
+	received := make(chan consumer.Message, 1)
+	consumeCtx, stopConsuming := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Consume(consumeCtx, func(_ context.Context, message consumer.Message) {
+			select {
+			case received <- message:
+			default:
+			}
+		})
+	}()
+
+	properties := &paho.PublishProperties{}
+	properties.User.Add("charge-point-id", "CP1").Add("direction", "inbound")
+	frame := &paho.Publish{Topic: "ocpp/frames/CP1", QoS: 1, Payload: []byte(`[2, "1", "Heartbeat", {}]`), Properties: properties}
+
+	// The bus subscribes asynchronously after connecting, so keep publishing until the frame is received.
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	var message consumer.Message
+waitLoop:
+	for {
+		select {
+		case message = <-received:
+			break waitLoop
+		case <-ticker.C:
+			_, err := client.Publish(ctx, frame)
+			s.Require().NoError(err)
+		case <-ctx.Done():
+			s.FailNow("timed out waiting for the frame")
+		}
+	}
+
+	s.Equal("ocpp/frames/CP1", message.Topic)
+	s.Equal("CP1", message.Headers["charge-point-id"])
+	s.Equal("inbound", message.Headers["direction"])
+	s.JSONEq(`[2, "1", "Heartbeat", {}]`, string(message.Payload))
+
+	stopConsuming()
+	s.Require().NoError(<-done)
+
+	err = bus.Publish(ctx, consumer.Message{Headers: map[string]string{"charge-point-id": "CP1"}, Payload: []byte(`{"valid": true}`)})
+	s.Require().NoError(err)
+
+	select {
+	case verdict := <-verdicts:
+		s.Require().NotNil(verdict.Properties)
+		s.Equal("CP1", verdict.Properties.User.Get("charge-point-id"))
+		s.JSONEq(`{"valid": true}`, string(verdict.Payload))
+	case <-ctx.Done():
+		s.FailNow("timed out waiting for the verdict")
+	}
+}
+
+func TestMqttBus(t *testing.T) {
+	suite.Run(t, new(mqttBusTestSuite))
+}
@@ -0,0 +1,30 @@
+package mqtt_bus
+
+type options struct {
+	clientID    string
+	outputTopic string
+	qos         byte
+}
+
+type Option func(*options)
+
+// WithClientID sets the MQTT client ID. Defaults to "chargeflow".
+func WithClientID(clientID string) Option {
+	return func(o *options) {
+		o.clientID = clientID
+	}
+}
+
+// WithOutputTopic sets the topic the verdicts are published to. Verdicts are discarded if empty.
+func WithOutputTopic(topic string) Option {
+	return func(o *options) {
+		o.outputTopic = topic
+	}
+}
+
+// WithQoS sets the QoS used for subscribing to the topics and publishing verdicts. Defaults to 1.
+func WithQoS(qos byte) Option {
+	return func(o *options) {
+		o.qos = qos
+	}
+}
@@ -0,0 +1,114 @@
+package nats_bus
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/consumer"
+)
+
+// bufferSize is the number of received messages buffered while a message is being validated.
+const bufferSize = 1024
+
+// Bus consumes OCPP frames from NATS subjects.
+type Bus struct {
+	logger   *zap.Logger
+	conn     *nats.Conn
+	subjects []string
+	config   options
+}
+
+func NewBus(logger *zap.Logger, url string, subjects []string, opts ...Option) (*Bus, error) {
+	config := options{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	conn, err := nats.Connect(url, nats.Name("chargeflow"))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to connect to NATS")
+	}
+
+	return &Bus{
+		logger:   logger.Named("nats_bus"),
+		conn:     conn,
+		subjects: subjects,
+		config:   config,
+	}, nil
+}
+
+func (b *Bus) Consume(ctx context.Context, handle func(ctx context.Context, message consumer.Message)) error {
+	messages := make(chan *nats.Msg, bufferSize)
+
+	for _, subject := range b.subjects {
+		var (
+			subscription *nats.Subscription
+			err          error
+		)
+
+		if b.config.queueGroup != "" {
+			subscription, err = b.conn.ChanQueueSubscribe(subject, b.config.queueGroup, messages)
+		} else {
+			subscription, err = b.conn.ChanSubscribe(subject, messages)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to subscribe to %s", subject)
+		}
+
+		//nolint:errcheck
+		defer subscription.Unsubscribe()
+	}
+
+	// Make sure the subscriptions are registered on the server before returning control to the caller.
+	if err := b.conn.Flush(); err != nil {
+		return errors.Wrap(err, "unable to flush subscriptions")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-messages:
+			headers := make(map[string]string, len(msg.Header))
+			for key := range msg.Header {
+				headers[key] = msg.Header.Get(key)
+			}
+
+			handle(ctx, consumer.Message{
+				Topic:   msg.Subject,
+				Headers: headers,
+				Payload: msg.Data,
+			})
+		}
+	}
+}
+
+func (b *Bus) Publish(_ context.Context, message consumer.Message) error {
+	if b.config.outputSubject == "" {
+		return nil
+	}
+
+	msg := nats.NewMsg(b.config.outputSubject)
+	msg.Data = message.Payload
+	for key, value := range message.Headers {
+		msg.Header.Set(key, value)
+	}
+
+	if err := b.conn.PublishMsg(msg); err != nil {
+		return errors.Wrap(err, "unable to publish message")
+	}
+
+	return nil
+}
+
+func (b *Bus) Close() error {
+	if err := b.conn.Drain(); err != nil {
+		b.conn.Close()
+		return errors.Wrap(err, "unable to drain NATS connection")
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package nats_bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/consumer"
+)
+
+type natsBusTestSuite struct {
+	suite.Suite
+	server *server.Server
+}
+
+func (s *natsBusTestSuite) SetupSuite() {
+	natsServer, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	s.Require().NoError(err)
+
+	go natsServer.Start()
+	s.Require().True(natsServer.ReadyForConnections(10*time.Second), "NATS server not ready")
+	s.server = natsServer
+}
+
+func (s *natsBusTestSuite) TearDownSuite() {
+	s.server.Shutdown()
+}
+
+func (s *natsBusTestSuite) TestConsumeAndPublish() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	bus, err := NewBus(zap.NewNop(), s.server.ClientURL(), []string{"ocpp.frames.>"}, WithOutputSubject("ocpp.verdicts"))
+	s.Require().NoError(err)
+	defer bus.Close()
+
+	client, err := nats.Connect(s.server.ClientURL())
+	s.Require().NoError(err)
+	defer client.Close()
+
+	verdicts, err := client.SubscribeSync("ocpp.verdicts")
+	s.Require().NoError(err)
+	s.Require().NoError(client.Flush())
+
+	received := make(chan consumer.Message, 1)
+	consumeCtx, stopConsuming := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Consume(consumeCtx, func(_ context.Context, message consumer.Message) {
+			select {
+			case received <- message:
+			default:
+			}
+		})
+	}()
+
+	// The subscription is registered asynchronously, so keep publishing until the frame is received.
+	msg := nats.NewMsg("ocpp.frames.CP1")
+	msg.Header.Set("charge-point-id", "CP1")
+	msg.Header.Set("direction", "inbound")
+	msg.Data = []byte(`[2, "1", "Heartbeat", {}]`)
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	var message consumer.Message
+waitLoop:
+	for {
+		select {
+		case message = <-received:
+			break waitLoop
+		case <-ticker.C:
+			s.Require().NoError(client.PublishMsg(msg))
+		case <-ctx.Done():
+			s.FailNow("timed out waiting for the frame")
+		}
+	}
+
+	s.Equal("ocpp.frames.CP1", message.Topic)
+	s.Equal("CP1", message.Headers["charge-point-id"])
+	s.Equal("inbound", message.Headers["direction"])
+	s.JSONEq(`[2, "1", "Heartbeat", {}]`, string(message.Payload))
+
+	stopConsuming()
+	s.Require().NoError(<-done)
+
+	err = bus.Publish(ctx, consumer.Message{Headers: map[string]string{"charge-point-id": "CP1"}, Payload: []byte(`{"valid": true}`)})
+	s.Require().NoError(err)
+
+	verdict, err := verdicts.NextMsg(10 * time.Second)
+	s.Require().NoError(err)
+	s.Equal("CP1", verdict.Header.Get("charge-point-id"))
+	s.JSONEq(`{"valid": true}`, string(verdict.Data))
+}
+
+func TestNatsBus(t *testing.T) {
+	suite.Run(t, new(natsBusTestSuite))
+}
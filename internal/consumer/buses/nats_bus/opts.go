@@ -0,0 +1,22 @@
+package nats_bus
+
+type options struct {
+	queueGroup    string
+	outputSubject string
+}
+
+type Option func(*options)
+
+// WithQueueGroup subscribes as part of a queue group, so that multiple consumers share the messages.
+func WithQueueGroup(group string) Option {
+	return func(o *options) {
+		o.queueGroup = group
+	}
+}
+
+// WithOutputSubject sets the subject the verdicts are published to. Verdicts are discarded if empty.
+func WithOutputSubject(subject string) Option {
+	return func(o *options) {
+		o.outputSubject = subject
+	}
+}
@@ -0,0 +1,184 @@
+// Package consumer continuously validates OCPP frames consumed from a message bus (e.g. Kafka, NATS or MQTT)
+// and publishes the validation verdicts back to the bus.
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+// Verdict is the outcome of validating a single frame, published to the output topic as JSON.
+type Verdict struct {
+	ChargePointId string           `json:"chargePointId"`
+	Direction     string           `json:"direction,omitempty"`
+	OcppVersion   string           `json:"ocppVersion"`
+	UniqueId      string           `json:"uniqueId"`
+	Action        string           `json:"action,omitempty"`
+	MessageType   ocpp.MessageType `json:"messageType,omitempty"`
+	Parsable      bool             `json:"parsable"`
+	Valid         bool             `json:"valid"`
+	Errors        []string         `json:"errors,omitempty"`
+	Topic         string           `json:"topic"`
+	Timestamp     time.Time        `json:"timestamp"`
+}
+
+// Consumer validates the frames received from a bus. Each charge point gets its own validation session,
+// so responses are only paired with requests of the same charge point.
+type Consumer struct {
+	logger  *zap.Logger
+	service *validation.Service
+	bus     Bus
+	octx    ocpp.OcppContext
+	config  options
+
+	// sessions are only accessed from the bus' consume loop, so they need no locking.
+	sessions map[string]*validation.Session
+}
+
+// NewConsumer creates a Consumer validating frames against the given OCPP context, unless a frame
+// specifies its OCPP version in a header.
+func NewConsumer(logger *zap.Logger, service *validation.Service, bus Bus, octx ocpp.OcppContext, opts ...Option) *Consumer {
+	config := options{
+		chargePointIdHeader: "charge-point-id",
+		directionHeader:     "direction",
+		versionHeader:       "ocpp-version",
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &Consumer{
+		logger:   logger.Named("consumer"),
+		service:  service,
+		bus:      bus,
+		octx:     octx,
+		config:   config,
+		sessions: make(map[string]*validation.Session),
+	}
+}
+
+// Run consumes and validates frames until the context is canceled.
+func (c *Consumer) Run(ctx context.Context) error {
+	c.logger.Info("Consuming OCPP frames")
+
+	if err := c.bus.Consume(ctx, c.handle); err != nil {
+		return errors.Wrap(err, "unable to consume messages")
+	}
+
+	return nil
+}
+
+func (c *Consumer) handle(ctx context.Context, message Message) {
+	chargePointId := c.chargePointId(message)
+	octx := c.ocppContext(message)
+	logger := c.logger.With(zap.String("chargePointId", chargePointId), zap.String("topic", message.Topic))
+
+	verdict := Verdict{
+		ChargePointId: chargePointId,
+		Direction:     message.Headers[c.config.directionHeader],
+		OcppVersion:   octx.Version.Name(),
+		Topic:         message.Topic,
+		Timestamp:     time.Now().UTC(),
+	}
+
+	result, err := c.session(chargePointId).ValidateFrame(octx, string(message.Payload))
+	if err != nil {
+		// The frame could not be validated at all, e.g. there is no schema for the action.
+		logger.Warn("Unable to validate frame", zap.Error(err))
+		verdict.Parsable = true
+		verdict.Errors = []string{err.Error()}
+	} else {
+		verdict.UniqueId = result.UniqueId
+		verdict.Action = result.Action
+		verdict.MessageType = result.MessageType
+		verdict.Parsable = result.Parsable
+		verdict.Valid = result.Valid
+		verdict.Errors = result.Errors
+	}
+
+	c.publish(ctx, logger, verdict)
+	if result == nil {
+		return
+	}
+
+	// Earlier messages violating the sequence rules get a verdict of their own
+	for _, sequence := range result.Sequence {
+		c.publish(ctx, logger, Verdict{
+			ChargePointId: chargePointId,
+			OcppVersion:   verdict.OcppVersion,
+			UniqueId:      sequence.UniqueId,
+			Action:        sequence.Action,
+			Parsable:      true,
+			Errors:        sequence.Errors,
+			Topic:         message.Topic,
+			Timestamp:     verdict.Timestamp,
+		})
+	}
+}
+
+// publish publishes the verdict to the output topic, unless only invalid verdicts are published.
+func (c *Consumer) publish(ctx context.Context, logger *zap.Logger, verdict Verdict) {
+	if !verdict.Valid {
+		logger.Warn("Invalid OCPP frame",
+			zap.String("uniqueId", verdict.UniqueId),
+			zap.String("action", verdict.Action),
+			zap.Strings("errors", verdict.Errors),
+		)
+	}
+
+	if verdict.Valid && c.config.onlyInvalid {
+		return
+	}
+
+	payload, err := json.Marshal(verdict)
+	if err != nil {
+		logger.Error("Unable to marshal verdict", zap.Error(err))
+		return
+	}
+
+	err = c.bus.Publish(ctx, Message{
+		Key:     verdict.ChargePointId,
+		Headers: map[string]string{c.config.chargePointIdHeader: verdict.ChargePointId},
+		Payload: payload,
+	})
+	if err != nil {
+		logger.Error("Unable to publish verdict", zap.Error(err))
+	}
+}
+
+// chargePointId returns the charge point ID from the headers, falling back to the message key.
+func (c *Consumer) chargePointId(message Message) string {
+	if chargePointId := message.Headers[c.config.chargePointIdHeader]; chargePointId != "" {
+		return chargePointId
+	}
+
+	return message.Key
+}
+
+func (c *Consumer) ocppContext(message Message) ocpp.OcppContext {
+	octx := c.octx
+
+	if version, err := ocpp.ParseVersion(message.Headers[c.config.versionHeader]); err == nil {
+		octx.Version = version
+	}
+
+	return octx
+}
+
+func (c *Consumer) session(chargePointId string) *validation.Session {
+	session, found := c.sessions[chargePointId]
+	if !found {
+		session = c.service.NewSession()
+		c.sessions[chargePointId] = session
+	}
+
+	return session
+}
@@ -0,0 +1,129 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
+	"github.com/ChargePi/chargeflow/pkg/schemas"
+)
+
+// fakeBus delivers the given messages and records the published ones.
+type fakeBus struct {
+	messages  []Message
+	published []Message
+}
+
+func (b *fakeBus) Consume(ctx context.Context, handle func(ctx context.Context, message Message)) error {
+	for _, message := range b.messages {
+		handle(ctx, message)
+	}
+	return nil
+}
+
+func (b *fakeBus) Publish(_ context.Context, message Message) error {
+	b.published = append(b.published, message)
+	return nil
+}
+
+func (b *fakeBus) Close() error {
+	return nil
+}
+
+type consumerTestSuite struct {
+	suite.Suite
+	service *validation.Service
+}
+
+func (s *consumerTestSuite) SetupSuite() {
+	logger := zap.NewNop()
+	registry := file_registry.NewFileSchemaRegistry(logger)
+	for _, version := range schemas.Versions() {
+		s.Require().NoError(schemas.Register(context.Background(), registry, version))
+	}
+
+	s.service = validation.NewService(logger, registry)
+}
+
+func (s *consumerTestSuite) verdicts(bus *fakeBus) []Verdict {
+	verdicts := make([]Verdict, 0, len(bus.published))
+	for _, message := range bus.published {
+		var verdict Verdict
+		s.Require().NoError(json.Unmarshal(message.Payload, &verdict))
+		s.Equal(verdict.ChargePointId, message.Key)
+		verdicts = append(verdicts, verdict)
+	}
+	return verdicts
+}
+
+func (s *consumerTestSuite) TestRun() {
+	bus := &fakeBus{messages: []Message{
+		{Topic: "ocpp", Headers: map[string]string{"charge-point-id": "CP1", "direction": "inbound"}, Payload: []byte(`[2, "1", "Heartbeat", {}]`)},
+		{Topic: "ocpp", Headers: map[string]string{"charge-point-id": "CP2", "direction": "inbound"}, Payload: []byte(`[2, "1", "Heartbeat", {"unexpected": true}]`)},
+		// Charge point ID taken from the key
+		{Topic: "ocpp", Key: "CP1", Headers: map[string]string{"direction": "outbound"}, Payload: []byte(`[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`)},
+		{Topic: "ocpp", Headers: map[string]string{"charge-point-id": "CP3", "ocpp-version": "2.0"}, Payload: []byte(`[2, "1", "CostUpdated", {"totalCost": 1, "transactionId": "1"}]`)},
+		{Topic: "ocpp", Headers: map[string]string{"charge-point-id": "CP3"}, Payload: []byte(`not json`)},
+		{Topic: "ocpp", Headers: map[string]string{"charge-point-id": "CP3"}, Payload: []byte(`[2, "2", "Unknown", {}]`)},
+	}}
+
+	consumer := NewConsumer(zap.NewNop(), s.service, bus, ocpp.OcppContext{Version: ocpp.V16})
+	s.Require().NoError(consumer.Run(context.Background()))
+
+	verdicts := s.verdicts(bus)
+	s.Require().Len(verdicts, 6)
+
+	s.Equal("CP1", verdicts[0].ChargePointId)
+	s.Equal("inbound", verdicts[0].Direction)
+	s.Equal("Heartbeat", verdicts[0].Action)
+	s.True(verdicts[0].Valid)
+
+	s.Equal("CP2", verdicts[1].ChargePointId)
+	s.False(verdicts[1].Valid)
+	s.NotEmpty(verdicts[1].Errors)
+
+	// Paired with CP1's request
+	s.Equal("CP1", verdicts[2].ChargePointId)
+	s.Equal("outbound", verdicts[2].Direction)
+	s.Equal("Heartbeat", verdicts[2].Action)
+	s.True(verdicts[2].Valid, verdicts[2].Errors)
+
+	s.Equal("2.0.1", verdicts[3].OcppVersion)
+	s.True(verdicts[3].Valid, verdicts[3].Errors)
+
+	s.False(verdicts[4].Parsable)
+	s.False(verdicts[4].Valid)
+
+	s.True(verdicts[5].Parsable)
+	s.False(verdicts[5].Valid)
+	s.NotEmpty(verdicts[5].Errors)
+}
+
+func (s *consumerTestSuite) TestRun_OnlyInvalid() {
+	bus := &fakeBus{messages: []Message{
+		{Headers: map[string]string{"cp": "CP1"}, Payload: []byte(`[2, "1", "Heartbeat", {}]`)},
+		{Headers: map[string]string{"cp": "CP1"}, Payload: []byte(`[2, "2", "Heartbeat", {"unexpected": true}]`)},
+	}}
+
+	consumer := NewConsumer(zap.NewNop(), s.service, bus, ocpp.OcppContext{Version: ocpp.V16},
+		WithOnlyInvalid(true),
+		WithChargePointIdHeader("cp"),
+	)
+	s.Require().NoError(consumer.Run(context.Background()))
+
+	verdicts := s.verdicts(bus)
+	s.Require().Len(verdicts, 1)
+	s.Equal("CP1", verdicts[0].ChargePointId)
+	s.Equal("2", verdicts[0].UniqueId)
+	s.Equal("CP1", bus.published[0].Headers["cp"])
+}
+
+func TestConsumer(t *testing.T) {
+	suite.Run(t, new(consumerTestSuite))
+}
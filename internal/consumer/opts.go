@@ -0,0 +1,38 @@
+package consumer
+
+type options struct {
+	chargePointIdHeader string
+	directionHeader     string
+	versionHeader       string
+	onlyInvalid         bool
+}
+
+type Option func(*options)
+
+// WithChargePointIdHeader sets the header holding the ID of the charge point that sent or received the frame.
+func WithChargePointIdHeader(header string) Option {
+	return func(o *options) {
+		o.chargePointIdHeader = header
+	}
+}
+
+// WithDirectionHeader sets the header holding the direction of the frame (e.g. "inbound" or "outbound").
+func WithDirectionHeader(header string) Option {
+	return func(o *options) {
+		o.directionHeader = header
+	}
+}
+
+// WithVersionHeader sets the header holding the OCPP version of the charge point's connection.
+func WithVersionHeader(header string) Option {
+	return func(o *options) {
+		o.versionHeader = header
+	}
+}
+
+// WithOnlyInvalid publishes verdicts only for frames that are not valid.
+func WithOnlyInvalid(onlyInvalid bool) Option {
+	return func(o *options) {
+		o.onlyInvalid = onlyInvalid
+	}
+}
@@ -0,0 +1,22 @@
+package grpc_server
+
+type options struct {
+	address        string
+	maxMessageSize int
+}
+
+type Option func(*options)
+
+// WithAddress sets the address the server listens on (e.g. ":9090").
+func WithAddress(address string) Option {
+	return func(o *options) {
+		o.address = address
+	}
+}
+
+// WithMaxMessageSize limits the size of received and sent messages, in bytes.
+func WithMaxMessageSize(size int) Option {
+	return func(o *options) {
+		o.maxMessageSize = size
+	}
+}
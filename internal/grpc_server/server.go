@@ -0,0 +1,86 @@
+package grpc_server
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	chargeflowv1 "github.com/ChargePi/chargeflow/gen/proto/chargeflow/v1"
+	"github.com/ChargePi/chargeflow/internal/validation"
+)
+
+// Server exposes the validation service as the chargeflow.v1.ChargeflowValidator gRPC service.
+type Server struct {
+	chargeflowv1.UnimplementedChargeflowValidatorServer
+
+	logger  *zap.Logger
+	config  options
+	service *validation.Service
+}
+
+func NewServer(logger *zap.Logger, service *validation.Service, opts ...Option) *Server {
+	config := options{
+		address:        ":9090",
+		maxMessageSize: 10 << 20, // 10 MiB
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &Server{
+		logger:  logger.Named("grpc_server"),
+		config:  config,
+		service: service,
+	}
+}
+
+// Register registers the validator, health and reflection services on the gRPC server.
+func (s *Server) Register(grpcServer *grpc.Server) {
+	chargeflowv1.RegisterChargeflowValidatorServer(grpcServer, s)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(chargeflowv1.ChargeflowValidator_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	reflection.Register(grpcServer)
+}
+
+// Run starts listening on the configured address and blocks until the context is cancelled,
+// after which the server is gracefully stopped.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.config.address)
+	if err != nil {
+		return errors.Wrapf(err, "unable to listen on %s", s.config.address)
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(s.config.maxMessageSize),
+		grpc.MaxSendMsgSize(s.config.maxMessageSize),
+	)
+	s.Register(grpcServer)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	s.logger.Info("gRPC server started", zap.String("address", listener.Addr().String()))
+
+	select {
+	case err := <-serveErr:
+		return errors.Wrap(err, "gRPC server stopped unexpectedly")
+	case <-ctx.Done():
+	}
+
+	s.logger.Info("Shutting down gRPC server")
+	grpcServer.GracefulStop()
+
+	return nil
+}
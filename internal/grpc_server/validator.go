@@ -0,0 +1,183 @@
+package grpc_server
+
+import (
+	"context"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	chargeflowv1 "github.com/ChargePi/chargeflow/gen/proto/chargeflow/v1"
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+const (
+	requestKey  = "request"
+	responseKey = "response"
+)
+
+// Validate validates a batch of messages and returns the aggregated report.
+func (s *Server) Validate(_ context.Context, req *chargeflowv1.ValidateRequest) (*chargeflowv1.ValidateResponse, error) {
+	octx, err := toOcppContext(req.GetOcppContext())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if len(req.GetMessages()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one message must be provided")
+	}
+
+	for i, message := range req.GetMessages() {
+		if strings.TrimSpace(message) == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "message %d is empty", i)
+		}
+	}
+
+	// The request itself is valid, so failing to validate its messages is a failure of the service. The results are
+	// returned to the client, logging them would flood the logs of the server.
+	validationReport, err := s.service.Validate(validation.Request{
+		OcppContext: octx,
+		Messages:    req.GetMessages(),
+		Console:     validation.ConsoleQuiet,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &chargeflowv1.ValidateResponse{Report: toProtoReport(validationReport)}, nil
+}
+
+// ValidateStream validates frames as they arrive. Each charge point gets its own validation session,
+// so responses are only paired with requests of the same charge point.
+func (s *Server) ValidateStream(stream chargeflowv1.ChargeflowValidator_ValidateStreamServer) error {
+	sessions := make(map[string]*validation.Session)
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	for {
+		req, err := stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			return closeSessions(stream, sessions)
+		case err != nil:
+			return err
+		}
+
+		if req.GetOcppContext() != nil {
+			octx, err = toOcppContext(req.GetOcppContext())
+			if err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+
+		chargePointId := req.GetChargePointId()
+		session, found := sessions[chargePointId]
+		if !found {
+			session = s.service.NewSession()
+			sessions[chargePointId] = session
+		}
+
+		response := &chargeflowv1.ValidateStreamResponse{ChargePointId: chargePointId}
+
+		result, err := session.ValidateFrame(octx, req.GetFrame())
+		if err != nil {
+			// The frame could not be validated (e.g. no schema for the action), which should not end the stream.
+			s.logger.Debug("Unable to validate frame", zap.String("chargePointId", chargePointId), zap.Error(err))
+			response.Parsable = true
+			response.Errors = []string{err.Error()}
+		} else {
+			response.UniqueId = result.UniqueId
+			response.Action = result.Action
+			response.MessageType = chargeflowv1.MessageType(result.MessageType)
+			response.Parsable = result.Parsable
+			response.Valid = result.Valid
+			response.Errors = result.Errors
+			response.Sequence = toProtoSequence(result.Sequence)
+		}
+
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+// closeSessions sends the remaining sequence results of every charge point once the client closed the stream.
+func closeSessions(stream chargeflowv1.ChargeflowValidator_ValidateStreamServer, sessions map[string]*validation.Session) error {
+	for _, chargePointId := range slices.Sorted(maps.Keys(sessions)) {
+		sequence := sessions[chargePointId].Close()
+		if len(sequence) == 0 {
+			continue
+		}
+
+		response := &chargeflowv1.ValidateStreamResponse{ChargePointId: chargePointId, Sequence: toProtoSequence(sequence)}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toProtoSequence(results []validation.SequenceResult) []*chargeflowv1.SequenceResult {
+	var sequence []*chargeflowv1.SequenceResult
+	for _, result := range results {
+		sequence = append(sequence, &chargeflowv1.SequenceResult{
+			UniqueId: result.UniqueId,
+			Action:   result.Action,
+			Errors:   result.Errors,
+		})
+	}
+
+	return sequence
+}
+
+// toOcppContext converts the protobuf OCPP context to ocpp.OcppContext, defaulting to OCPP 1.6.
+func toOcppContext(c *chargeflowv1.OcppContext) (ocpp.OcppContext, error) {
+	version := ocpp.V16
+	if c.GetVersion() != "" {
+		var err error
+		version, err = ocpp.ParseVersion(c.GetVersion())
+		if err != nil {
+			return ocpp.OcppContext{}, err
+		}
+	}
+
+	return ocpp.OcppContext{
+		Version: version,
+		Vendor:  c.GetVendor(),
+		Model:   c.GetModel(),
+	}, nil
+}
+
+func toProtoReport(r *report.Report) *chargeflowv1.Report {
+	protoReport := &chargeflowv1.Report{
+		InvalidMessages:     make(map[string]*chargeflowv1.MessageErrors, len(r.InvalidMessages)),
+		NonParsableMessages: make(map[string]*chargeflowv1.Errors, len(r.NonParsableMessages)),
+		Statistics: &chargeflowv1.Statistics{
+			ValidRequests:      int64(r.Statistics.ValidRequests),
+			ValidResponses:     int64(r.Statistics.ValidResponses),
+			InvalidRequests:    int64(r.Statistics.InvalidRequests),
+			InvalidResponses:   int64(r.Statistics.InvalidResponses),
+			UnparsableMessages: int64(r.Statistics.UnparsableMessages),
+		},
+	}
+
+	for messageId, requestResponse := range r.InvalidMessages {
+		protoReport.InvalidMessages[messageId] = &chargeflowv1.MessageErrors{
+			Request:  requestResponse[requestKey],
+			Response: requestResponse[responseKey],
+		}
+	}
+
+	for key, errs := range r.NonParsableMessages {
+		protoReport.NonParsableMessages[key] = &chargeflowv1.Errors{Errors: errs}
+	}
+
+	return protoReport
+}
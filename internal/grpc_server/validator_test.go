@@ -0,0 +1,171 @@
+package grpc_server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	chargeflowv1 "github.com/ChargePi/chargeflow/gen/proto/chargeflow/v1"
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
+)
+
+const (
+	heartbeatRequestSchema  = `{"type": "object", "additionalProperties": false}`
+	heartbeatResponseSchema = `{"type": "object", "properties": {"currentTime": {"type": "string"}}, "required": ["currentTime"]}`
+
+	validRequest    = `[2, "1", "Heartbeat", {}]`
+	validResponse   = `[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`
+	invalidResponse = `[3, "2", {}]`
+	invalidRequest  = `[2, "2", "Heartbeat", {"unexpected": true}]`
+)
+
+type validatorTestSuite struct {
+	suite.Suite
+	grpcServer *grpc.Server
+	conn       *grpc.ClientConn
+	client     chargeflowv1.ChargeflowValidatorClient
+}
+
+func (s *validatorTestSuite) SetupSuite() {
+	logger := zap.NewNop()
+	registry := file_registry.NewFileSchemaRegistry(logger)
+
+	for action, schema := range map[string]string{
+		"HeartbeatRequest":  heartbeatRequestSchema,
+		"HeartbeatResponse": heartbeatResponseSchema,
+	} {
+		err := registry.RegisterSchema(context.Background(), schema_registry.CreateSchemaRequest{
+			OcppContext: ocpp.OcppContext{Version: ocpp.V16},
+			Action:      action,
+			Schema:      json.RawMessage(schema),
+		})
+		s.Require().NoError(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	s.grpcServer = grpc.NewServer()
+	NewServer(logger, validation.NewService(logger, registry)).Register(s.grpcServer)
+
+	go func() {
+		_ = s.grpcServer.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+
+	s.conn = conn
+	s.client = chargeflowv1.NewChargeflowValidatorClient(conn)
+}
+
+func (s *validatorTestSuite) TearDownSuite() {
+	_ = s.conn.Close()
+	s.grpcServer.Stop()
+}
+
+func (s *validatorTestSuite) TestValidate() {
+	ctx := context.Background()
+
+	response, err := s.client.Validate(ctx, &chargeflowv1.ValidateRequest{
+		OcppContext: &chargeflowv1.OcppContext{Version: "1.6"},
+		Messages:    []string{validRequest, validResponse, invalidRequest, `{"invalid": "json"}`},
+	})
+	s.Require().NoError(err)
+
+	report := response.GetReport()
+	s.EqualValues(1, report.GetStatistics().GetValidRequests())
+	s.EqualValues(1, report.GetStatistics().GetInvalidRequests())
+	s.EqualValues(1, report.GetStatistics().GetValidResponses())
+	s.EqualValues(1, report.GetStatistics().GetUnparsableMessages())
+	s.Contains(report.GetInvalidMessages(), "2")
+	s.NotEmpty(report.GetInvalidMessages()["2"].GetRequest())
+
+	_, err = s.client.Validate(ctx, &chargeflowv1.ValidateRequest{
+		OcppContext: &chargeflowv1.OcppContext{Version: "9.9"},
+		Messages:    []string{validRequest},
+	})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = s.client.Validate(ctx, &chargeflowv1.ValidateRequest{})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = s.client.Validate(ctx, &chargeflowv1.ValidateRequest{
+		OcppContext: &chargeflowv1.OcppContext{Version: "1.6"},
+		Messages:    []string{validRequest, " "},
+	})
+	s.Equal(codes.InvalidArgument, status.Code(err))
+
+	// There is no schema for the action, so the service can't validate the message
+	_, err = s.client.Validate(ctx, &chargeflowv1.ValidateRequest{
+		OcppContext: &chargeflowv1.OcppContext{Version: "1.6"},
+		Messages:    []string{`[2, "3", "Authorize", {"idTag": "ABC123"}]`},
+	})
+	s.Equal(codes.Internal, status.Code(err))
+}
+
+func (s *validatorTestSuite) TestValidateStream() {
+	stream, err := s.client.ValidateStream(context.Background())
+	s.Require().NoError(err)
+
+	frames := []*chargeflowv1.ValidateStreamRequest{
+		{OcppContext: &chargeflowv1.OcppContext{Version: "1.6"}, ChargePointId: "CP1", Frame: validRequest},
+		{ChargePointId: "CP2", Frame: invalidRequest},
+		{ChargePointId: "CP1", Frame: validResponse},
+		{ChargePointId: "CP1", Frame: invalidResponse},
+		{ChargePointId: "CP2", Frame: invalidResponse},
+		{ChargePointId: "CP1", Frame: `[2, "3", "Unknown", {}]`},
+	}
+
+	expected := []struct {
+		chargePointId string
+		action        string
+		valid         bool
+		hasErrors     bool
+	}{
+		{chargePointId: "CP1", action: "Heartbeat", valid: true},
+		{chargePointId: "CP2", action: "Heartbeat", hasErrors: true},
+		{chargePointId: "CP1", action: "Heartbeat", valid: true},
+		// The request with ID "2" was sent by CP2, so CP1's response cannot be paired
+		{chargePointId: "CP1", hasErrors: true},
+		{chargePointId: "CP2", action: "Heartbeat", hasErrors: true},
+		// No schema for the action, but the stream stays open
+		{chargePointId: "CP1", hasErrors: true},
+	}
+
+	for _, frame := range frames {
+		s.Require().NoError(stream.Send(frame))
+	}
+	s.Require().NoError(stream.CloseSend())
+
+	for _, exp := range expected {
+		response, err := stream.Recv()
+		s.Require().NoError(err)
+		s.Equal(exp.chargePointId, response.GetChargePointId())
+		s.Equal(exp.action, response.GetAction())
+		s.Equal(exp.valid, response.GetValid())
+		s.Equal(exp.hasErrors, len(response.GetErrors()) > 0, response.GetErrors())
+	}
+
+	_, err = stream.Recv()
+	s.ErrorIs(err, io.EOF)
+}
+
+func TestValidatorServer(t *testing.T) {
+	suite.Run(t, new(validatorTestSuite))
+}
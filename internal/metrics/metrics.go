@@ -0,0 +1,96 @@
+// Package metrics exposes Prometheus metrics about the messages validated by the long-running modes.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/remote_registry"
+)
+
+const namespace = "chargeflow"
+
+// Metrics collects validation metrics in its own Prometheus registry. It implements validation.Observer.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	messages *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewMetrics creates the validation metrics, along with the standard Go runtime and process metrics.
+func NewMetrics() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		registry: registry,
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_total",
+			Help:      "Number of validated OCPP messages by result (valid, invalid, unparsable or error).",
+		}, []string{"ocpp_version", "vendor", "model", "action", "result"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "validation_errors_total",
+			Help:      "Number of validation errors by category (e.g. the failing JSON schema keyword).",
+		}, []string{"ocpp_version", "vendor", "model", "action", "category"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "validation_duration_seconds",
+			Help:      "Duration of validating a single OCPP message.",
+			Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 10),
+		}, []string{"ocpp_version", "action"}),
+	}
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.messages,
+		m.errors,
+		m.duration,
+	)
+
+	return m
+}
+
+// Observe records the outcome of validating a single message.
+func (m *Metrics) Observe(observation validation.Observation) {
+	octx := observation.OcppContext
+	version := octx.Version.Name()
+
+	m.messages.WithLabelValues(version, octx.Vendor, octx.Model, observation.Action, string(observation.Outcome)).Inc()
+
+	for _, category := range observation.Categories {
+		m.errors.WithLabelValues(version, octx.Vendor, octx.Model, observation.Action, string(category)).Inc()
+	}
+
+	if observation.Duration > 0 {
+		m.duration.WithLabelValues(version, observation.Action).Observe(observation.Duration.Seconds())
+	}
+}
+
+// RegisterCache exposes the hit and miss counts of a remote registry schema cache.
+func (m *Metrics) RegisterCache(cache *remote_registry.MemoryCache) {
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "schema_cache_hits_total",
+			Help:      "Number of schema lookups served from the remote registry cache.",
+		}, func() float64 { return float64(cache.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "schema_cache_misses_total",
+			Help:      "Number of schema lookups not found in the remote registry cache, including expired entries.",
+		}, func() float64 { return float64(cache.Stats().Misses) }),
+	)
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/remote_registry"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := NewMetrics()
+	octx := ocpp.OcppContext{Version: ocpp.V16, Vendor: "Acme", Model: "X1"}
+
+	m.Observe(validation.Observation{OcppContext: octx, Action: "StatusNotification", Outcome: validation.OutcomeValid, Duration: time.Millisecond})
+	m.Observe(validation.Observation{
+		OcppContext: octx,
+		Action:      "StatusNotification",
+		Outcome:     validation.OutcomeInvalid,
+		Categories:  []validator.ErrorCategory{"required", "enum", "required"},
+		Duration:    time.Millisecond,
+	})
+	m.Observe(validation.Observation{OcppContext: octx, Outcome: validation.OutcomeUnparsable})
+
+	require.EqualValues(t, 1, testutil.ToFloat64(m.messages.WithLabelValues("1.6", "Acme", "X1", "StatusNotification", "valid")))
+	require.EqualValues(t, 1, testutil.ToFloat64(m.messages.WithLabelValues("1.6", "Acme", "X1", "StatusNotification", "invalid")))
+	require.EqualValues(t, 1, testutil.ToFloat64(m.messages.WithLabelValues("1.6", "Acme", "X1", "", "unparsable")))
+	require.EqualValues(t, 2, testutil.ToFloat64(m.errors.WithLabelValues("1.6", "Acme", "X1", "StatusNotification", "required")))
+	require.EqualValues(t, 1, testutil.ToFloat64(m.errors.WithLabelValues("1.6", "Acme", "X1", "StatusNotification", "enum")))
+	// Unparsable messages are not timed
+	require.Equal(t, 1, testutil.CollectAndCount(m.duration))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	ctx := context.Background()
+	m := NewMetrics()
+
+	cache := remote_registry.NewMemoryCache(time.Minute)
+	m.RegisterCache(cache)
+	_, _ = cache.Get(ctx, "ocpp-1.6-HeartbeatRequest")
+
+	m.Observe(validation.Observation{OcppContext: ocpp.OcppContext{Version: ocpp.V20}, Action: "Heartbeat", Outcome: validation.OutcomeValid, Duration: time.Millisecond})
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+
+	require.Contains(t, string(body), "chargeflow_schema_cache_hits_total 0")
+	require.Contains(t, string(body), "chargeflow_schema_cache_misses_total 1")
+	require.Contains(t, string(body), `chargeflow_validation_duration_seconds_count{action="Heartbeat",ocpp_version="2.0.1"} 1`)
+	require.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+)
+
+// OcppContext is the JSON representation of ocpp.OcppContext used by the API.
+type OcppContext struct {
+	Version string `json:"version"`
+	Vendor  string `json:"vendor,omitempty"`
+	Model   string `json:"model,omitempty"`
+}
+
+// ValidateRequest is the body of POST /validate. Either Message or Messages must be set.
+// Each message can be either the raw OCPP-J array or a JSON string containing it.
+type ValidateRequest struct {
+	OcppContext OcppContext       `json:"ocppContext"`
+	Message     json.RawMessage   `json:"message,omitempty"`
+	Messages    []json.RawMessage `json:"messages,omitempty"`
+}
+
+// ListSchemasResponse is the body returned by GET /schemas.
+type ListSchemasResponse struct {
+	Version string   `json:"version"`
+	Vendor  string   `json:"vendor,omitempty"`
+	Model   string   `json:"model,omitempty"`
+	Schemas []string `json:"schemas"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type statusResponse struct {
+	Status string `json:"status"`
+}
+
+// handleValidate validates a single message or a batch of messages sent as JSON.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var body ValidateRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.config.maxBodySize))
+	if err := decoder.Decode(&body); err != nil {
+		s.writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid request body"))
+		return
+	}
+
+	octx, err := toOcppContext(body.OcppContext)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rawMessages := body.Messages
+	if len(body.Message) > 0 {
+		rawMessages = append([]json.RawMessage{body.Message}, rawMessages...)
+	}
+
+	if len(rawMessages) == 0 {
+		s.writeError(w, http.StatusBadRequest, errors.New("either message or messages must be provided"))
+		return
+	}
+
+	messages := make([]string, 0, len(rawMessages))
+	for _, rawMessage := range rawMessages {
+		messages = append(messages, normalizeMessage(rawMessage))
+	}
+
+	s.validate(w, validation.Request{OcppContext: octx, Messages: messages})
+}
+
+// handleValidateFile validates newline-delimited messages, either uploaded as the "file" field of a
+// multipart form or sent as the raw request body. The OCPP context is read from the form or query.
+func (s *Server) handleValidateFile(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.config.maxBodySize)
+
+	var content []byte
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, errors.Wrap(err, "missing \"file\" form field"))
+			return
+		}
+		defer file.Close()
+
+		content, err = io.ReadAll(file)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, errors.Wrap(err, "unable to read uploaded file"))
+			return
+		}
+	default:
+		var err error
+		content, err = io.ReadAll(r.Body)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, errors.Wrap(err, "unable to read request body"))
+			return
+		}
+	}
+
+	octx, err := toOcppContext(OcppContext{
+		Version: r.FormValue("version"),
+		Vendor:  r.FormValue("vendor"),
+		Model:   r.FormValue("model"),
+	})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(bytes.TrimSpace(content)) == 0 {
+		s.writeError(w, http.StatusBadRequest, errors.New("file is empty"))
+		return
+	}
+
+	messages := strings.Split(strings.TrimRight(string(content), "\r\n"), "\n")
+	s.validate(w, validation.Request{OcppContext: octx, Messages: messages})
+}
+
+// handleListSchemas lists the schemas available for the requested OCPP context.
+func (s *Server) handleListSchemas(w http.ResponseWriter, r *http.Request) {
+	lister, ok := s.registry.(schema_registry.SchemaLister)
+	if !ok {
+		s.writeError(w, http.StatusNotImplemented, errors.Errorf("listing schemas is not supported by the %s registry", s.registry.Type()))
+		return
+	}
+
+	query := r.URL.Query()
+	octx, err := toOcppContext(OcppContext{
+		Version: query.Get("version"),
+		Vendor:  query.Get("vendor"),
+		Model:   query.Get("model"),
+	})
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	schemas, err := lister.ListSchemas(r.Context(), schema_registry.ListSchemasRequest{OcppContext: octx})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, errors.Wrap(err, "unable to list schemas"))
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, ListSchemasResponse{
+		Version: octx.Version.Name(),
+		Vendor:  octx.Vendor,
+		Model:   octx.Model,
+		Schemas: schemas,
+	})
+}
+
+// handleHealth reports whether the process is alive.
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, statusResponse{Status: "ok"})
+}
+
+// handleReady reports whether the server is accepting traffic.
+func (s *Server) handleReady(w http.ResponseWriter, _ *http.Request) {
+	if !s.ready.Load() {
+		s.writeJSON(w, http.StatusServiceUnavailable, statusResponse{Status: "not ready"})
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, statusResponse{Status: "ready"})
+}
+
+func (s *Server) validate(w http.ResponseWriter, req validation.Request) {
+	// The results are returned to the client, logging them would flood the logs of the server
+	req.Console = validation.ConsoleQuiet
+
+	validationReport, err := s.service.Validate(req)
+	if err != nil {
+		s.writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, validationReport)
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.Warn("Unable to write response", zap.Error(err))
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	s.logger.Debug("Request failed", zap.Int("status", status), zap.Error(err))
+	s.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// toOcppContext converts the API representation to ocpp.OcppContext, defaulting to OCPP 1.6.
+func toOcppContext(c OcppContext) (ocpp.OcppContext, error) {
+	version := ocpp.V16
+	if c.Version != "" {
+		var err error
+		version, err = ocpp.ParseVersion(c.Version)
+		if err != nil {
+			return ocpp.OcppContext{}, err
+		}
+	}
+
+	return ocpp.OcppContext{
+		Version: version,
+		Vendor:  c.Vendor,
+		Model:   c.Model,
+	}, nil
+}
+
+// normalizeMessage returns the OCPP-J message as a string. Messages sent as JSON strings are
+// unquoted, while raw JSON arrays are passed through as-is.
+func normalizeMessage(raw json.RawMessage) string {
+	var message string
+	if err := json.Unmarshal(raw, &message); err == nil {
+		return message
+	}
+
+	return string(raw)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+type options struct {
+	address        string
+	maxBodySize    int64
+	readTimeout    time.Duration
+	metricsHandler http.Handler
+}
+
+type Option func(*options)
+
+// WithAddress sets the address the server listens on (e.g. ":8080").
+func WithAddress(address string) Option {
+	return func(o *options) {
+		o.address = address
+	}
+}
+
+// WithMaxBodySize limits the size of request bodies, in bytes.
+func WithMaxBodySize(size int64) Option {
+	return func(o *options) {
+		o.maxBodySize = size
+	}
+}
+
+// WithReadTimeout sets the maximum duration for reading an entire request.
+func WithReadTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.readTimeout = d
+	}
+}
+
+// WithMetricsHandler serves the given handler (e.g. Prometheus metrics) on GET /metrics.
+func WithMetricsHandler(handler http.Handler) Option {
+	return func(o *options) {
+		o.metricsHandler = handler
+	}
+}
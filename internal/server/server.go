@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// Server exposes the validation service over HTTP.
+type Server struct {
+	logger   *zap.Logger
+	config   options
+	service  *validation.Service
+	registry schema_registry.SchemaRegistry
+
+	// ready is set once the server is listening and cleared when it starts shutting down.
+	ready atomic.Bool
+}
+
+func NewServer(
+	logger *zap.Logger,
+	service *validation.Service,
+	registry schema_registry.SchemaRegistry,
+	opts ...Option,
+) *Server {
+	config := options{
+		address:     ":8080",
+		maxBodySize: 10 << 20, // 10 MiB
+		readTimeout: 30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &Server{
+		logger:   logger.Named("http_server"),
+		config:   config,
+		service:  service,
+		registry: registry,
+	}
+}
+
+// Handler returns the HTTP handler with all the API routes registered.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate", s.handleValidate)
+	mux.HandleFunc("POST /validate/file", s.handleValidateFile)
+	mux.HandleFunc("GET /schemas", s.handleListSchemas)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+
+	if s.config.metricsHandler != nil {
+		mux.Handle("GET /metrics", s.config.metricsHandler)
+	}
+
+	return mux
+}
+
+// Run starts listening on the configured address and blocks until the context is cancelled,
+// after which the server is gracefully shut down.
+func (s *Server) Run(ctx context.Context) error {
+	httpServer := &http.Server{
+		Addr:              s.config.address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: s.config.readTimeout,
+		ReadTimeout:       s.config.readTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	listener, err := net.Listen("tcp", s.config.address)
+	if err != nil {
+		return errors.Wrapf(err, "unable to listen on %s", s.config.address)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	s.ready.Store(true)
+	s.logger.Info("HTTP server started", zap.String("address", listener.Addr().String()))
+
+	select {
+	case err := <-serveErr:
+		s.ready.Store(false)
+		return errors.Wrap(err, "HTTP server stopped unexpectedly")
+	case <-ctx.Done():
+	}
+
+	s.ready.Store(false)
+	s.logger.Info("Shutting down HTTP server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return errors.Wrap(err, "unable to gracefully shut down HTTP server")
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/ChargePi/chargeflow/internal/metrics"
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/report"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
+)
+
+const (
+	bootNotificationRequestSchema = `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"chargePointVendor": {"type": "string", "maxLength": 20},
+			"chargePointModel": {"type": "string", "maxLength": 20}
+		},
+		"additionalProperties": false,
+		"required": ["chargePointVendor", "chargePointModel"]
+	}`
+
+	bootNotificationResponseSchema = `{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"type": "object",
+		"properties": {
+			"status": {"type": "string", "enum": ["Accepted", "Pending", "Rejected"]}
+		},
+		"additionalProperties": false,
+		"required": ["status"]
+	}`
+
+	validRequest   = `[2, "1234", "BootNotification", {"chargePointVendor": "TestVendor", "chargePointModel": "TestModel"}]`
+	validResponse  = `[3, "1234", {"status": "Accepted"}]`
+	invalidRequest = `[2, "5678", "BootNotification", {"chargePointVendor": "TestVendor"}]`
+)
+
+type serverTestSuite struct {
+	suite.Suite
+	handler http.Handler
+	// logs are the errors logged by the validation service
+	logs *observer.ObservedLogs
+}
+
+func (s *serverTestSuite) SetupSuite() {
+	logger := zap.NewNop()
+	core, logs := observer.New(zapcore.ErrorLevel)
+	s.logs = logs
+
+	registry := file_registry.NewFileSchemaRegistry(logger)
+
+	for action, schema := range map[string]string{
+		"BootNotificationRequest":  bootNotificationRequestSchema,
+		"BootNotificationResponse": bootNotificationResponseSchema,
+	} {
+		err := registry.RegisterSchema(context.Background(), schema_registry.CreateSchemaRequest{
+			OcppContext: ocpp.OcppContext{Version: ocpp.V16},
+			Action:      action,
+			Schema:      json.RawMessage(schema),
+		})
+		s.Require().NoError(err)
+	}
+
+	m := metrics.NewMetrics()
+	s.handler = NewServer(
+		logger,
+		validation.NewService(zap.New(core), registry, validation.WithObserver(m)),
+		registry,
+		WithMetricsHandler(m.Handler()),
+	).Handler()
+}
+
+func (s *serverTestSuite) do(method, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	recorder := httptest.NewRecorder()
+	s.handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (s *serverTestSuite) TestValidate() {
+	tests := []struct {
+		name             string
+		body             string
+		expectedStatus   int
+		expectedInvalid  []string
+		expectedRequests int
+	}{
+		{
+			name:             "Single valid message as raw JSON",
+			body:             `{"ocppContext": {"version": "1.6"}, "message": ` + validRequest + `}`,
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 1,
+		},
+		{
+			name:             "Single valid message as string",
+			body:             `{"ocppContext": {"version": "1.6"}, "message": ` + quote(validRequest) + `}`,
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 1,
+		},
+		{
+			name:             "Batch with an invalid message",
+			body:             `{"ocppContext": {"version": "1.6"}, "messages": [` + validRequest + `, ` + validResponse + `, ` + invalidRequest + `]}`,
+			expectedStatus:   http.StatusOK,
+			expectedInvalid:  []string{"5678"},
+			expectedRequests: 2,
+		},
+		{
+			name:           "Invalid OCPP version",
+			body:           `{"ocppContext": {"version": "9.9"}, "message": ` + validRequest + `}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "No messages",
+			body:           `{"ocppContext": {"version": "1.6"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Malformed body",
+			body:           `{"ocppContext":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown action",
+			body:           `{"ocppContext": {"version": "1.6"}, "message": [2, "1", "Heartbeat", {}]}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			recorder := s.do(http.MethodPost, "/validate", "application/json", tt.body)
+			s.Equal(tt.expectedStatus, recorder.Code, recorder.Body.String())
+
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var validationReport report.Report
+			s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &validationReport))
+			s.Equal(tt.expectedRequests, validationReport.Statistics.GetTotalRequest())
+			s.Len(validationReport.InvalidMessages, len(tt.expectedInvalid))
+			for _, id := range tt.expectedInvalid {
+				s.Contains(validationReport.InvalidMessages, id)
+			}
+		})
+	}
+}
+
+func (s *serverTestSuite) TestValidateDoesNotLogResults() {
+	s.logs.TakeAll()
+
+	recorder := s.do(http.MethodPost, "/validate", "application/json", `{"ocppContext": {"version": "1.6"}, "message": `+invalidRequest+`}`)
+	s.Equal(http.StatusOK, recorder.Code)
+
+	// Invalid messages are only returned to the client
+	s.Empty(s.logs.TakeAll())
+}
+
+func (s *serverTestSuite) TestValidateFile() {
+	s.Run("Raw body", func() {
+		body := strings.Join([]string{validRequest, validResponse, invalidRequest}, "\n")
+		recorder := s.do(http.MethodPost, "/validate/file?version=1.6", "text/plain", body)
+		s.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+
+		var validationReport report.Report
+		s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &validationReport))
+		s.Equal(1, validationReport.Statistics.ValidRequests)
+		s.Equal(1, validationReport.Statistics.InvalidRequests)
+		s.Equal(1, validationReport.Statistics.ValidResponses)
+	})
+
+	s.Run("Empty body", func() {
+		recorder := s.do(http.MethodPost, "/validate/file?version=1.6", "text/plain", "")
+		s.Equal(http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func (s *serverTestSuite) TestListSchemas() {
+	recorder := s.do(http.MethodGet, "/schemas?version=1.6", "", "")
+	s.Require().Equal(http.StatusOK, recorder.Code)
+
+	var response ListSchemasResponse
+	s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+	s.Equal([]string{"BootNotificationRequest", "BootNotificationResponse"}, response.Schemas)
+
+	recorder = s.do(http.MethodGet, "/schemas?version=2.1", "", "")
+	s.Require().Equal(http.StatusOK, recorder.Code)
+	s.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &response))
+	s.Empty(response.Schemas)
+}
+
+func (s *serverTestSuite) TestHealth() {
+	s.Equal(http.StatusOK, s.do(http.MethodGet, "/healthz", "", "").Code)
+	s.Equal(http.StatusServiceUnavailable, s.do(http.MethodGet, "/readyz", "", "").Code)
+}
+
+func (s *serverTestSuite) TestMetrics() {
+	s.Require().Equal(http.StatusOK, s.do(http.MethodPost, "/validate", "application/json", `{"ocppContext": {"version": "1.6"}, "message": `+invalidRequest+`}`).Code)
+
+	recorder := s.do(http.MethodGet, "/metrics", "", "")
+	s.Require().Equal(http.StatusOK, recorder.Code)
+	s.Contains(recorder.Body.String(), `chargeflow_messages_total{action="BootNotification",model="",ocpp_version="1.6",result="invalid",vendor=""}`)
+	s.Contains(recorder.Body.String(), `chargeflow_validation_errors_total{action="BootNotification",category="required"`)
+}
+
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
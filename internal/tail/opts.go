@@ -0,0 +1,26 @@
+package tail
+
+import "time"
+
+type options struct {
+	pollInterval time.Duration
+	fromEnd      bool
+}
+
+type Option func(*options)
+
+// WithPollInterval sets how often the file is checked for new lines, truncation and rotation. A non-positive
+// interval falls back to the default of 250ms.
+func WithPollInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = interval
+	}
+}
+
+// WithFromEnd skips the existing content of the file, only following lines appended afterward.
+// Files that appear after a rotation are always read from the start.
+func WithFromEnd(fromEnd bool) Option {
+	return func(o *options) {
+		o.fromEnd = fromEnd
+	}
+}
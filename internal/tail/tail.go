@@ -0,0 +1,209 @@
+// Package tail follows a growing file line by line, like `tail -F`.
+package tail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Line is a complete line read from the followed file.
+type Line struct {
+	// Number of the line within the current file, starting at 1. It is reset when the file is truncated or rotated.
+	Number int
+	Text   string
+}
+
+// Tailer follows a file by polling it. It handles:
+//   - the file not existing yet (it waits for it to be created),
+//   - truncation (e.g. copytruncate log rotation), after which the file is read from the start,
+//   - rotation (the file is renamed or removed and a new one is created), after which the rest of the old file
+//     is read before switching to the new one.
+//
+// Lines are only emitted once they are terminated by a newline, so partially written lines are never split.
+type Tailer struct {
+	logger *zap.Logger
+	path   string
+	config options
+}
+
+// defaultPollInterval is used when no poll interval or a non-positive one is set.
+const defaultPollInterval = 250 * time.Millisecond
+
+func NewTailer(logger *zap.Logger, path string, opts ...Option) *Tailer {
+	config := options{
+		pollInterval: defaultPollInterval,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.pollInterval <= 0 {
+		config.pollInterval = defaultPollInterval
+	}
+
+	return &Tailer{
+		logger: logger.Named("tail").With(zap.String("file", path)),
+		path:   path,
+		config: config,
+	}
+}
+
+// followedFile is the state of the currently followed file.
+type followedFile struct {
+	file   *os.File
+	info   os.FileInfo
+	reader *bufio.Reader
+	// offset is the number of bytes read from the file.
+	offset int64
+	line   int
+	// partial holds the last read line, until it is terminated by a newline.
+	partial strings.Builder
+}
+
+// Run follows the file until the context is canceled, calling handle for every complete line.
+func (t *Tailer) Run(ctx context.Context, handle func(Line)) error {
+	ticker := time.NewTicker(t.config.pollInterval)
+	defer ticker.Stop()
+
+	current, err := t.waitForFile(ctx, ticker, t.config.fromEnd)
+	if current == nil || err != nil {
+		return err
+	}
+	defer func() { _ = current.file.Close() }()
+
+	for {
+		if err := t.drain(current, handle); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(t.path)
+		switch {
+		case err != nil:
+			// The file was removed (e.g. during rotation); keep reading the old one until a new file appears.
+			continue
+		case !os.SameFile(info, current.info):
+			t.logger.Info("File rotated, following the new file")
+
+			// Read anything written to the old file before it was rotated.
+			if err := t.drain(current, handle); err != nil {
+				return err
+			}
+			t.flushPartial(current, handle)
+			_ = current.file.Close()
+
+			next, err := t.open(false)
+			if err != nil {
+				// The new file might have been rotated again already; retry on the next tick.
+				t.logger.Warn("Unable to open the rotated file", zap.Error(err))
+				continue
+			}
+			current = next
+		case info.Size() < current.offset:
+			t.logger.Info("File truncated, reading from the start")
+
+			if _, err := current.file.Seek(0, io.SeekStart); err != nil {
+				return errors.Wrap(err, "unable to seek to the start of the truncated file")
+			}
+			current.reader.Reset(current.file)
+			current.offset = 0
+			current.line = 0
+			current.partial.Reset()
+		}
+	}
+}
+
+// waitForFile opens the file, waiting until it exists. It returns nil if the context is canceled first.
+func (t *Tailer) waitForFile(ctx context.Context, ticker *time.Ticker, fromEnd bool) (*followedFile, error) {
+	for {
+		current, err := t.open(fromEnd)
+		switch {
+		case err == nil:
+			return current, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+
+		t.logger.Debug("Waiting for the file to be created")
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tailer) open(fromEnd bool) (*followedFile, error) {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to open file")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, errors.Wrap(err, "unable to stat file")
+	}
+
+	var offset int64
+	if fromEnd {
+		offset, err = file.Seek(0, io.SeekEnd)
+		if err != nil {
+			_ = file.Close()
+			return nil, errors.Wrap(err, "unable to seek to the end of the file")
+		}
+	}
+
+	return &followedFile{
+		file:   file,
+		info:   info,
+		reader: bufio.NewReader(file),
+		offset: offset,
+	}, nil
+}
+
+// drain reads all complete lines currently available in the file.
+func (t *Tailer) drain(current *followedFile, handle func(Line)) error {
+	for {
+		chunk, err := current.reader.ReadString('\n')
+		current.offset += int64(len(chunk))
+		current.partial.WriteString(chunk)
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return errors.Wrap(err, "unable to read file")
+		}
+
+		text := strings.TrimRight(current.partial.String(), "\r\n")
+		current.partial.Reset()
+		current.line++
+		handle(Line{Number: current.line, Text: text})
+	}
+}
+
+// flushPartial emits the last line of a file that will not be written to anymore, even if it is not terminated.
+func (t *Tailer) flushPartial(current *followedFile, handle func(Line)) {
+	if current.partial.Len() == 0 {
+		return
+	}
+
+	current.line++
+	handle(Line{Number: current.line, Text: current.partial.String()})
+	current.partial.Reset()
+}
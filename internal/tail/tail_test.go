@@ -0,0 +1,137 @@
+package tail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+const (
+	pollInterval = 10 * time.Millisecond
+	waitTimeout  = 2 * time.Second
+)
+
+type tailTestSuite struct {
+	suite.Suite
+	path   string
+	lines  chan Line
+	cancel context.CancelFunc
+	done   chan error
+}
+
+func (s *tailTestSuite) SetupTest() {
+	s.path = filepath.Join(s.T().TempDir(), "csms.log")
+	s.lines = make(chan Line, 100)
+	s.done = make(chan error, 1)
+}
+
+func (s *tailTestSuite) TearDownTest() {
+	if s.cancel != nil {
+		s.cancel()
+		s.Require().NoError(<-s.done)
+		s.cancel = nil
+	}
+}
+
+func (s *tailTestSuite) start(opts ...Option) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	tailer := NewTailer(zap.NewNop(), s.path, append([]Option{WithPollInterval(pollInterval)}, opts...)...)
+	go func() {
+		s.done <- tailer.Run(ctx, func(line Line) { s.lines <- line })
+	}()
+}
+
+func (s *tailTestSuite) write(flag int, content string) {
+	file, err := os.OpenFile(s.path, flag|os.O_WRONLY|os.O_CREATE, 0o644)
+	s.Require().NoError(err)
+	_, err = file.WriteString(content)
+	s.Require().NoError(err)
+	s.Require().NoError(file.Close())
+}
+
+func (s *tailTestSuite) expectLines(expected ...Line) {
+	for _, line := range expected {
+		select {
+		case actual := <-s.lines:
+			s.Equal(line, actual)
+		case <-time.After(waitTimeout):
+			s.FailNowf("timed out", "waiting for line %v", line)
+		}
+	}
+}
+
+func (s *tailTestSuite) expectNoLines() {
+	select {
+	case line := <-s.lines:
+		s.Failf("unexpected line", "%v", line)
+	case <-time.After(5 * pollInterval):
+	}
+}
+
+func (s *tailTestSuite) TestFollow() {
+	s.write(os.O_TRUNC, "first\nsecond\n")
+	s.start()
+	s.expectLines(Line{Number: 1, Text: "first"}, Line{Number: 2, Text: "second"})
+
+	// Partial lines are only emitted once complete
+	s.write(os.O_APPEND, "thi")
+	s.expectNoLines()
+	s.write(os.O_APPEND, "rd\r\n")
+	s.expectLines(Line{Number: 3, Text: "third"})
+}
+
+func (s *tailTestSuite) TestFromEnd() {
+	s.write(os.O_TRUNC, "old\n")
+	s.start(WithFromEnd(true))
+	s.expectNoLines()
+
+	s.write(os.O_APPEND, "new\n")
+	s.expectLines(Line{Number: 1, Text: "new"})
+}
+
+func (s *tailTestSuite) TestNonPositivePollInterval() {
+	s.write(os.O_TRUNC, "first\n")
+	s.start(WithPollInterval(0))
+	s.expectLines(Line{Number: 1, Text: "first"})
+}
+
+func (s *tailTestSuite) TestWaitsForFile() {
+	s.start()
+	s.expectNoLines()
+
+	s.write(os.O_TRUNC, "first\n")
+	s.expectLines(Line{Number: 1, Text: "first"})
+}
+
+func (s *tailTestSuite) TestTruncation() {
+	s.write(os.O_TRUNC, "first\nsecond\n")
+	s.start()
+	s.expectLines(Line{Number: 1, Text: "first"}, Line{Number: 2, Text: "second"})
+
+	s.write(os.O_TRUNC, "new\n")
+	s.expectLines(Line{Number: 1, Text: "new"})
+}
+
+func (s *tailTestSuite) TestRotation() {
+	s.write(os.O_TRUNC, "first\n")
+	s.start()
+	s.expectLines(Line{Number: 1, Text: "first"})
+
+	// Lines written to the old file just before rotation are not lost
+	s.write(os.O_APPEND, "last")
+	s.Require().NoError(os.Rename(s.path, s.path+".1"))
+	s.write(os.O_TRUNC, "rotated\n")
+
+	s.expectLines(Line{Number: 2, Text: "last"}, Line{Number: 1, Text: "rotated"})
+}
+
+func TestTail(t *testing.T) {
+	suite.Run(t, new(tailTestSuite))
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+// Console modes, controlling how the validation results are written to the console.
+const (
+	// ConsoleLogs writes the results as log lines.
+	ConsoleLogs = "logs"
+	// ConsolePretty writes colorized results grouped per message, followed by a summary table.
+	ConsolePretty = "pretty"
+	// ConsoleJSON writes the JSON report to stdout.
+	ConsoleJSON = "json"
+	// ConsoleQuiet doesn't write the results at all.
+	ConsoleQuiet = "quiet"
+)
+
+// ConsoleModes lists the supported console modes.
+var ConsoleModes = []string{ConsoleLogs, ConsolePretty, ConsoleJSON, ConsoleQuiet}
+
+// writeConsole writes the validation results to the console in the requested mode.
+func (s *Service) writeConsole(mode string, validationReport *report.Report) error {
+	switch mode {
+	case "", ConsoleLogs:
+		s.outputValidationErrorToLogs(validationReport)
+		return nil
+	case ConsolePretty:
+		return newPrettyPrinter(s.stdout, s.color).Print(validationReport)
+	case ConsoleJSON:
+		return writeJSON(s.stdout, validationReport)
+	case ConsoleQuiet:
+		return nil
+	default:
+		return errors.Errorf("unknown console mode %q", mode)
+	}
+}
+
+// writeJSON writes the report as indented JSON.
+func writeJSON(w io.Writer, r *report.Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.Wrap(encoder.Encode(r), "failed to write report")
+}
@@ -3,13 +3,18 @@ package validation
 import (
 	"encoding/csv"
 	"errors"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/ChargePi/chargeflow/pkg/report"
 )
 
-var headers = []string{"message_id", "type", "errors"}
+var headers = []string{"message_id", "type", "errors", "file", "line", "raw"}
+
+var statisticsHeaders = []string{"breakdown", "key", "valid_requests", "invalid_requests", "valid_responses", "invalid_responses", "count"}
 
 // csvWriter implements ReportWriter for CSV output.
 type csvWriter struct{}
@@ -34,20 +39,92 @@ func (csvWriter) Write(path string, r *report.Report) error {
 	}
 
 	// Invalid messages
-	for msgID, rr := range r.InvalidMessages {
-		for typ, errs := range rr {
-			if err = w.Write([]string{msgID, typ, strings.Join(errs, " | ")}); err != nil {
+	for _, msgID := range r.InvalidMessageIds() {
+		rr := r.InvalidMessages[msgID]
+		for _, typ := range slices.Sorted(maps.Keys(rr)) {
+			row := append([]string{msgID, typ, strings.Join(rr[typ], " | ")}, originColumns(r.Messages[msgID][typ])...)
+			if err = w.Write(row); err != nil {
 				return err
 			}
 		}
 	}
 
 	// Non parsable messages
-	for msgID, errs := range r.NonParsableMessages {
-		if err = w.Write([]string{msgID, "non_parsable", strings.Join(errs, " | ")}); err != nil {
+	for _, msgID := range r.NonParsableKeys() {
+		row := append([]string{msgID, "non_parsable", strings.Join(r.NonParsableMessages[msgID], " | ")}, originColumns(r.NonParsableDetails[msgID])...)
+		if err = w.Write(row); err != nil {
 			return err
 		}
 	}
 
+	// The statistics don't fit the columns of the messages, so they follow as a second table after a blank line
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return err
+	}
+
+	if _, err = f.WriteString("\n"); err != nil {
+		return err
+	}
+
+	return writeStatistics(w, r.Statistics)
+}
+
+// writeStatistics writes the statistics breakdowns as a table with one row per breakdown entry.
+func writeStatistics(w *csv.Writer, stats report.Statistics) error {
+	if err := w.Write(statisticsHeaders); err != nil {
+		return err
+	}
+
+	countBreakdowns := []struct {
+		name   string
+		counts map[string]report.Counts
+	}{
+		{"action", stats.ByAction},
+		{"vendor_model", stats.ByVendorModel},
+	}
+
+	for _, breakdown := range countBreakdowns {
+		for _, key := range report.MostInvalid(breakdown.counts) {
+			c := breakdown.counts[key]
+			row := []string{
+				breakdown.name, key,
+				strconv.Itoa(c.ValidRequests), strconv.Itoa(c.InvalidRequests),
+				strconv.Itoa(c.ValidResponses), strconv.Itoa(c.InvalidResponses),
+				strconv.Itoa(c.Total()),
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	occurrenceBreakdowns := []struct {
+		name   string
+		counts map[string]int
+	}{
+		{"error_kind", stats.ByErrorKind},
+		{"field", stats.ByField},
+		{"error_code", stats.ByErrorCode},
+	}
+
+	for _, breakdown := range occurrenceBreakdowns {
+		for _, key := range report.MostFrequent(breakdown.counts) {
+			if err := w.Write([]string{breakdown.name, key, "", "", "", "", strconv.Itoa(breakdown.counts[key])}); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
+
+// originColumns returns the file, line and raw frame columns of a message.
+func originColumns(details report.MessageDetails) []string {
+	line := ""
+	if details.Line > 0 {
+		line = strconv.Itoa(details.Line)
+	}
+
+	return []string{details.File, line, details.Raw}
+}
@@ -24,8 +24,14 @@ func TestCSVStrategy_Write(t *testing.T) {
 				"request": []string{"e1", "e2"},
 			},
 		},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {"request": {File: "messages.txt", Line: 3, Raw: `[2, "m1", "Heartbeat", []]`}},
+		},
 		NonParsableMessages: map[string][]string{"p1": {"pe1"}},
-		Statistics:          report.Statistics{},
+		Statistics: report.Statistics{
+			ByAction: map[string]report.Counts{"Heartbeat": {InvalidRequests: 1}},
+			ByField:  map[string]int{"Heartbeat/foo": 1},
+		},
 	}
 
 	s := csvWriter{}
@@ -36,6 +42,16 @@ func TestCSVStrategy_Write(t *testing.T) {
 
 	content := string(b)
 	require.Truef(t, strings.Contains(content, "message_id,type,errors") || strings.Contains(content, "message_id, type, errors"), "csv header missing, got: %s", content)
+	require.Contains(t, content, `m1,request,e1 | e2,messages.txt,3,"[2, ""m1"", ""Heartbeat"", []]"`, "expected origin of m1 in csv")
 	require.Contains(t, content, "m1", "expected m1 in csv")
 	require.Contains(t, content, "non_parsable", "expected non_parsable in csv")
+
+	// The statistics follow the messages in the same file, as a separate table
+	require.Contains(t, content, "\n\nbreakdown,key,valid_requests,invalid_requests,valid_responses,invalid_responses,count\n")
+	require.Contains(t, content, "action,Heartbeat,0,1,0,0,1")
+	require.Contains(t, content, "field,Heartbeat/foo,,,,,1")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "expected no files besides the report")
 }
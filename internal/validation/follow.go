@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/tail"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+// Follow tails a growing file (like `tail -F`) until the context is canceled, validating messages as they are
+// appended and logging violations as they appear. Requests and responses are paired incrementally, also across
+// file rotations. Empty lines are skipped.
+func (s *Service) Follow(ctx context.Context, octx ocpp.OcppContext, file string, opts ...tail.Option) error {
+	logger := s.logger.With(
+		zap.String("file", file),
+		zap.String("ocppVersion", octx.Version.Name()),
+		zap.String("vendor", octx.Vendor),
+		zap.String("model", octx.Model),
+	)
+	logger.Info("Following file for new messages")
+
+	session := s.NewSession()
+	tailer := tail.NewTailer(s.logger, file, opts...)
+
+	err := tailer.Run(ctx, func(line tail.Line) {
+		if strings.TrimSpace(line.Text) == "" {
+			return
+		}
+
+		result, err := session.ValidateFrameAt(octx, line.Number, line.Text)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Message at line %d could not be validated:", line.Number))
+			logger.Error(fmt.Sprintf("👉 %s", err))
+			return
+		}
+
+		s.outputFrameResultToLogs(logger, line.Number, result)
+		s.outputSequenceResultsToLogs(logger, result.Sequence)
+	})
+
+	// The messages still in the sequence window can't be followed by any other message anymore
+	s.outputSequenceResultsToLogs(logger, session.Close())
+	return err
+}
+
+// outputFrameResultToLogs outputs the validation errors of a single frame to the logs.
+func (s *Service) outputFrameResultToLogs(logger *zap.Logger, line int, result *FrameResult) {
+	logger = logger.With(zap.Int("line", line))
+
+	switch {
+	case !result.Parsable:
+		logger.Error(fmt.Sprintf("Message could not be parsed at line %d:", line))
+	case result.Valid:
+		logger.Debug(fmt.Sprintf("✅ Message %s (%s) is valid", result.UniqueId, result.Action))
+		return
+	case result.IsRequest:
+		logger.Error(fmt.Sprintf("Request for message %s has the following validation errors:", result.UniqueId), zap.String("messageId", result.UniqueId))
+	default:
+		logger.Error(fmt.Sprintf("Response for message %s has the following validation errors:", result.UniqueId), zap.String("messageId", result.UniqueId))
+	}
+
+	for _, validationErr := range result.Errors {
+		logger.Error(fmt.Sprintf("👉 %s", validationErr))
+	}
+}
+
+// outputSequenceResultsToLogs outputs the violations of rules spanning multiple messages to the logs.
+func (s *Service) outputSequenceResultsToLogs(logger *zap.Logger, results []SequenceResult) {
+	for _, result := range results {
+		logger.Error(fmt.Sprintf("Message %s (%s) has the following sequence errors:", result.UniqueId, result.Action), zap.String("messageId", result.UniqueId))
+		for _, sequenceErr := range result.Errors {
+			logger.Error(fmt.Sprintf("👉 %s", sequenceErr))
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaptinlin/jsonschema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	mock_schema_registry "github.com/ChargePi/chargeflow/gen/mocks/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/internal/tail"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+)
+
+type observerFunc func(Observation)
+
+func (f observerFunc) Observe(observation Observation) {
+	f(observation)
+}
+
+func TestService_Follow(t *testing.T) {
+	const response = `[3, "1234", {"status": "Accepted", "currentTime": "2024-01-01T00:00:00Z", "interval": 300}]`
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(t)
+	requestSchema, err := jsonschema.NewCompiler().Compile(bootNotificationSchema)
+	require.NoError(t, err)
+	responseSchema, err := jsonschema.NewCompiler().Compile(bootNotificationResponseSchema)
+	require.NoError(t, err)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "BootNotificationRequest"}).Return(requestSchema, true)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "BootNotificationResponse"}).Return(responseSchema, true)
+
+	observations := make(chan Observation, 10)
+	service := NewService(zap.NewNop(), registry, WithObserver(observerFunc(func(observation Observation) {
+		observations <- observation
+	})))
+
+	file := filepath.Join(t.TempDir(), "csms.log")
+	require.NoError(t, os.WriteFile(file, []byte(ocpp16validReq+"\n\n"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- service.Follow(ctx, octx, file, tail.WithPollInterval(10*time.Millisecond))
+	}()
+
+	expectOutcome := func(action string, outcome Outcome) {
+		select {
+		case observation := <-observations:
+			require.Equal(t, action, observation.Action)
+			require.Equal(t, outcome, observation.Outcome)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %s (%s)", action, outcome)
+		}
+	}
+
+	// The empty line is skipped
+	expectOutcome("BootNotification", OutcomeValid)
+
+	appendFile, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = appendFile.WriteString(response + "\n" + unparsableMsg + "\n")
+	require.NoError(t, err)
+	require.NoError(t, appendFile.Close())
+
+	// The response is paired with the request read earlier
+	expectOutcome("BootNotification", OutcomeValid)
+	expectOutcome("", OutcomeUnparsable)
+
+	cancel()
+	require.NoError(t, <-done)
+}
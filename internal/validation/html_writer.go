@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"bytes"
+	"cmp"
+	_ "embed"
+	"errors"
+	"html/template"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+//go:embed templates/report.html
+var htmlTemplate string
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"breakdown": func(title string, rows []htmlCounts) map[string]any {
+		return map[string]any{"Title": title, "Rows": rows}
+	},
+}).Parse(htmlTemplate))
+
+type htmlReport struct {
+	Source      string
+	GeneratedAt string
+	Total       int
+	SuccessRate float64
+	Statistics  report.Statistics
+	Actions     []actionSummary
+	Categories  []htmlCategory
+	// Breakdowns of the statistics, most invalid or most frequent first.
+	ActionCounts []htmlCounts
+	VendorModels []htmlCounts
+	Fields       []htmlCategory
+	ErrorCodes   []htmlCategory
+	Messages     []htmlMessage
+	Unparsable   []htmlUnparsable
+	// Details are rendered on demand by the embedded script.
+	Details map[string]map[string]report.MessageDetails
+}
+
+type htmlCategory struct {
+	Name  string
+	Count int
+	Width float64
+}
+
+type htmlCounts struct {
+	Name string
+	report.Counts
+}
+
+type htmlMessage struct {
+	Id         string
+	Action     string
+	Status     string
+	Categories []string
+	Errors     []string
+}
+
+type htmlUnparsable struct {
+	Location string
+	Errors   []string
+	Raw      string
+}
+
+// htmlWriter implements ReportWriter for a self-contained, interactive HTML report that can be shared
+// with people who don't use the CLI.
+type htmlWriter struct{}
+
+func (htmlWriter) Write(path string, r *report.Report) error {
+	if r == nil {
+		return errors.New("report is nil")
+	}
+
+	var b bytes.Buffer
+	if err := htmlReportTemplate.Execute(&b, newHtmlReport(r)); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// newHtmlReport prepares the report for rendering: one row per message, and the per-error type counts for the chart.
+func newHtmlReport(r *report.Report) htmlReport {
+	view := htmlReport{
+		Source:      r.Source,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Statistics:  r.Statistics,
+		Total:       r.Statistics.GetTotal() + r.Statistics.UnparsableMessages,
+		SuccessRate: r.Statistics.TotalValidMessagesPercentage(),
+		Details:     r.Messages,
+		Actions:     summarizeActions(r),
+	}
+
+	categoryCounts := map[string]int{}
+
+	for _, messageId := range r.MessageIds() {
+		parts := r.Messages[messageId]
+		message := htmlMessage{Id: messageId, Action: messageAction(parts), Status: "valid"}
+
+		for _, part := range slices.Sorted(maps.Keys(parts)) {
+			for _, detail := range parts[part].Errors {
+				category := string(detail.Category)
+				if !slices.Contains(message.Categories, category) {
+					message.Categories = append(message.Categories, category)
+				}
+				message.Errors = append(message.Errors, detail.Message)
+				categoryCounts[category]++
+			}
+		}
+
+		if _, invalid := r.InvalidMessages[messageId]; invalid {
+			message.Status = "invalid"
+		}
+
+		view.Messages = append(view.Messages, message)
+	}
+
+	view.Categories = newHtmlBars(categoryCounts, slices.Sorted(maps.Keys(categoryCounts)))
+	view.Fields = newHtmlBars(r.Statistics.ByField, report.MostFrequent(r.Statistics.ByField))
+	view.ErrorCodes = newHtmlBars(r.Statistics.ByErrorCode, report.MostFrequent(r.Statistics.ByErrorCode))
+	view.ActionCounts = newHtmlCounts(r.Statistics.ByAction)
+	view.VendorModels = newHtmlCounts(r.Statistics.ByVendorModel)
+
+	for _, key := range r.NonParsableKeys() {
+		details := r.NonParsableDetails[key]
+		view.Unparsable = append(view.Unparsable, htmlUnparsable{
+			Location: cmp.Or(details.Location(), key),
+			Errors:   r.NonParsableMessages[key],
+			Raw:      details.Raw,
+		})
+	}
+
+	return view
+}
+
+// newHtmlBars returns a bar per key, in the given order, scaled to the largest count.
+func newHtmlBars(counts map[string]int, keys []string) []htmlCategory {
+	maxCount := 0
+	for _, count := range counts {
+		maxCount = max(maxCount, count)
+	}
+
+	bars := make([]htmlCategory, 0, len(keys))
+	for _, key := range keys {
+		bars = append(bars, htmlCategory{
+			Name:  key,
+			Count: counts[key],
+			Width: float64(counts[key]) / float64(maxCount) * 100,
+		})
+	}
+
+	return bars
+}
+
+// newHtmlCounts returns the rows of a breakdown table, most invalid first.
+func newHtmlCounts(counts map[string]report.Counts) []htmlCounts {
+	rows := make([]htmlCounts, 0, len(counts))
+	for _, key := range report.MostInvalid(counts) {
+		rows = append(rows, htmlCounts{Name: key, Counts: counts[key]})
+	}
+
+	return rows
+}
@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+func TestHTMLWriter_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.html")
+
+	r := &report.Report{
+		Source: "messages.txt",
+		InvalidMessages: map[string]map[string][]string{
+			"m1": {"request": []string{"Value is integer but should be string"}},
+		},
+		ValidMessages:       map[string][]string{"m2": {"request"}},
+		NonParsableMessages: map[string][]string{"line 3": {"Message is not a valid OCPP message"}},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {"request": {
+				Action:  "Authorize",
+				Payload: json.RawMessage(`{"idTag": "</script><script>alert(1)</script>"}`),
+				Errors:  []report.ErrorDetails{{Category: "type", Message: "Value is integer but should be string"}},
+				Fields:  []string{"/idTag"},
+			}},
+			"m2": {"request": {Action: "Heartbeat", Payload: json.RawMessage(`{}`)}},
+		},
+		Statistics: report.Statistics{
+			ValidRequests: 1, InvalidRequests: 1, UnparsableMessages: 1,
+			ByAction:      map[string]report.Counts{"Authorize": {InvalidRequests: 1}, "Heartbeat": {ValidRequests: 1}},
+			ByField:       map[string]int{"Authorize/idTag": 1},
+			ByVendorModel: map[string]report.Counts{"ABB/Terra AC": {ValidRequests: 1, InvalidRequests: 1}},
+		},
+	}
+
+	require.NoError(t, htmlWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	content := string(b)
+	require.Contains(t, content, "<title>ChargeFlow validation report – messages.txt</title>")
+	require.Contains(t, content, `data-id="m1" data-status="invalid" data-action="Authorize" data-categories="type"`)
+	require.Contains(t, content, `data-id="m2" data-status="valid" data-action="Heartbeat"`)
+	require.Contains(t, content, "line 3")
+	require.Contains(t, content, "50.00%")
+	require.Contains(t, content, "<tr><td>Authorize</td><td>0</td><td>1</td><td>0</td><td>0</td><td>100.00%</td></tr>")
+	require.Contains(t, content, "<span>Authorize/idTag</span>")
+	require.Contains(t, content, "<h2>Vendors and models</h2>")
+	// Payloads must not be able to break out of the embedded script
+	require.NotContains(t, content, "<script>alert(1)")
+}
+
+func TestNewHtmlReport(t *testing.T) {
+	r := &report.Report{
+		InvalidMessages: map[string]map[string][]string{"m1": {"response": []string{"e1"}}},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {
+				"request":  {Action: "Authorize"},
+				"response": {Action: "Authorize", Errors: []report.ErrorDetails{{Category: "required", Message: "e1"}}},
+			},
+			"m2": {"request": {Action: "Authorize"}},
+			"m3": {"response": {}},
+		},
+	}
+
+	view := newHtmlReport(r)
+	require.Equal(t, []actionSummary{
+		{Name: "Authorize", Valid: 1, Total: 2},
+		{Name: "unknown", Valid: 1, Total: 1},
+	}, view.Actions)
+	require.Equal(t, 50.0, view.Actions[0].PassRate())
+	require.Equal(t, 50.0, view.Actions[0].FailRate())
+	require.Equal(t, []htmlCategory{{Name: "required", Count: 1, Width: 100}}, view.Categories)
+	require.Len(t, view.Messages, 3)
+	require.Equal(t, "invalid", view.Messages[0].Status)
+	require.Equal(t, []string{"required"}, view.Messages[0].Categories)
+}
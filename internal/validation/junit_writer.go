@@ -0,0 +1,190 @@
+package validation
+
+import (
+	"cmp"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitWriter implements ReportWriter for JUnit XML output. Every request and response is a test case,
+// so CI systems can show the failing messages next to the rest of the test results.
+type junitWriter struct{}
+
+func (junitWriter) Write(path string, r *report.Report) error {
+	if r == nil {
+		return errors.New("report is nil")
+	}
+
+	suite := junitTestSuite{Name: "chargeflow"}
+	if r.Source != "" {
+		suite.Name = r.Source
+	}
+
+	messageIds := slices.Collect(maps.Keys(r.ValidMessages))
+	for messageId := range r.InvalidMessages {
+		if _, found := r.ValidMessages[messageId]; !found {
+			messageIds = append(messageIds, messageId)
+		}
+	}
+	r.SortMessageIds(messageIds)
+
+	for _, messageId := range messageIds {
+		// A message can have a valid request and an invalid response, or vice versa
+		parts := slices.Clone(r.ValidMessages[messageId])
+		parts = append(parts, slices.Collect(maps.Keys(r.InvalidMessages[messageId]))...)
+		slices.Sort(parts)
+
+		for _, part := range parts {
+			details := r.Messages[messageId][part]
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s %s", messageId, part),
+				ClassName: "ocpp." + part,
+				File:      cmp.Or(details.File, r.Source),
+				Line:      details.Line,
+			}
+
+			if errs, found := r.InvalidMessages[messageId][part]; found {
+				testCase.Failure = &junitProblem{
+					Message: fmt.Sprintf("%s of message %s is invalid", capitalize(part), messageId),
+					Type:    "validation",
+					Text:    junitText(errs, details.Raw),
+				}
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+	}
+
+	for _, key := range r.NonParsableKeys() {
+		details := r.NonParsableDetails[key]
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      key,
+			ClassName: "ocpp.unparsable",
+			File:      cmp.Or(details.File, r.Source),
+			Line:      cmp.Or(details.Line, lineFromKey(key)),
+			Error: &junitProblem{
+				Message: fmt.Sprintf("Message at %s could not be parsed", key),
+				Type:    "parse",
+				Text:    junitText(r.NonParsableMessages[key], details.Raw),
+			},
+		})
+		suite.Errors++
+	}
+
+	suite.Tests = len(suite.TestCases)
+	suite.Properties = junitProperties(r.Statistics)
+
+	b, err := xml.MarshalIndent(junitTestSuites{
+		Name:     "chargeflow",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, append([]byte(xml.Header), b...), 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// junitProperties returns the statistics breakdowns as test suite properties, e.g. "action.Authorize.invalid_requests".
+func junitProperties(stats report.Statistics) []junitProperty {
+	var properties []junitProperty
+
+	addCounts := func(prefix string, counts map[string]report.Counts) {
+		for _, key := range report.MostInvalid(counts) {
+			c := counts[key]
+			properties = append(properties,
+				junitProperty{Name: fmt.Sprintf("%s.%s.valid_requests", prefix, key), Value: strconv.Itoa(c.ValidRequests)},
+				junitProperty{Name: fmt.Sprintf("%s.%s.invalid_requests", prefix, key), Value: strconv.Itoa(c.InvalidRequests)},
+				junitProperty{Name: fmt.Sprintf("%s.%s.valid_responses", prefix, key), Value: strconv.Itoa(c.ValidResponses)},
+				junitProperty{Name: fmt.Sprintf("%s.%s.invalid_responses", prefix, key), Value: strconv.Itoa(c.InvalidResponses)},
+			)
+		}
+	}
+
+	addOccurrences := func(prefix string, counts map[string]int) {
+		for _, key := range report.MostFrequent(counts) {
+			properties = append(properties, junitProperty{Name: fmt.Sprintf("%s.%s", prefix, key), Value: strconv.Itoa(counts[key])})
+		}
+	}
+
+	addCounts("action", stats.ByAction)
+	addOccurrences("error_kind", stats.ByErrorKind)
+	addOccurrences("field", stats.ByField)
+	addOccurrences("error_code", stats.ByErrorCode)
+	addCounts("vendor_model", stats.ByVendorModel)
+
+	return properties
+}
+
+// junitText lists the errors of a test case, followed by the original frame if known.
+func junitText(errs []string, raw string) string {
+	text := strings.Join(errs, "\n")
+	if raw != "" {
+		text += "\n\n" + raw
+	}
+
+	return text
+}
+
+// capitalize upper-cases the first letter of a (ASCII) word.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+func TestJUnitWriter_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.xml")
+
+	r := &report.Report{
+		Source: "messages.txt",
+		InvalidMessages: map[string]map[string][]string{
+			"m1": {"response": []string{"e1", "e2"}},
+			"m2": {"request": []string{"e3"}},
+		},
+		ValidMessages: map[string][]string{"m1": {"request"}, "m3": {"request", "response"}},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {"response": {File: "messages.txt", Line: 2, Raw: `[3, "m1", {}]`}},
+		},
+		NonParsableMessages: map[string][]string{"line 4": {"pe1"}},
+		Statistics:          report.Statistics{ByErrorCode: map[string]int{"NotSupported": 1}},
+	}
+
+	require.NoError(t, junitWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(b, &suites))
+	require.Equal(t, 6, suites.Tests)
+	require.Equal(t, 2, suites.Failures)
+	require.Equal(t, 1, suites.Errors)
+	require.Len(t, suites.Suites, 1)
+	require.Equal(t, []junitProperty{{Name: "error_code.NotSupported", Value: "1"}}, suites.Suites[0].Properties)
+
+	var names []string
+	for _, testCase := range suites.Suites[0].TestCases {
+		names = append(names, testCase.Name)
+	}
+	require.Equal(t, []string{"m1 request", "m1 response", "m2 request", "m3 request", "m3 response", "line 4"}, names)
+
+	failed := suites.Suites[0].TestCases[1]
+	require.NotNil(t, failed.Failure)
+	require.Equal(t, "e1\ne2\n\n[3, \"m1\", {}]", failed.Failure.Text)
+	require.Equal(t, "messages.txt", failed.File)
+	require.Equal(t, 2, failed.Line)
+	require.Equal(t, 4, suites.Suites[0].TestCases[5].Line)
+	require.Nil(t, suites.Suites[0].TestCases[0].Failure)
+	require.NotNil(t, suites.Suites[0].TestCases[5].Error)
+}
@@ -0,0 +1,255 @@
+package validation
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+const (
+	// topErrorsLimit is the number of most frequent errors listed in the Markdown report.
+	topErrorsLimit = 10
+	// breakdownLimit is the number of entries listed per statistics breakdown in the Markdown report.
+	breakdownLimit = 10
+)
+
+// markdownWriter implements ReportWriter for Markdown output, concise enough to be posted as a comment
+// on a GitHub pull request or GitLab merge request.
+type markdownWriter struct{}
+
+func (markdownWriter) Write(path string, r *report.Report) error {
+	if r == nil {
+		return errors.New("report is nil")
+	}
+
+	var b strings.Builder
+	b.WriteString("## ChargeFlow validation report\n\n")
+
+	stats := r.Statistics
+	status := "✅ All messages are valid"
+	if len(r.InvalidMessages) > 0 || len(r.NonParsableMessages) > 0 {
+		status = fmt.Sprintf("❌ %d invalid, %d unparsable", len(r.InvalidMessages), len(r.NonParsableMessages))
+	}
+
+	if r.Source != "" {
+		b.WriteString(fmt.Sprintf("**%s** · ", escapeMarkdown(r.Source)))
+	}
+	b.WriteString(fmt.Sprintf("%s · success rate **%.2f%%**\n\n", status, stats.TotalValidMessagesPercentage()))
+
+	b.WriteString("| | Valid | Invalid |\n|---|---:|---:|\n")
+	b.WriteString(fmt.Sprintf("| Requests | %d | %d |\n", stats.ValidRequests, stats.InvalidRequests))
+	b.WriteString(fmt.Sprintf("| Responses | %d | %d |\n", stats.ValidResponses, stats.InvalidResponses))
+	b.WriteString(fmt.Sprintf("| Unparsable | | %d |\n\n", stats.UnparsableMessages))
+
+	if actions := summarizeActions(r); len(actions) > 0 {
+		b.WriteString("### Pass rate by action\n\n| Action | Messages | Valid | Pass rate |\n|---|---:|---:|---:|\n")
+		for _, action := range actions {
+			b.WriteString(fmt.Sprintf("| %s | %d | %d | %.2f%% |\n", escapeMarkdown(action.Name), action.Total, action.Valid, action.PassRate()))
+		}
+		b.WriteString("\n")
+	}
+
+	writeMarkdownFailuresByAction(&b, stats.ByAction)
+
+	if topErrors := topErrors(r); len(topErrors) > 0 {
+		b.WriteString("### Top errors\n\n| Error | Occurrences |\n|---|---:|\n")
+		for _, topError := range topErrors {
+			b.WriteString(fmt.Sprintf("| %s | %d |\n", escapeMarkdown(topError.message), topError.count))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(stats.ByErrorKind) > 0 || len(stats.ByField) > 0 || len(stats.ByErrorCode) > 0 || len(stats.ByVendorModel) > 0 {
+		b.WriteString("<details>\n<summary>Breakdowns</summary>\n\n")
+		writeMarkdownOccurrences(&b, "Errors by kind", "Kind", stats.ByErrorKind)
+		writeMarkdownOccurrences(&b, "Failing fields", "Field", stats.ByField)
+		writeMarkdownOccurrences(&b, "CALLERROR codes", "Error code", stats.ByErrorCode)
+		writeMarkdownCounts(&b, "Vendors and models", "Vendor/model", stats.ByVendorModel)
+		b.WriteString("</details>\n\n")
+	}
+
+	if len(r.InvalidMessages) > 0 || len(r.NonParsableMessages) > 0 {
+		b.WriteString(fmt.Sprintf("<details>\n<summary>Details of %d failing messages</summary>\n\n", len(r.InvalidMessages)+len(r.NonParsableMessages)))
+
+		for _, messageId := range r.InvalidMessageIds() {
+			parts := r.InvalidMessages[messageId]
+			b.WriteString(fmt.Sprintf("#### %s %s\n\n", codeSpan(messageId), escapeMarkdown(messageAction(r.Messages[messageId]))))
+
+			for _, part := range slices.Sorted(maps.Keys(parts)) {
+				label := fmt.Sprintf("**%s**", part)
+				if location := r.Messages[messageId][part].Location(); location != "" {
+					label += fmt.Sprintf(" (%s)", escapeMarkdown(location))
+				}
+
+				for _, e := range parts[part] {
+					b.WriteString(fmt.Sprintf("- %s: %s\n", label, escapeMarkdown(e)))
+				}
+			}
+			b.WriteString("\n")
+		}
+
+		for _, key := range r.NonParsableKeys() {
+			b.WriteString(fmt.Sprintf("#### %s (unparsable)\n\n", escapeMarkdown(key)))
+			for _, e := range r.NonParsableMessages[key] {
+				b.WriteString(fmt.Sprintf("- %s\n", escapeMarkdown(e)))
+			}
+
+			if raw := r.NonParsableDetails[key].Raw; raw != "" {
+				fence := codeFence(raw)
+				b.WriteString(fmt.Sprintf("\n%s\n%s\n%s\n", fence, raw, fence))
+			}
+			b.WriteString("\n")
+		}
+
+		b.WriteString("</details>\n")
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeMarkdownFailuresByAction lists the actions with invalid requests or responses, most invalid first.
+func writeMarkdownFailuresByAction(b *strings.Builder, byAction map[string]report.Counts) {
+	failing := map[string]report.Counts{}
+	for action, counts := range byAction {
+		if counts.Invalid() > 0 {
+			failing[action] = counts
+		}
+	}
+
+	writeMarkdownCounts(b, "Failures by action", "Action", failing)
+}
+
+// writeMarkdownCounts writes a table of the valid and invalid requests and responses, most invalid first.
+func writeMarkdownCounts(b *strings.Builder, title, column string, counts map[string]report.Counts) {
+	if len(counts) == 0 {
+		return
+	}
+
+	b.WriteString(fmt.Sprintf("### %s\n\n| %s | Invalid requests | Invalid responses | Invalid rate |\n|---|---:|---:|---:|\n", title, column))
+	for _, key := range limit(report.MostInvalid(counts), breakdownLimit) {
+		c := counts[key]
+		b.WriteString(fmt.Sprintf("| %s | %d of %d | %d of %d | %.2f%% |\n",
+			escapeMarkdown(key),
+			c.InvalidRequests, c.ValidRequests+c.InvalidRequests,
+			c.InvalidResponses, c.ValidResponses+c.InvalidResponses,
+			c.InvalidPercentage(),
+		))
+	}
+	b.WriteString("\n")
+}
+
+// writeMarkdownOccurrences writes a table of occurrences, most frequent first.
+func writeMarkdownOccurrences(b *strings.Builder, title, column string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	b.WriteString(fmt.Sprintf("### %s\n\n| %s | Occurrences |\n|---|---:|\n", title, column))
+	for _, key := range limit(report.MostFrequent(counts), breakdownLimit) {
+		b.WriteString(fmt.Sprintf("| %s | %d |\n", escapeMarkdown(key), counts[key]))
+	}
+	b.WriteString("\n")
+}
+
+// limit returns at most n elements of the slice.
+func limit[T any](s []T, n int) []T {
+	if len(s) > n {
+		return s[:n]
+	}
+
+	return s
+}
+
+type errorCount struct {
+	message string
+	count   int
+}
+
+// topErrors returns the most frequent error messages, most frequent first.
+func topErrors(r *report.Report) []errorCount {
+	counts := map[string]int{}
+	for _, parts := range r.InvalidMessages {
+		for _, errs := range parts {
+			for _, e := range errs {
+				counts[e]++
+			}
+		}
+	}
+
+	for _, errs := range r.NonParsableMessages {
+		for _, e := range errs {
+			counts[e]++
+		}
+	}
+
+	result := make([]errorCount, 0, len(counts))
+	for message, count := range counts {
+		result = append(result, errorCount{message: message, count: count})
+	}
+
+	slices.SortFunc(result, func(a, b errorCount) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.message, b.message))
+	})
+
+	return limit(result, topErrorsLimit)
+}
+
+// markdownReplacer escapes characters that would break tables or be interpreted as formatting.
+var markdownReplacer = strings.NewReplacer(
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"<", "&lt;",
+	">", "&gt;",
+	"\n", " ",
+)
+
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+// codeFence returns a backtick fence longer than the longest run of backticks in the text, so the text can't close
+// the code block it's in.
+func codeFence(s string) string {
+	return strings.Repeat("`", max(3, longestBacktickRun(s)+1))
+}
+
+// codeSpan returns the text as inline code, delimited by more backticks than the text contains in a row, so the
+// text can't close the code span. Text starting or ending with a backtick is padded with a space, as Markdown
+// strips one space from both sides.
+func codeSpan(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+
+	delimiter := strings.Repeat("`", longestBacktickRun(s)+1)
+	return delimiter + s + delimiter
+}
+
+// longestBacktickRun returns the length of the longest run of backticks in the text.
+func longestBacktickRun(s string) int {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r != '`' {
+			run = 0
+			continue
+		}
+
+		run++
+		longest = max(longest, run)
+	}
+
+	return longest
+}
@@ -0,0 +1,123 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+func TestMarkdownWriter_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.md")
+
+	r := &report.Report{
+		Source: "firmware_1.2.txt",
+		InvalidMessages: map[string]map[string][]string{
+			"m1": {"request": []string{"Required property 'idTag' is missing"}},
+			"m2": {"request": []string{"Required property 'idTag' is missing"}, "response": []string{"Value | pipe"}},
+		},
+		ValidMessages:       map[string][]string{"m3": {"request", "response"}},
+		NonParsableMessages: map[string][]string{"line 7": {"Message is not a valid OCPP message"}},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {"request": {Action: "Authorize"}},
+			"m2": {"request": {Action: "Authorize"}, "response": {Action: "Authorize"}},
+			"m3": {"request": {Action: "Heartbeat"}, "response": {Action: "Heartbeat"}},
+		},
+		Statistics: report.Statistics{
+			ValidRequests: 1, ValidResponses: 1, InvalidRequests: 2, InvalidResponses: 1, UnparsableMessages: 1,
+			ByAction: map[string]report.Counts{
+				"Authorize": {InvalidRequests: 2, InvalidResponses: 1},
+				"Heartbeat": {ValidRequests: 1, ValidResponses: 1},
+			},
+			ByField: map[string]int{"Authorize/idTag": 2},
+		},
+	}
+
+	require.NoError(t, markdownWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	content := string(b)
+	require.Contains(t, content, "**firmware\\_1.2.txt** · ❌ 2 invalid, 1 unparsable · success rate **40.00%**")
+	require.Contains(t, content, "| Authorize | 2 | 0 | 0.00% |")
+	require.Contains(t, content, "| Heartbeat | 1 | 1 | 100.00% |")
+	// Only failing actions are listed
+	require.Contains(t, content, "### Failures by action")
+	require.Contains(t, content, "| Authorize | 2 of 2 | 1 of 1 | 100.00% |")
+	require.NotContains(t, content, "| Heartbeat | 0 of 1 |")
+	require.Contains(t, content, "| Authorize/idTag | 2 |")
+	// The most frequent error is listed first
+	require.Regexp(t, `(?s)### Top errors.*Required property 'idTag' is missing \| 2 \|.*Value \\\| pipe \| 1 \|`, content)
+	require.Contains(t, content, "<details>")
+	require.Contains(t, content, "#### `m2` Authorize")
+	require.Contains(t, content, "- **response**: Value \\| pipe")
+	require.Contains(t, content, "#### line 7 (unparsable)")
+}
+
+func TestMarkdownWriter_WriteRawFrameWithBackticks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.md")
+
+	raw := "[2, \"1\", \"DataTransfer\", {\"data\": \"```` # not a heading\"}"
+	r := &report.Report{
+		NonParsableMessages: map[string][]string{"line 1": {"Message is not a valid OCPP message"}},
+		NonParsableDetails:  map[string]report.MessageDetails{"line 1": {Raw: raw}},
+		Statistics:          report.Statistics{UnparsableMessages: 1},
+	}
+
+	require.NoError(t, markdownWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// The fence is longer than the backticks in the frame, so the frame can't close it
+	require.Contains(t, string(b), "\n`````\n"+raw+"\n`````\n")
+}
+
+func TestMarkdownWriter_WriteAllValid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.md")
+
+	r := &report.Report{
+		ValidMessages: map[string][]string{"m1": {"request"}},
+		Messages:      map[string]map[string]report.MessageDetails{"m1": {"request": {Action: "Heartbeat"}}},
+		Statistics:    report.Statistics{ValidRequests: 1},
+	}
+
+	require.NoError(t, markdownWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	content := string(b)
+	require.Contains(t, content, "✅ All messages are valid")
+	require.NotContains(t, content, "Top errors")
+	require.NotContains(t, content, "<details>")
+}
+
+func TestMarkdownWriter_WriteMessageIdWithBackticks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.md")
+
+	r := &report.Report{
+		InvalidMessages: map[string]map[string][]string{"m`1": {"request": []string{"Required property 'idTag' is missing"}}},
+		Messages:        map[string]map[string]report.MessageDetails{"m`1": {"request": {Action: "Authorize"}}},
+		Statistics:      report.Statistics{InvalidRequests: 1},
+	}
+
+	require.NoError(t, markdownWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// The backtick in the message ID doesn't close the code span
+	require.Contains(t, string(b), "#### ``m`1`` Authorize\n")
+}
+
+func TestCodeSpan(t *testing.T) {
+	require.Equal(t, "`m1`", codeSpan("m1"))
+	require.Equal(t, "``a`b``", codeSpan("a`b"))
+	require.Equal(t, "``` ``m1 ```", codeSpan("``m1"))
+	require.Equal(t, "`a b`", codeSpan("a\nb"))
+}
@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+// Outcome of validating a single message.
+type Outcome string
+
+const (
+	OutcomeValid   = Outcome("valid")
+	OutcomeInvalid = Outcome("invalid")
+	// OutcomeUnparsable is used for frames that are not structurally valid OCPP-J messages.
+	OutcomeUnparsable = Outcome("unparsable")
+	// OutcomeError is used for messages that could not be validated at all, e.g. when there is no schema for the action.
+	OutcomeError = Outcome("error")
+)
+
+// Observation describes the validation of a single message.
+type Observation struct {
+	OcppContext ocpp.OcppContext
+	// Action of the message. Empty for unparsable messages.
+	Action  string
+	Outcome Outcome
+	// Categories of the validation errors of an invalid message.
+	Categories []validator.ErrorCategory
+	// Duration of the validation against the schema. Zero for unparsable messages.
+	Duration time.Duration
+}
+
+// Observer is notified about every message validated by the Service, e.g. to collect metrics.
+// Implementations must be safe for concurrent use.
+type Observer interface {
+	Observe(observation Observation)
+}
+
+// validateMessage validates a message and notifies the observer about the outcome. The request is the
+// request a response answers, or nil when validating a request.
+func (s *Service) validateMessage(octx ocpp.OcppContext, action string, request, message ocpp.Message) (*validator.ValidationResult, error) {
+	start := time.Now()
+	result, err := s.validator.ValidateResponse(octx, request, message)
+
+	observation := Observation{
+		OcppContext: octx,
+		Action:      action,
+		Outcome:     OutcomeValid,
+		Duration:    time.Since(start),
+	}
+
+	switch {
+	case err != nil:
+		observation.Outcome = OutcomeError
+	case !result.IsValid():
+		observation.Outcome = OutcomeInvalid
+		observation.Categories = result.ErrorCategories()
+	}
+
+	s.observe(observation)
+	return result, err
+}
+
+// observeParserResult notifies the observer about a message that failed structural parsing, but was recognized
+// as an OCPP-J message (e.g. a response without a matching request).
+func (s *Service) observeParserResult(octx ocpp.OcppContext, action string, errs []string) {
+	categories := make([]validator.ErrorCategory, 0, len(errs))
+	for range errs {
+		categories = append(categories, validator.CategoryMessage)
+	}
+
+	s.observe(Observation{
+		OcppContext: octx,
+		Action:      action,
+		Outcome:     OutcomeInvalid,
+		Categories:  categories,
+	})
+}
+
+func (s *Service) observeUnparsable(octx ocpp.OcppContext) {
+	s.observe(Observation{OcppContext: octx, Outcome: OutcomeUnparsable})
+}
+
+func (s *Service) observe(observation Observation) {
+	if s.observer != nil {
+		s.observer.Observe(observation)
+	}
+}
@@ -1,13 +1,21 @@
 package validation
 
-import "github.com/ChargePi/chargeflow/pkg/ocpp"
+import (
+	"io"
+	"time"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
 
 // Request carries all inputs for a single validation run.
 type Request struct {
 	OcppContext ocpp.OcppContext // OCPP version, vendor, and model for schema selection
 	Messages    []string         // inline messages to validate (mutually exclusive with File)
 	File        string           // path to a newline-delimited file of messages
-	Output      string           // optional path to write the report (.json, .csv, .txt)
+	Output      string           // optional path to write the report (.json, .csv, .txt, .xml, .sarif, .html, .md)
+	Format      string           // optional report format, overriding the output extension (see Formats)
+	Console     string           // how the results are written to the console (see ConsoleModes), logs by default
 }
 
 // Option is a functional option for ValidateFile (kept for backwards compat with callers
@@ -15,10 +23,58 @@ type Request struct {
 type Option func(*options)
 
 type options struct {
-	output string
+	output   string
+	observer Observer
+	stdout   io.Writer
+	color    bool
+
+	sequenceExchanges int
+	sequenceAge       time.Duration
+	pendingRequests   int
+	pendingAge        time.Duration
+
+	validatorOptions []validator.Option
 }
 
 // WithOutput sets the output path for the validation report.
 func WithOutput(path string) Option {
 	return func(o *options) { o.output = path }
 }
+
+// WithObserver sets an Observer notified about every validated message, e.g. for collecting metrics.
+func WithObserver(observer Observer) Option {
+	return func(o *options) { o.observer = observer }
+}
+
+// WithStdout sets where the results are written to in the pretty and JSON console modes. Defaults to os.Stdout.
+func WithStdout(w io.Writer) Option {
+	return func(o *options) { o.stdout = w }
+}
+
+// WithColor enables colorized results in the pretty console mode.
+func WithColor(enabled bool) Option {
+	return func(o *options) { o.color = enabled }
+}
+
+// WithValidatorOptions configures the validator, e.g. which rules are checked.
+func WithValidatorOptions(opts ...validator.Option) Option {
+	return func(o *options) { o.validatorOptions = append(o.validatorOptions, opts...) }
+}
+
+// WithSequenceWindow sets how long sessions wait for the messages following an exchange before the rules spanning
+// multiple messages are checked for it: until the given number of exchanges followed it, or until it's older than age.
+func WithSequenceWindow(exchanges int, age time.Duration) Option {
+	return func(o *options) {
+		o.sequenceExchanges = exchanges
+		o.sequenceAge = age
+	}
+}
+
+// WithPendingRequestLimit sets how many requests without a response sessions keep, and for how long. Older requests
+// are dropped, so their responses can't be paired anymore.
+func WithPendingRequestLimit(requests int, age time.Duration) Option {
+	return func(o *options) {
+		o.pendingRequests = requests
+		o.pendingAge = age
+	}
+}
@@ -0,0 +1,305 @@
+package validation
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+// ANSI escape codes used by the pretty printer.
+const (
+	ansiBold  = "1"
+	ansiDim   = "2"
+	ansiRed   = "31"
+	ansiGreen = "32"
+	ansiCyan  = "36"
+)
+
+// prettyPrinter writes human-readable validation results to the console: the results grouped per message,
+// with the failing fields highlighted in the pretty-printed payload, followed by a summary table.
+type prettyPrinter struct {
+	w     io.Writer
+	color bool
+}
+
+func newPrettyPrinter(w io.Writer, color bool) *prettyPrinter {
+	return &prettyPrinter{w: w, color: color}
+}
+
+// Print writes the report.
+func (p *prettyPrinter) Print(r *report.Report) error {
+	if r == nil {
+		return errors.New("report is nil")
+	}
+
+	var b bytes.Buffer
+	for _, messageId := range r.MessageIds() {
+		p.writeMessage(&b, r, messageId)
+	}
+
+	for _, key := range r.NonParsableKeys() {
+		p.writeUnparsable(&b, r, key)
+	}
+
+	p.writeSummary(&b, r)
+
+	_, err := p.w.Write(b.Bytes())
+	return errors.Wrap(err, "failed to write results")
+}
+
+// writeMessage writes the results of a single message, with the details of every invalid part.
+func (p *prettyPrinter) writeMessage(b *bytes.Buffer, r *report.Report, messageId string) {
+	parts := r.Messages[messageId]
+	invalidParts := r.InvalidMessages[messageId]
+
+	action := ""
+	location := ""
+	for _, part := range slices.Sorted(maps.Keys(parts)) {
+		action = cmp.Or(action, parts[part].Action)
+		location = cmp.Or(location, parts[part].Location())
+	}
+
+	status := p.paint("✓", ansiGreen, ansiBold)
+	if len(invalidParts) > 0 {
+		status = p.paint("✗", ansiRed, ansiBold)
+	}
+
+	if len(invalidParts) > 0 {
+		separate(b)
+	}
+
+	_, _ = fmt.Fprintf(b, "%s %s %s  %s\n", status, p.paint(messageId, ansiBold), p.paint(action, ansiCyan), p.paint(location, ansiDim))
+	if len(invalidParts) == 0 {
+		return
+	}
+
+	for _, part := range slices.Sorted(maps.Keys(parts)) {
+		errs, invalid := invalidParts[part]
+		if !invalid {
+			_, _ = fmt.Fprintf(b, "    %s %s\n", part, p.paint("✓", ansiGreen))
+			continue
+		}
+
+		details := parts[part]
+		_, _ = fmt.Fprintf(b, "    %s %s  %s\n", part, p.paint("✗", ansiRed), p.paint(details.Location(), ansiDim))
+		for _, err := range errs {
+			_, _ = fmt.Fprintf(b, "      %s %s\n", p.paint("•", ansiRed), err)
+		}
+
+		if len(details.Payload) > 0 {
+			p.writePayload(b, details.Payload, details.Fields)
+		}
+	}
+
+	b.WriteString("\n")
+}
+
+// writeUnparsable writes the errors and the original frame of a message that could not be parsed.
+func (p *prettyPrinter) writeUnparsable(b *bytes.Buffer, r *report.Report, key string) {
+	details := r.NonParsableDetails[key]
+
+	location := key
+	if details.Location() != "" {
+		location = details.Location()
+	}
+
+	separate(b)
+	_, _ = fmt.Fprintf(b, "%s %s  %s\n", p.paint("✗", ansiRed, ansiBold), p.paint(location, ansiBold), p.paint("unparsable", ansiDim))
+	for _, err := range r.NonParsableMessages[key] {
+		_, _ = fmt.Fprintf(b, "      %s %s\n", p.paint("•", ansiRed), err)
+	}
+
+	if details.Raw != "" {
+		_, _ = fmt.Fprintf(b, "      %s\n", p.paint("> "+details.Raw, ansiDim))
+	}
+
+	b.WriteString("\n")
+}
+
+// writePayload pretty-prints the payload, highlighting the fields that failed validation. Required fields
+// missing from the payload are listed as well.
+func (p *prettyPrinter) writePayload(b *bytes.Buffer, payload json.RawMessage, fields []string) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		// The payload is always marshalled by the report, so this is not expected to happen
+		_, _ = fmt.Fprintf(b, "        %s\n", payload)
+		return
+	}
+
+	p.writeValue(b, "        ", "", value, "", "", fields)
+}
+
+// writeValue writes a JSON value and its children, one line at a time. The path is the JSON pointer of the value.
+func (p *prettyPrinter) writeValue(b *bytes.Buffer, indent, prefix string, value interface{}, path, comma string, fields []string) {
+	invalid := slices.Contains(fields, path)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := slices.Sorted(maps.Keys(v))
+		missing := missingFields(path, v, fields)
+		if len(keys) == 0 && len(missing) == 0 {
+			p.writePayloadLine(b, indent+prefix+"{}"+comma, invalid)
+			return
+		}
+
+		p.writePayloadLine(b, indent+prefix+"{", invalid)
+		for i, key := range keys {
+			p.writeValue(b, indent+"  ", strconv.Quote(key)+": ", v[key], path+"/"+escapePointer(key), separator(i, len(keys)+len(missing)), fields)
+		}
+		for i, key := range missing {
+			line := indent + "  " + strconv.Quote(key) + ": …" + separator(len(keys)+i, len(keys)+len(missing))
+			_, _ = fmt.Fprintf(b, "%s  %s\n", p.paint(line, ansiRed), p.paint("◀ missing", ansiRed, ansiBold))
+		}
+		p.writePayloadLine(b, indent+"}"+comma, false)
+	case []interface{}:
+		if len(v) == 0 {
+			p.writePayloadLine(b, indent+prefix+"[]"+comma, invalid)
+			return
+		}
+
+		p.writePayloadLine(b, indent+prefix+"[", invalid)
+		for i, item := range v {
+			p.writeValue(b, indent+"  ", "", item, path+"/"+strconv.Itoa(i), separator(i, len(v)), fields)
+		}
+		p.writePayloadLine(b, indent+"]"+comma, false)
+	default:
+		scalar, _ := json.Marshal(v)
+		p.writePayloadLine(b, indent+prefix+string(scalar)+comma, invalid)
+	}
+}
+
+// writePayloadLine writes a single line of the payload, highlighted if the field is invalid.
+func (p *prettyPrinter) writePayloadLine(b *bytes.Buffer, line string, invalid bool) {
+	if !invalid {
+		_, _ = fmt.Fprintln(b, line)
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "%s  %s\n", p.paint(line, ansiRed), p.paint("◀ invalid", ansiRed, ansiBold))
+}
+
+// writeSummary writes the summary table, the results by action and the overall result.
+func (p *prettyPrinter) writeSummary(b *bytes.Buffer, r *report.Report) {
+	stats := r.Statistics
+
+	separate(b)
+
+	title := "Summary"
+	if r.Source != "" {
+		title = fmt.Sprintf("Summary of %s", r.Source)
+	}
+	_, _ = fmt.Fprintf(b, "%s\n\n", p.paint(title, ansiBold))
+
+	table := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(table, "\tValid\tInvalid\n")
+	_, _ = fmt.Fprintf(table, "Requests\t%d\t%d\n", stats.ValidRequests, stats.InvalidRequests)
+	_, _ = fmt.Fprintf(table, "Responses\t%d\t%d\n", stats.ValidResponses, stats.InvalidResponses)
+	_, _ = fmt.Fprintf(table, "Unparsable\t\t%d\n", stats.UnparsableMessages)
+	_ = table.Flush()
+
+	if len(stats.ByAction) > 0 {
+		b.WriteString("\n")
+
+		table = tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(table, "Action\tRequests\tResponses\tInvalid rate\n")
+		for _, action := range report.MostInvalid(stats.ByAction) {
+			counts := stats.ByAction[action]
+			_, _ = fmt.Fprintf(table, "%s\t%d valid, %d invalid\t%d valid, %d invalid\t%.2f%%\n",
+				action,
+				counts.ValidRequests, counts.InvalidRequests,
+				counts.ValidResponses, counts.InvalidResponses,
+				counts.InvalidPercentage(),
+			)
+		}
+		_ = table.Flush()
+	}
+
+	b.WriteString("\n")
+	if len(r.InvalidMessages) == 0 && len(r.NonParsableMessages) == 0 {
+		_, _ = fmt.Fprintf(b, "%s\n", p.paint("✓ All messages are valid", ansiGreen, ansiBold))
+		return
+	}
+
+	_, _ = fmt.Fprintf(b, "%s · success rate %.2f%%\n",
+		p.paint(fmt.Sprintf("✗ %d invalid, %d unparsable", len(r.InvalidMessages), len(r.NonParsableMessages)), ansiRed, ansiBold),
+		stats.TotalValidMessagesPercentage(),
+	)
+}
+
+// paint wraps the text in the ANSI escape codes if colors are enabled.
+func (p *prettyPrinter) paint(text string, codes ...string) string {
+	if !p.color || text == "" {
+		return text
+	}
+
+	return "\033[" + strings.Join(codes, ";") + "m" + text + "\033[0m"
+}
+
+// separate separates a block of output from the previous output with an empty line.
+func separate(b *bytes.Buffer) {
+	if b.Len() > 0 && !bytes.HasSuffix(b.Bytes(), []byte("\n\n")) {
+		b.WriteString("\n")
+	}
+}
+
+// missingFields returns the names of the failing fields of an object that are missing from it, e.g. missing
+// required properties.
+func missingFields(path string, object map[string]interface{}, fields []string) []string {
+	var missing []string
+	for _, field := range fields {
+		parent, name, found := cutLast(field, "/")
+		if !found || parent != path {
+			continue
+		}
+
+		name = unescapePointer(name)
+		if _, exists := object[name]; !exists && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	slices.Sort(missing)
+	return missing
+}
+
+// separator returns the separator following the i-th of n values.
+func separator(i, n int) string {
+	if i < n-1 {
+		return ","
+	}
+
+	return ""
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+// escapePointer escapes a key for use in a JSON pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// unescapePointer unescapes a JSON pointer token.
+func unescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+)
+
+func TestPrettyPrinter_Print(t *testing.T) {
+	r := &report.Report{
+		Source: "boot.txt",
+		InvalidMessages: map[string]map[string][]string{
+			"m1": {"request": []string{"Required property 'chargePointModel' is missing", "Value is integer but should be string"}},
+		},
+		ValidMessages:       map[string][]string{"m1": {"response"}, "m2": {"request", "response"}},
+		NonParsableMessages: map[string][]string{"line 5": {"Message is not a valid OCPP message"}},
+		NonParsableDetails:  map[string]report.MessageDetails{"line 5": {File: "boot.txt", Line: 5, Raw: `{"invalid": "json"}`}},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {
+				"request": {
+					File:    "boot.txt",
+					Line:    1,
+					Action:  "BootNotification",
+					Payload: []byte(`{"chargePointVendor":"ABB","firmwareVersion":12,"modem":{"iccid":""}}`),
+					Fields:  []string{"/chargePointModel", "/firmwareVersion"},
+				},
+				"response": {File: "boot.txt", Line: 2, Action: "BootNotification"},
+			},
+			"m2": {
+				"request":  {File: "boot.txt", Line: 3, Action: "Heartbeat"},
+				"response": {File: "boot.txt", Line: 4, Action: "Heartbeat"},
+			},
+		},
+		Statistics: report.Statistics{
+			ValidRequests: 1, ValidResponses: 2, InvalidRequests: 1, UnparsableMessages: 1,
+			ByAction: map[string]report.Counts{
+				"BootNotification": {InvalidRequests: 1, ValidResponses: 1},
+				"Heartbeat":        {ValidRequests: 1, ValidResponses: 1},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, newPrettyPrinter(&b, false).Print(r))
+
+	content := b.String()
+	require.Contains(t, content, "✗ m1 BootNotification  boot.txt:1\n    request ✗  boot.txt:1\n")
+	require.Contains(t, content, "      • Required property 'chargePointModel' is missing\n")
+	// The failing fields are highlighted in the payload, including the missing ones
+	require.Contains(t, content, `
+        {
+          "chargePointVendor": "ABB",
+          "firmwareVersion": 12,  ◀ invalid
+          "modem": {
+            "iccid": ""
+          },
+          "chargePointModel": …  ◀ missing
+        }
+    response ✓
+`)
+	require.Contains(t, content, "\n✓ m2 Heartbeat  boot.txt:3\n")
+	require.Contains(t, content, "✗ boot.txt:5  unparsable\n      • Message is not a valid OCPP message\n      > {\"invalid\": \"json\"}\n")
+	require.Contains(t, content, "Summary of boot.txt")
+	require.Contains(t, content, "Requests    1      1\n")
+	require.Regexp(t, `(?s)BootNotification\s+0 valid, 1 invalid\s+1 valid, 0 invalid\s+50.00%.*Heartbeat`, content)
+	require.Contains(t, content, "✗ 1 invalid, 1 unparsable · success rate 75.00%")
+	require.NotContains(t, content, "\033[")
+}
+
+func TestPrettyPrinter_PrintColor(t *testing.T) {
+	r := &report.Report{
+		ValidMessages: map[string][]string{"m1": {"request"}},
+		Messages:      map[string]map[string]report.MessageDetails{"m1": {"request": {Action: "Heartbeat"}}},
+		Statistics:    report.Statistics{ValidRequests: 1},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, newPrettyPrinter(&b, true).Print(r))
+
+	content := b.String()
+	require.Contains(t, content, "\033[32;1m✓\033[0m \033[1mm1\033[0m \033[36mHeartbeat\033[0m")
+	require.Contains(t, content, "\033[32;1m✓ All messages are valid\033[0m")
+}
+
+func Test_missingFields(t *testing.T) {
+	object := map[string]interface{}{"status": "Accepted"}
+
+	require.Equal(t, []string{"a/b", "expiryDate"}, missingFields("/idTagInfo", object, []string{
+		"/idTagInfo/status",
+		"/idTagInfo/expiryDate",
+		"/idTagInfo/a~1b",
+		"/idTag",
+		"/idTagInfo/nested/field",
+	}))
+	require.Empty(t, missingFields("", object, []string{"/status"}))
+}
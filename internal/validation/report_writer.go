@@ -1,7 +1,10 @@
 package validation
 
 import (
+	"cmp"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -9,32 +12,155 @@ import (
 	"github.com/ChargePi/chargeflow/pkg/report"
 )
 
+// Supported report formats.
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatTXT      = "txt"
+	FormatJUnit    = "junit"
+	FormatSARIF    = "sarif"
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists the supported report formats.
+var Formats = []string{FormatJSON, FormatCSV, FormatTXT, FormatJUnit, FormatSARIF, FormatHTML, FormatMarkdown}
+
+// formatsByExtension maps the file extensions to the report format they select.
+var formatsByExtension = map[string]string{
+	".json":  FormatJSON,
+	".csv":   FormatCSV,
+	".txt":   FormatTXT,
+	".xml":   FormatJUnit,
+	".sarif": FormatSARIF,
+	".html":  FormatHTML,
+	".htm":   FormatHTML,
+	".md":    FormatMarkdown,
+}
+
 // ReportWriter defines how to write a validation report.
 type ReportWriter interface {
 	Write(path string, r *report.Report) error
 }
 
-// outputStrategyFactory returns an ReportWriter based on the file extension.
-func outputStrategyFactory(path string) (ReportWriter, error) {
+// ResolveFormat returns the report format for the given path. An explicit format takes precedence over
+// the file extension.
+func ResolveFormat(path, format string) (string, error) {
+	if format != "" {
+		format = strings.ToLower(format)
+		for _, supported := range Formats {
+			if format == supported {
+				return format, nil
+			}
+		}
+
+		return "", errors.Errorf("unsupported output format: %s", format)
+	}
+
+	// GitHub code scanning recognizes both .sarif and .sarif.json files
+	if strings.HasSuffix(strings.ToLower(path), ".sarif.json") {
+		return FormatSARIF, nil
+	}
+
 	ext := strings.ToLower(filepath.Ext(path))
-	switch ext {
-	case ".json":
+	format, found := formatsByExtension[ext]
+	if !found {
+		return "", errors.Errorf("unsupported output extension: %s", ext)
+	}
+
+	return format, nil
+}
+
+// outputStrategyFactory returns an ReportWriter based on the format, or the file extension if no format is set.
+func outputStrategyFactory(path, format string) (ReportWriter, error) {
+	format, err := ResolveFormat(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
 		return jsonStrategy{}, nil
-	case ".csv":
+	case FormatCSV:
 		return csvWriter{}, nil
-	case ".txt":
+	case FormatTXT:
 		return txtWriter{}, nil
+	case FormatJUnit:
+		return junitWriter{}, nil
+	case FormatHTML:
+		return htmlWriter{}, nil
+	case FormatMarkdown:
+		return markdownWriter{}, nil
 	default:
-		return nil, errors.Errorf("unsupported output extension: %s", ext)
+		return sarifWriter{}, nil
 	}
 }
 
 // WriteReport is a convenience exported helper that writes the report using the
 // appropriate ReportWriter based on the provided path extension.
 func WriteReport(path string, r *report.Report) error {
-	strat, err := outputStrategyFactory(path)
+	strat, err := outputStrategyFactory(path, "")
 	if err != nil {
 		return err
 	}
 	return strat.Write(path, r)
 }
+
+// unknownAction is used for messages whose action can't be determined, e.g. a response without its request.
+const unknownAction = "unknown"
+
+// actionSummary is the number of valid messages out of all messages of an action.
+type actionSummary struct {
+	Name  string
+	Valid int
+	Total int
+}
+
+// PassRate returns the percentage of valid messages.
+func (a actionSummary) PassRate() float64 {
+	return getPercentage(a.Valid, a.Total)
+}
+
+// FailRate returns the percentage of invalid messages.
+func (a actionSummary) FailRate() float64 {
+	return 100 - a.PassRate()
+}
+
+// summarizeActions counts the valid and invalid messages per action, sorted by action.
+func summarizeActions(r *report.Report) []actionSummary {
+	actions := map[string]*actionSummary{}
+	for messageId, parts := range r.Messages {
+		name := messageAction(parts)
+
+		action, found := actions[name]
+		if !found {
+			action = &actionSummary{Name: name}
+			actions[name] = action
+		}
+
+		action.Total++
+		if _, invalid := r.InvalidMessages[messageId]; !invalid {
+			action.Valid++
+		}
+	}
+
+	summaries := make([]actionSummary, 0, len(actions))
+	for _, name := range slices.Sorted(maps.Keys(actions)) {
+		summaries = append(summaries, *actions[name])
+	}
+
+	return summaries
+}
+
+// messageAction returns the action of a message, preferring the request's.
+func messageAction(parts map[string]report.MessageDetails) string {
+	return cmp.Or(parts["request"].Action, parts["response"].Action, unknownAction)
+}
+
+func getPercentage(fraction, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(fraction) / float64(total) * 100
+}
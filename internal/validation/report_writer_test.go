@@ -1,7 +1,6 @@
 package validation
 
 import (
-	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -9,35 +8,35 @@ import (
 
 func TestOutputStrategyFactory(t *testing.T) {
 	tests := []struct {
-		name    string
-		path    string
-		wantErr bool
+		name     string
+		path     string
+		format   string
+		expected ReportWriter
+		wantErr  bool
 	}{
-		{"json", "a.json", false},
-		{"csv", "b.csv", false},
-		{"txt", "c.txt", false},
-		{"bad", "d.unknown", true},
+		{"json", "a.json", "", jsonStrategy{}, false},
+		{"csv", "b.csv", "", csvWriter{}, false},
+		{"txt", "c.txt", "", txtWriter{}, false},
+		{"junit", "e.xml", "", junitWriter{}, false},
+		{"sarif", "f.sarif", "", sarifWriter{}, false},
+		{"sarif json", "g.sarif.json", "", sarifWriter{}, false},
+		{"html", "h.html", "", htmlWriter{}, false},
+		{"markdown", "i.md", "", markdownWriter{}, false},
+		{"explicit format", "report.out", "junit", junitWriter{}, false},
+		{"explicit format overrides extension", "report.json", "SARIF", sarifWriter{}, false},
+		{"bad", "d.unknown", "", nil, true},
+		{"bad format", "a.json", "yaml", nil, true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			strat, err := outputStrategyFactory(tt.path)
+			strat, err := outputStrategyFactory(tt.path, tt.format)
 			if tt.wantErr {
 				require.Error(t, err)
 				return
 			}
 			require.NoError(t, err)
-
-			// ensure strategy concrete type via extension
-			ext := filepath.Ext(tt.path)
-			switch ext {
-			case ".json":
-				require.IsType(t, jsonStrategy{}, strat)
-			case ".csv":
-				require.IsType(t, csvWriter{}, strat)
-			case ".txt":
-				require.IsType(t, txtWriter{}, strat)
-			}
+			require.IsType(t, tt.expected, strat)
 		})
 	}
 }
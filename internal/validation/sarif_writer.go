@@ -0,0 +1,271 @@
+package validation
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	ruleInvalidRequest  = "invalid-request"
+	ruleInvalidResponse = "invalid-response"
+	ruleSemantic        = "semantic-rule"
+	ruleSequence        = "sequence-rule"
+	ruleCustom          = "custom-rule"
+	ruleUnparsable      = "unparsable-message"
+
+	// sarifSourceRoot is the base of the artifact URIs, resolved by code scanning to the root of the repository.
+	sarifSourceRoot = "%SRCROOT%"
+)
+
+var sarifRules = []sarifRule{
+	{
+		Id:               ruleInvalidRequest,
+		ShortDescription: sarifMessage{Text: "OCPP request does not conform to the schema or the OCPP-J message structure"},
+	},
+	{
+		Id:               ruleInvalidResponse,
+		ShortDescription: sarifMessage{Text: "OCPP response does not conform to the schema or the OCPP-J message structure"},
+	},
+	{
+		Id:               ruleSemantic,
+		ShortDescription: sarifMessage{Text: "OCPP message violates a built-in semantic rule"},
+	},
+	{
+		Id:               ruleSequence,
+		ShortDescription: sarifMessage{Text: "OCPP message violates a built-in rule on the sequence of messages"},
+	},
+	{
+		Id:               ruleCustom,
+		ShortDescription: sarifMessage{Text: "OCPP message violates a user-defined rule of the rules file"},
+	},
+	{
+		Id:               ruleUnparsable,
+		ShortDescription: sarifMessage{Text: "Message is not a valid OCPP-J message"},
+	},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	OriginalUriBaseIds map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+	Properties         sarifRunProperties               `json:"properties"`
+}
+
+// sarifRunProperties is the property bag of the run, carrying the statistics of the report.
+type sarifRunProperties struct {
+	Statistics report.Statistics `json:"statistics"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	Uri       string `json:"uri"`
+	UriBaseId string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifWriter implements ReportWriter for SARIF output, which can be uploaded to GitHub code scanning
+// to annotate the validated file.
+type sarifWriter struct {
+	// rules are the rules of the validator, to tell violations of user-defined rules apart. Built-in rules are
+	// always known.
+	rules []validator.Rule
+}
+
+func (w sarifWriter) Write(path string, r *report.Report) error {
+	if r == nil {
+		return errors.New("report is nil")
+	}
+
+	// Without a working directory, the artifact URIs are written as they are
+	root, _ := os.Getwd()
+	ruleKinds := make(map[string]string)
+	for _, rule := range validator.BuiltinRules() {
+		ruleKinds[rule.ID] = ruleSemantic
+		if rule.CheckSequence != nil {
+			ruleKinds[rule.ID] = ruleSequence
+		}
+	}
+
+	for _, rule := range w.rules {
+		if _, builtin := ruleKinds[rule.ID]; !builtin {
+			ruleKinds[rule.ID] = ruleCustom
+		}
+	}
+
+	results := []sarifResult{}
+
+	for _, messageId := range r.InvalidMessageIds() {
+		parts := r.InvalidMessages[messageId]
+		for _, part := range slices.Sorted(maps.Keys(parts)) {
+			details := r.Messages[messageId][part]
+
+			for _, validationErr := range parts[part] {
+				results = append(results, sarifResult{
+					RuleId:    sarifRuleId(ruleKinds, part, validationErr),
+					Level:     "error",
+					Message:   sarifMessage{Text: fmt.Sprintf("%s of message %s: %s", capitalize(part), messageId, validationErr)},
+					Locations: sarifLocations(root, cmp.Or(details.File, r.Source), details.Line),
+				})
+			}
+		}
+	}
+
+	for _, key := range r.NonParsableKeys() {
+		details := r.NonParsableDetails[key]
+		for _, parseErr := range r.NonParsableMessages[key] {
+			results = append(results, sarifResult{
+				RuleId:    ruleUnparsable,
+				Level:     "error",
+				Message:   sarifMessage{Text: fmt.Sprintf("Message at %s could not be parsed: %s", key, parseErr)},
+				Locations: sarifLocations(root, cmp.Or(details.File, r.Source), cmp.Or(details.Line, lineFromKey(key))),
+			})
+		}
+	}
+
+	b, err := json.MarshalIndent(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "chargeflow",
+				InformationUri: "https://github.com/ChargePi/chargeflow",
+				Rules:          sarifRules,
+			}},
+			OriginalUriBaseIds: sarifOriginalUriBaseIds(root),
+			Results:            results,
+			Properties:         sarifRunProperties{Statistics: r.Statistics},
+		}},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, b, 0644); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// sarifRuleId returns the SARIF rule of a validation error of the request or response. Violations of semantic,
+// sequence and user-defined rules are prefixed with the ID of the rule, see ruleKinds. Any other error is an error
+// of the schema or the message structure.
+func sarifRuleId(ruleKinds map[string]string, part, validationErr string) string {
+	id, _, _ := strings.Cut(validationErr, ": ")
+	if kind, found := ruleKinds[id]; found {
+		return kind
+	}
+
+	if part == "request" {
+		return ruleInvalidRequest
+	}
+
+	return ruleInvalidResponse
+}
+
+// sarifOriginalUriBaseIds returns the location of the source root the artifact URIs are relative to.
+func sarifOriginalUriBaseIds(root string) map[string]sarifArtifactLocation {
+	if root == "" {
+		return nil
+	}
+
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(root)}).String()
+	return map[string]sarifArtifactLocation{sarifSourceRoot: {Uri: strings.TrimSuffix(uri, "/") + "/"}}
+}
+
+// sarifLocations returns the location of a result in the source file, relative to the root if the file is within
+// it. Results of inline messages have no location.
+func sarifLocations(root, source string, line int) []sarifLocation {
+	if source == "" {
+		return nil
+	}
+
+	artifact := sarifArtifactLocation{Uri: filepath.ToSlash(source)}
+	if root != "" {
+		absolute, err := filepath.Abs(source)
+		if err == nil {
+			relative, err := filepath.Rel(root, absolute)
+			if err == nil && filepath.IsLocal(relative) {
+				artifact = sarifArtifactLocation{Uri: filepath.ToSlash(relative), UriBaseId: sarifSourceRoot}
+			}
+		}
+	}
+
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact},
+	}
+
+	if line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+
+	return []sarifLocation{location}
+}
+
+// lineFromKey returns the line number of a non-parsable message key ("line N"), or 0 if unknown.
+func lineFromKey(key string) int {
+	line, err := strconv.Atoi(strings.TrimPrefix(key, "line "))
+	if err != nil {
+		return 0
+	}
+
+	return line
+}
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/pkg/report"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+func TestSARIFWriter_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.sarif")
+
+	r := &report.Report{
+		Source: "fixtures/messages.txt",
+		InvalidMessages: map[string]map[string][]string{
+			"m1": {"request": []string{"e1"}, "response": []string{"e2"}},
+		},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {"request": {File: "fixtures/messages.txt", Line: 2}, "response": {File: "fixtures/messages.txt", Line: 3}},
+		},
+		NonParsableMessages: map[string][]string{"line 4": {"pe1"}},
+		Statistics:          report.Statistics{InvalidRequests: 1, ByAction: map[string]report.Counts{"Authorize": {InvalidRequests: 1}}},
+	}
+
+	require.NoError(t, sarifWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(b, &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Tool.Driver.Rules, 6)
+	require.Equal(t, r.Statistics, log.Runs[0].Properties.Statistics)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 3)
+	require.Equal(t, ruleInvalidRequest, results[0].RuleId)
+	require.Equal(t, ruleInvalidResponse, results[1].RuleId)
+	require.Equal(t, ruleUnparsable, results[2].RuleId)
+
+	for i, line := range []int{2, 3, 4} {
+		location := results[i].Locations[0].PhysicalLocation
+		require.Equal(t, "fixtures/messages.txt", location.ArtifactLocation.Uri)
+		require.Equal(t, sarifSourceRoot, location.ArtifactLocation.UriBaseId)
+		require.NotNil(t, location.Region)
+		require.Equal(t, line, location.Region.StartLine)
+	}
+}
+
+func TestSARIFWriter_WriteRuleKinds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.sarif")
+
+	r := &report.Report{
+		InvalidMessages: map[string]map[string][]string{
+			"m1": {"request": []string{
+				"Required property 'idTag' is missing",
+				"ocpp16.stack-level: stackLevel must not be negative, got -1",
+				"acme.interval: interval is not satisfied",
+			}},
+			"m2": {"response": []string{
+				"data: value is not valid",
+				"ocpp16.trigger-message: TriggerMessage was accepted, but no Heartbeat followed",
+			}},
+		},
+	}
+
+	writer := sarifWriter{rules: []validator.Rule{{ID: "ocpp16.stack-level"}, {ID: "acme.interval"}}}
+	require.NoError(t, writer.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(b, &log))
+
+	var ruleIds []string
+	for _, result := range log.Runs[0].Results {
+		ruleIds = append(ruleIds, result.RuleId)
+	}
+	require.Equal(t, []string{ruleInvalidRequest, ruleSemantic, ruleCustom, ruleInvalidResponse, ruleSequence}, ruleIds)
+}
+
+func TestSARIFWriter_WriteRelativeUris(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.sarif")
+
+	root, err := os.Getwd()
+	require.NoError(t, err)
+
+	outside := filepath.Join(t.TempDir(), "messages.txt")
+	r := &report.Report{
+		InvalidMessages: map[string]map[string][]string{"m1": {"request": []string{"e1"}}},
+		Messages: map[string]map[string]report.MessageDetails{
+			"m1": {"request": {File: filepath.Join(root, "fixtures", "messages.txt"), Line: 2}},
+		},
+		NonParsableMessages: map[string][]string{"line 1": {"pe1"}},
+		NonParsableDetails:  map[string]report.MessageDetails{"line 1": {File: outside, Line: 1}},
+	}
+
+	require.NoError(t, sarifWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(b, &log))
+	require.Contains(t, log.Runs[0].OriginalUriBaseIds, sarifSourceRoot)
+
+	// Files within the working directory are relative to it
+	results := log.Runs[0].Results
+	require.Equal(t, sarifArtifactLocation{Uri: "fixtures/messages.txt", UriBaseId: sarifSourceRoot}, results[0].Locations[0].PhysicalLocation.ArtifactLocation)
+	// Other files keep their path
+	require.Equal(t, sarifArtifactLocation{Uri: filepath.ToSlash(outside)}, results[1].Locations[0].PhysicalLocation.ArtifactLocation)
+}
+
+func TestSARIFWriter_WriteInlineMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.sarif")
+
+	r := &report.Report{
+		InvalidMessages: map[string]map[string][]string{"m1": {"request": []string{"e1"}}},
+	}
+
+	require.NoError(t, sarifWriter{}.Write(path, r))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(b, &log))
+	require.Len(t, log.Runs[0].Results, 1)
+	require.Empty(t, log.Runs[0].Results[0].Locations)
+}
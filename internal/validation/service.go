@@ -5,7 +5,9 @@ import (
 	"io"
 	"maps"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -17,24 +19,53 @@ import (
 	"github.com/ChargePi/chargeflow/pkg/validator"
 )
 
+// Service parses, validates and reports on OCPP messages. The parser and aggregator are
+// stateful, so a fresh pair is created for every Validate call; this makes a single Service
+// safe to reuse across runs (e.g. by a long-running server).
 type Service struct {
-	logger     *zap.Logger
-	registry   schema_registry.SchemaRegistry
-	parser     *parser.ParserV2
-	validator  *validator.Validator
-	aggregator *report.Aggregator
+	logger    *zap.Logger
+	registry  schema_registry.SchemaRegistry
+	validator *validator.Validator
+	observer  Observer
+	stdout    io.Writer
+	color     bool
+
+	// sequenceExchanges and sequenceAge are the window of the sequence checks of sessions.
+	sequenceExchanges int
+	sequenceAge       time.Duration
+	// pendingRequests and pendingAge limit the requests without a response sessions keep.
+	pendingRequests int
+	pendingAge      time.Duration
 }
 
 func NewService(
 	logger *zap.Logger,
 	registry schema_registry.SchemaRegistry,
+	opts ...Option,
 ) *Service {
+	config := options{
+		stdout:            os.Stdout,
+		sequenceExchanges: defaultSequenceExchanges,
+		sequenceAge:       defaultSequenceAge,
+		pendingRequests:   defaultPendingRequests,
+		pendingAge:        defaultPendingAge,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
 	return &Service{
-		logger:     logger,
-		registry:   registry,
-		parser:     parser.NewParserV2(logger),
-		validator:  validator.NewValidator(logger, registry),
-		aggregator: report.NewAggregator(logger),
+		logger:    logger,
+		registry:  registry,
+		validator: validator.NewValidator(logger, registry, config.validatorOptions...),
+		observer:  config.observer,
+		stdout:    config.stdout,
+		color:     config.color,
+
+		sequenceExchanges: config.sequenceExchanges,
+		sequenceAge:       config.sequenceAge,
+		pendingRequests:   config.pendingRequests,
+		pendingAge:        config.pendingAge,
 	}
 }
 
@@ -42,14 +73,17 @@ func NewService(
 // the registry attempts vendor/model-specific schemas before falling back to the base OCPP spec schemas.
 func (s *Service) Validate(req Request) (*report.Report, error) {
 	logger := s.logger.With(
-		zap.String("ocppVersion", req.OcppContext.Version.String()),
+		zap.String("ocppVersion", req.OcppContext.Version.Name()),
 		zap.String("vendor", req.OcppContext.Vendor),
 		zap.String("model", req.OcppContext.Model),
 	)
 	logger.Info("Validating messages")
 
 	msgs := req.Messages
+	source := ""
 	if len(msgs) == 0 && req.File != "" {
+		source = req.File
+
 		var err error
 		msgs, err = s.getMessagesFromFile(req.File)
 		if err != nil {
@@ -57,18 +91,27 @@ func (s *Service) Validate(req Request) (*report.Report, error) {
 		}
 	}
 
-	validationReport, err := s.parseAndValidate(req.OcppContext, msgs)
+	validationReport, err := s.parseAndValidate(req.OcppContext, source, msgs)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse and validate messages")
 	}
 
-	s.outputValidationErrorToLogs(validationReport)
+	validationReport.Source = source
+	if err := s.writeConsole(req.Console, validationReport); err != nil {
+		return nil, err
+	}
 
 	if req.Output != "" {
-		strat, err := outputStrategyFactory(req.Output)
+		strat, err := outputStrategyFactory(req.Output, req.Format)
 		if err != nil {
 			return nil, err
 		}
+
+		// User-defined rules are only known to the validator
+		if writer, ok := strat.(sarifWriter); ok {
+			writer.rules = s.validator.Rules()
+			strat = writer
+		}
 		if err := strat.Write(req.Output, validationReport); err != nil {
 			return nil, errors.Wrap(err, "failed to write validation report")
 		}
@@ -84,17 +127,26 @@ func (s *Service) outputValidationErrorToLogs(validationReport *report.Report) {
 		return
 	}
 
-	for line, errs := range validationReport.NonParsableMessages {
+	for _, line := range validationReport.NonParsableKeys() {
+		errs := validationReport.NonParsableMessages[line]
 		logger := s.logger.With(zap.String("line", line))
+		if details, found := validationReport.NonParsableDetails[line]; found {
+			logger = logger.With(zap.String("raw", details.Raw))
+		}
 		logger.Error(fmt.Sprintf("Message could not be parsed at %s:", line))
 		for _, parseErr := range errs {
 			logger.Error(fmt.Sprintf("👉 %s", parseErr))
 		}
 	}
 
-	for messageId, requestResponse := range validationReport.InvalidMessages {
-		for k, validationErrors := range requestResponse {
+	for _, messageId := range validationReport.InvalidMessageIds() {
+		requestResponse := validationReport.InvalidMessages[messageId]
+		for _, k := range slices.Sorted(maps.Keys(requestResponse)) {
+			validationErrors := requestResponse[k]
 			logger := s.logger.With(zap.String("messageId", messageId))
+			if details, found := validationReport.Messages[messageId][k]; found && details.Location() != "" {
+				logger = logger.With(zap.String("location", details.Location()))
+			}
 			switch k {
 			case "request":
 				logger.Error(fmt.Sprintf("Request for message %s has the following validation errors:", messageId))
@@ -109,32 +161,52 @@ func (s *Service) outputValidationErrorToLogs(validationReport *report.Report) {
 	}
 }
 
-// parseAndValidate parses and validates a list of OCPP messages.
-func (s *Service) parseAndValidate(octx ocpp.OcppContext, messages []string) (*report.Report, error) {
-	logger := s.logger.With(zap.String("ocppVersion", octx.Version.String()), zap.Int("messages", len(messages)))
+// parseAndValidate parses and validates a list of OCPP messages, read from the given file if it's not empty.
+func (s *Service) parseAndValidate(octx ocpp.OcppContext, file string, messages []string) (*report.Report, error) {
+	logger := s.logger.With(zap.String("ocppVersion", octx.Version.Name()), zap.Int("messages", len(messages)))
 	logger.Info("Parsing and validating messages")
 
-	parserResults, nonParsedMessages, err := s.parser.Parse(messages)
+	messageParser := parser.NewParserV2(s.logger, parser.WithFile(file))
+	aggregator := report.NewAggregator(s.logger, report.WithVendorModel(octx.Vendor, octx.Model))
+
+	parserResults, nonParsedMessages, err := messageParser.Parse(messages)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse messages")
 	}
 
 	for line, result := range nonParsedMessages {
-		s.aggregator.AddNonParsableMessage(line, result)
+		aggregator.AddNonParsableMessage(line, result)
+		s.observeUnparsable(octx)
 	}
 
 	for messageId, result := range parserResults {
+		action := requestAction(result)
+
 		_, found := result.GetRequest()
 		if found {
-			s.aggregator.AddParserResult(messageId, true, result.Request)
+			aggregator.AddParserResult(messageId, true, result.Request)
 		}
 
 		_, found = result.GetResponse()
 		if found {
-			s.aggregator.AddParserResult(messageId, false, result.Response)
+			aggregator.AddParserResult(messageId, false, result.Response)
+		} else if _, found = result.GetResponseError(); found {
+			aggregator.AddParserResult(messageId, false, result.ResponseError)
+		}
+
+		if errs := result.Request.Errors(); len(errs) > 0 {
+			s.observeParserResult(octx, action, errs)
+		}
+
+		if errs := result.Response.Errors(); len(errs) > 0 {
+			s.observeParserResult(octx, action, errs)
 		}
 	}
 
+	// Rules spanning multiple messages are checked before the invalid messages are filtered out, as a follow-up
+	// message doesn't need a valid response to count
+	sequenceResults := s.validator.ValidateSequence(octx, parserResults)
+
 	validMessages := s.filterValidMessages(parserResults)
 	invalidMessagesCount := len(parserResults) - len(validMessages)
 	logger.Info("✅ OCPP messages parsed. Proceeding with validation.",
@@ -143,38 +215,50 @@ func (s *Service) parseAndValidate(octx ocpp.OcppContext, messages []string) (*r
 	)
 
 	for messageId, parserResult := range validMessages {
+		action := requestAction(parserResult)
+
 		request, found := parserResult.GetRequest()
 		if found {
-			result, err := s.validator.ValidateMessage(octx, request)
+			result, err := s.validateMessage(octx, action, nil, request)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to validate request message")
 			}
-			s.aggregator.AddValidationResults(messageId, true, *result)
+			aggregator.AddValidationResults(messageId, true, *result)
 		}
 
 		response, found := parserResult.GetResponse()
 		if found {
-			result, err := s.validator.ValidateMessage(octx, response)
+			result, err := s.validateMessage(octx, action, request, response)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to validate response message")
 			}
-			s.aggregator.AddValidationResults(messageId, false, *result)
+			result.Merge(sequenceResults[messageId])
+			aggregator.AddValidationResults(messageId, false, *result)
 		}
 
 		responseError, found := parserResult.GetResponseError()
 		if found {
-			result, err := s.validator.ValidateMessage(octx, responseError)
+			result, err := s.validateMessage(octx, action, request, responseError)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to validate response error message")
 			}
-			s.aggregator.AddValidationResults(messageId, false, *result)
+			aggregator.AddValidationResults(messageId, false, *result)
 		}
 	}
 
-	validationReport := s.aggregator.CreateReport()
+	validationReport := aggregator.CreateReport()
 	return &validationReport, nil
 }
 
+// requestAction returns the action of the request, or an empty string if there is no request.
+func requestAction(result parser.RequestResponseResult) string {
+	if request, found := result.GetRequest(); found {
+		return request.GetAction()
+	}
+
+	return ""
+}
+
 // getMessagesFromFile reads newline-delimited OCPP messages from a file.
 func (s *Service) getMessagesFromFile(file string) ([]string, error) {
 	s.logger.Debug("Reading file", zap.String("file", file))
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"strings"
@@ -15,6 +16,8 @@ import (
 	mock_schema_registry "github.com/ChargePi/chargeflow/gen/mocks/pkg/schema_registry"
 	"github.com/ChargePi/chargeflow/pkg/ocpp"
 	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
+	"github.com/ChargePi/chargeflow/pkg/schemas"
 )
 
 var (
@@ -410,6 +413,102 @@ func (s *validationServiceTestSuite) TestValidate_Message() {
 	}
 }
 
+func (s *validationServiceTestSuite) TestValidate_Console() {
+	req := Request{OcppContext: ocpp.OcppContext{Version: ocpp.V16}, Messages: []string{unparsableMsg}}
+
+	tests := []struct {
+		console  string
+		expected string
+	}{
+		{console: ConsolePretty, expected: "✗ line 1  unparsable"},
+		{console: ConsoleJSON, expected: `"non_parsable_messages": {`},
+		{console: ConsoleQuiet, expected: ""},
+		{console: ConsoleLogs, expected: ""},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.console, func() {
+			var stdout strings.Builder
+			service := NewService(s.logger, mock_schema_registry.NewMockSchemaRegistry(s.T()), WithStdout(&stdout))
+
+			req.Console = tt.console
+			_, err := service.Validate(req)
+			s.Require().NoError(err)
+
+			if tt.expected == "" {
+				s.Empty(stdout.String())
+			} else {
+				s.Contains(stdout.String(), tt.expected)
+			}
+		})
+	}
+
+	req.Console = "table"
+	_, err := NewService(s.logger, mock_schema_registry.NewMockSchemaRegistry(s.T())).Validate(req)
+	s.ErrorContains(err, `unknown console mode "table"`)
+}
+
+func (s *validationServiceTestSuite) TestValidate_Sequence() {
+	permissiveSchema, err := jsonschema.NewCompiler().Compile([]byte(`{"type": "object"}`))
+	s.Require().NoError(err)
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(s.T())
+	registry.EXPECT().GetSchema(mock.Anything, mock.Anything).Return(permissiveSchema, true)
+
+	req := Request{
+		OcppContext: ocpp.OcppContext{Version: ocpp.V16},
+		Messages: []string{
+			`[2, "1", "TriggerMessage", {"requestedMessage": "Heartbeat"}]`,
+			`[3, "1", {"status": "Accepted"}]`,
+			`[2, "2", "TriggerMessage", {"requestedMessage": "BootNotification"}]`,
+			`[3, "2", {"status": "Accepted"}]`,
+			`[2, "3", "Heartbeat", {}]`,
+		},
+	}
+
+	r, err := NewService(s.logger, registry).Validate(req)
+	s.Require().NoError(err)
+	s.Equal(map[string]map[string][]string{
+		"2": {"response": {"ocpp16.trigger-message: TriggerMessage was accepted, but no BootNotification followed"}},
+	}, r.InvalidMessages)
+	s.Equal([]string{"/status"}, r.Messages["2"]["response"].Fields)
+}
+
+func (s *validationServiceTestSuite) TestValidate_Soap() {
+	registry := file_registry.NewFileSchemaRegistry(s.logger)
+	s.Require().NoError(schemas.Register(context.Background(), registry, ocpp.V15))
+
+	envelope := func(header, body string) string {
+		return `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://www.w3.org/2005/08/addressing" xmlns:cs="urn://Ocpp/Cs/2012/06/">` +
+			"<s:Header><cs:chargeBoxIdentity>CB1</cs:chargeBoxIdentity>" + header + "</s:Header><s:Body>" + body + "</s:Body></s:Envelope>"
+	}
+
+	req := Request{
+		OcppContext: ocpp.OcppContext{Version: ocpp.V15},
+		Messages: []string{
+			envelope("<a:Action>/StartTransaction</a:Action><a:MessageID>1</a:MessageID>",
+				"<cs:startTransactionRequest><cs:connectorId>1</cs:connectorId><cs:idTag>TAG</cs:idTag>"+
+					"<cs:timestamp>2013-02-01T15:09:18Z</cs:timestamp><cs:meterStart>0</cs:meterStart></cs:startTransactionRequest>"),
+			envelope("<a:Action>/StartTransactionResponse</a:Action><a:RelatesTo>1</a:RelatesTo>",
+				"<cs:startTransactionResponse><cs:transactionId>1</cs:transactionId><cs:idTagInfo><cs:status>Unknown</cs:status></cs:idTagInfo></cs:startTransactionResponse>"),
+			envelope("<a:Action>/Heartbeat</a:Action><a:MessageID>2</a:MessageID>", "<cs:heartbeatRequest/>"),
+			envelope("<a:RelatesTo>2</a:RelatesTo>",
+				"<s:Fault><s:Code><s:Value>s:Sender</s:Value><s:Subcode><s:Value>cs:IdentityMismatch</s:Value></s:Subcode></s:Code></s:Fault>"),
+		},
+	}
+
+	r, err := NewService(s.logger, registry).Validate(req)
+	s.Require().NoError(err)
+	s.Empty(r.NonParsableMessages)
+	s.Equal(map[string]map[string][]string{
+		"1": {"response": {"Property 'idTagInfo' does not match the schema"}},
+	}, r.InvalidMessages)
+	s.Equal([]string{"/idTagInfo/status"}, r.Messages["1"]["response"].Fields)
+	s.Equal("CB1", r.Messages["1"]["request"].ChargeBoxIdentity)
+	s.JSONEq(`{"connectorId": 1, "idTag": "TAG", "timestamp": "2013-02-01T15:09:18Z", "meterStart": 0}`, string(r.Messages["1"]["request"].Payload))
+	s.Equal("IdentityMismatch", r.Messages["2"]["response"].Action)
+}
+
 func TestValidationService(t *testing.T) {
 	suite.Run(t, new(validationServiceTestSuite))
 }
@@ -0,0 +1,315 @@
+package validation
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/parser"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+const (
+	noMatchingRequestErr = "unable to determine response type: no matching request found"
+
+	// defaultSequenceExchanges and defaultSequenceAge are the default window of the sequence checks of sessions.
+	defaultSequenceExchanges = 200
+	defaultSequenceAge       = 5 * time.Minute
+
+	// defaultPendingRequests and defaultPendingAge limit the requests without a response a session keeps by default.
+	defaultPendingRequests = 1000
+	defaultPendingAge      = 5 * time.Minute
+)
+
+// FrameResult is the outcome of validating a single OCPP-J frame.
+type FrameResult struct {
+	// UniqueId of the message, or "line N" if the frame could not be parsed.
+	UniqueId string
+	// Action of the message. For responses, the action is taken from the matching request.
+	Action      string
+	MessageType ocpp.MessageType
+	// IsRequest is true for CALL and SEND frames.
+	IsRequest bool
+	// Parsable is false when the frame is not a structurally valid OCPP-J message.
+	Parsable bool
+	Valid    bool
+	Errors   []string
+	// Sequence are the results of earlier messages of the session that violate rules spanning multiple messages,
+	// e.g. an accepted RemoteStartTransaction that no StartTransaction followed. They are reported once the
+	// messages left the sequence window (see WithSequenceWindow).
+	Sequence []SequenceResult
+}
+
+// SequenceResult is the outcome of checking the rules spanning multiple messages for an exchange of a session.
+type SequenceResult struct {
+	// UniqueId of the exchange.
+	UniqueId string
+	// Action of the request.
+	Action string
+	Errors []string
+}
+
+// pendingRequest is a request of a session waiting for its response.
+type pendingRequest struct {
+	uniqueId string
+	arrived  time.Time
+}
+
+// sequenceEntry is an exchange of a session the rules spanning multiple messages are checked against.
+type sequenceEntry struct {
+	exchange validator.Exchange
+	// arrived is when the request of the exchange arrived.
+	arrived time.Time
+}
+
+// Session validates a stream of individual OCPP-J frames (e.g. the traffic of a single charge point),
+// pairing responses with the requests seen earlier in the same session. Completed request/response pairs
+// are dropped from the session to keep its memory bounded, as are requests that weren't answered in time
+// (see WithPendingRequestLimit). A Session is safe for concurrent use.
+//
+// Rules spanning multiple messages are checked for an exchange once enough exchanges followed it, or once it's
+// old enough, and reported with the frame that completed the window (see FrameResult.Sequence). Earlier exchanges
+// are kept as context up to the same number of exchanges.
+type Session struct {
+	mu      sync.Mutex
+	service *Service
+	parser  *parser.ParserV2
+	line    int
+
+	// pending are the requests waiting for a response in the order they arrived, by unique ID in pendingRequests.
+	pending         []*pendingRequest
+	pendingRequests map[string]*pendingRequest
+
+	// sequence are the recent exchanges in the order their requests arrived, by unique ID in exchanges.
+	// The sequence checks of the first checked exchanges are final.
+	sequence  []*sequenceEntry
+	exchanges map[string]*sequenceEntry
+	checked   int
+	// octx is the OCPP context of the last frame, used when closing the session.
+	octx ocpp.OcppContext
+}
+
+// NewSession creates a new stateful session for validating frames one by one.
+func (s *Service) NewSession() *Session {
+	return &Session{
+		service:         s,
+		parser:          parser.NewParserV2(s.logger),
+		pendingRequests: make(map[string]*pendingRequest),
+		exchanges:       make(map[string]*sequenceEntry),
+	}
+}
+
+// ValidateFrame parses a single frame, pairs it with earlier frames of the session and validates it.
+func (s *Session) ValidateFrame(octx ocpp.OcppContext, frame string) (*FrameResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.line++
+	return s.validateFrameInSequence(octx, s.line, frame)
+}
+
+// ValidateFrameAt is like ValidateFrame, but uses the given line (e.g. of a log file) to identify unparsable frames.
+func (s *Session) ValidateFrameAt(octx ocpp.OcppContext, line int, frame string) (*FrameResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.validateFrameInSequence(octx, line, frame)
+}
+
+// Close checks the rules spanning multiple messages for the exchanges still in the sequence window, e.g. once the
+// connection is closed, and returns the results with violations. Frames validated afterward start a new window.
+func (s *Session) Close() []SequenceResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := s.checkSequence(s.octx, true)
+	s.sequence = nil
+	s.checked = 0
+	clear(s.exchanges)
+	return results
+}
+
+// validateFrameInSequence validates the frame and checks the rules spanning multiple messages for the exchanges
+// that left the sequence window.
+func (s *Session) validateFrameInSequence(octx ocpp.OcppContext, line int, frame string) (*FrameResult, error) {
+	s.octx = octx
+	s.dropPendingRequests()
+
+	result, err := s.validateFrame(octx, line, frame)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Sequence = s.checkSequence(octx, false)
+	return result, nil
+}
+
+func (s *Session) validateFrame(octx ocpp.OcppContext, line int, frame string) (*FrameResult, error) {
+	key, typeId, parsable := s.parser.ParseNext(line, frame)
+
+	result := &FrameResult{
+		UniqueId:    key,
+		MessageType: typeId,
+		IsRequest:   typeId == ocpp.CALL || typeId == ocpp.SEND,
+		Parsable:    parsable,
+	}
+
+	if !parsable {
+		nonParsable, _ := s.parser.NonParsable(key)
+		result.Errors = nonParsable.Errors()
+		s.parser.Forget(key)
+		s.service.observeUnparsable(octx)
+		return result, nil
+	}
+
+	pair, _ := s.parser.Result(key)
+	s.trackExchange(key, typeId, pair)
+
+	var part parser.Result
+	switch typeId {
+	case ocpp.CALL, ocpp.SEND:
+		part = pair.Request
+	case ocpp.CALL_RESULT, ocpp.CALL_ERROR:
+		part = pair.Response
+	case ocpp.CALL_RESULT_ERROR:
+		part = pair.ResponseError
+	}
+
+	// Once a response arrives (or for SEND, which has none), the pair is complete.
+	if !result.IsRequest || typeId == ocpp.SEND {
+		s.parser.Forget(key)
+		delete(s.pendingRequests, key)
+	} else {
+		request := &pendingRequest{uniqueId: key, arrived: time.Now()}
+		s.pending = append(s.pending, request)
+		s.pendingRequests[key] = request
+	}
+
+	if request, found := pair.GetRequest(); found {
+		result.Action = request.GetAction()
+	} else if typeId == ocpp.CALL_RESULT && part.Message() != nil {
+		result.Action = part.Message().GetAction()
+	}
+
+	switch {
+	case !part.IsValid():
+		result.Errors = part.Errors()
+		s.service.observeParserResult(octx, result.Action, result.Errors)
+		return result, nil
+	case part.Message() == nil:
+		result.Errors = []string{noMatchingRequestErr}
+		s.service.observeParserResult(octx, result.Action, result.Errors)
+		return result, nil
+	}
+
+	var request ocpp.Message
+	if !result.IsRequest {
+		request, _ = pair.GetRequest()
+	}
+
+	validationResult, err := s.service.validateMessage(octx, result.Action, request, part.Message())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate message")
+	}
+
+	result.Valid = validationResult.IsValid()
+	result.Errors = validationResult.Errors()
+	return result, nil
+}
+
+// dropPendingRequests drops the oldest requests without a response to make room for another request, and the
+// requests that are too old to still be answered.
+func (s *Session) dropPendingRequests() {
+	now := time.Now()
+	for len(s.pending) > 0 {
+		oldest := s.pending[0]
+		switch {
+		case s.pendingRequests[oldest.uniqueId] != oldest:
+			// Answered, or replaced by a later request with the same unique ID
+		case len(s.pendingRequests) >= s.service.pendingRequests || now.Sub(oldest.arrived) >= s.service.pendingAge:
+			s.parser.Forget(oldest.uniqueId)
+			delete(s.pendingRequests, oldest.uniqueId)
+		default:
+			return
+		}
+
+		s.pending[0] = nil
+		s.pending = s.pending[1:]
+	}
+}
+
+// trackExchange adds the exchange of a request to the sequence, and completes it once the response arrives.
+func (s *Session) trackExchange(uniqueId string, typeId ocpp.MessageType, pair parser.RequestResponseResult) {
+	exchange, found := validator.NewExchange(uniqueId, pair)
+	if !found {
+		return
+	}
+
+	switch typeId {
+	case ocpp.CALL, ocpp.SEND:
+		entry := &sequenceEntry{exchange: exchange, arrived: time.Now()}
+		s.sequence = append(s.sequence, entry)
+		s.exchanges[uniqueId] = entry
+	case ocpp.CALL_RESULT:
+		if entry, found := s.exchanges[uniqueId]; found {
+			entry.exchange.Response = exchange.Response
+		}
+	}
+}
+
+// checkSequence checks the rules spanning multiple messages for the exchanges that left the sequence window, or
+// for all remaining exchanges, and returns the results with violations. Exchanges beyond the context are dropped.
+func (s *Session) checkSequence(octx ocpp.OcppContext, all bool) []SequenceResult {
+	now := time.Now()
+	final := s.checked
+	for ; final < len(s.sequence); final++ {
+		followed := len(s.sequence) - final - 1
+		if !all && followed < s.service.sequenceExchanges && now.Sub(s.sequence[final].arrived) < s.service.sequenceAge {
+			break
+		}
+	}
+
+	if final == s.checked {
+		return nil
+	}
+
+	exchanges := make([]validator.Exchange, 0, len(s.sequence))
+	for _, entry := range s.sequence {
+		exchanges = append(exchanges, entry.exchange)
+	}
+
+	validationResults := s.service.validator.ValidateExchanges(octx, exchanges)
+
+	var results []SequenceResult
+	for _, entry := range s.sequence[s.checked:final] {
+		validationResult, found := validationResults[entry.exchange.MessageId]
+		if !found || validationResult.IsValid() {
+			continue
+		}
+
+		results = append(results, SequenceResult{
+			UniqueId: entry.exchange.MessageId,
+			Action:   entry.exchange.Action,
+			Errors:   validationResult.Errors(),
+		})
+	}
+
+	s.checked = final
+
+	// Keep the checked exchanges as context for the exchanges still in the window
+	if dropped := s.checked - s.service.sequenceExchanges; dropped > 0 {
+		for _, entry := range s.sequence[:dropped] {
+			if s.exchanges[entry.exchange.MessageId] == entry {
+				delete(s.exchanges, entry.exchange.MessageId)
+			}
+		}
+
+		s.sequence = slices.Delete(s.sequence, 0, dropped)
+		s.checked -= dropped
+	}
+
+	return results
+}
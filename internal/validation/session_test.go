@@ -0,0 +1,166 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaptinlin/jsonschema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	mock_schema_registry "github.com/ChargePi/chargeflow/gen/mocks/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+)
+
+func TestSession_ValidateFrame(t *testing.T) {
+	const response = `[3, "1234", {"status": "Accepted", "currentTime": "2024-01-01T00:00:00Z", "interval": 300}]`
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(t)
+	requestSchema, err := jsonschema.NewCompiler().Compile(bootNotificationSchema)
+	require.NoError(t, err)
+	responseSchema, err := jsonschema.NewCompiler().Compile(bootNotificationResponseSchema)
+	require.NoError(t, err)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "BootNotificationRequest"}).Return(requestSchema, true)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "BootNotificationResponse"}).Return(responseSchema, true)
+
+	session := NewService(zap.NewNop(), registry).NewSession()
+
+	tests := []struct {
+		name     string
+		frame    string
+		expected FrameResult
+	}{
+		{
+			name:  "Valid request",
+			frame: ocpp16validReq,
+			expected: FrameResult{
+				UniqueId: "1234", Action: "BootNotification", MessageType: ocpp.CALL,
+				IsRequest: true, Parsable: true, Valid: true, Errors: []string{},
+			},
+		},
+		{
+			name:  "Response paired with the earlier request",
+			frame: response,
+			expected: FrameResult{
+				UniqueId: "1234", Action: "BootNotification", MessageType: ocpp.CALL_RESULT,
+				Parsable: true, Valid: true, Errors: []string{},
+			},
+		},
+		{
+			name:  "Response without a request",
+			frame: ocpp16validRes,
+			expected: FrameResult{
+				UniqueId: "1234", MessageType: ocpp.CALL_RESULT,
+				Parsable: true, Errors: []string{noMatchingRequestErr},
+			},
+		},
+		{
+			name:  "Unparsable frame",
+			frame: unparsableMsg,
+			expected: FrameResult{
+				UniqueId: "line 4", Errors: []string{"Message is not a valid OCPP message"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.ValidateFrame(octx, tt.frame)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, *result)
+		})
+	}
+}
+
+func TestSession_Sequence(t *testing.T) {
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	permissiveSchema, err := jsonschema.NewCompiler().Compile([]byte(`{"type": "object"}`))
+	require.NoError(t, err)
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(t)
+	registry.EXPECT().GetSchema(mock.Anything, mock.Anything).Return(permissiveSchema, true)
+
+	// Exchanges are checked once two more exchanges followed them
+	session := NewService(zap.NewNop(), registry, WithSequenceWindow(2, time.Hour)).NewSession()
+
+	frames := []string{
+		`[2, "1", "TriggerMessage", {"requestedMessage": "Heartbeat"}]`,
+		`[3, "1", {"status": "Accepted"}]`,
+		`[2, "2", "TriggerMessage", {"requestedMessage": "BootNotification"}]`,
+		`[3, "2", {"status": "Accepted"}]`,
+		`[2, "3", "Heartbeat", {}]`,
+		`[3, "3", {"currentTime": "2024-01-01T00:00:00Z"}]`,
+	}
+	for _, frame := range frames {
+		result, err := session.ValidateFrame(octx, frame)
+		require.NoError(t, err)
+		require.Empty(t, result.Sequence, frame)
+	}
+
+	result, err := session.ValidateFrame(octx, `[2, "4", "Heartbeat", {}]`)
+	require.NoError(t, err)
+	require.Equal(t, []SequenceResult{{
+		UniqueId: "2",
+		Action:   "TriggerMessage",
+		Errors:   []string{"ocpp16.trigger-message: TriggerMessage was accepted, but no BootNotification followed"},
+	}}, result.Sequence)
+
+	// Closing the session checks the exchanges still in the window
+	for _, frame := range []string{`[2, "5", "TriggerMessage", {"requestedMessage": "StatusNotification"}]`, `[3, "5", {"status": "Accepted"}]`} {
+		result, err = session.ValidateFrame(octx, frame)
+		require.NoError(t, err)
+		require.Empty(t, result.Sequence)
+	}
+
+	require.Equal(t, []SequenceResult{{
+		UniqueId: "5",
+		Action:   "TriggerMessage",
+		Errors:   []string{"ocpp16.trigger-message: TriggerMessage was accepted, but no StatusNotification followed"},
+	}}, session.Close())
+	require.Empty(t, session.Close())
+}
+
+func TestSession_DropsPendingRequests(t *testing.T) {
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	permissiveSchema, err := jsonschema.NewCompiler().Compile([]byte(`{"type": "object"}`))
+	require.NoError(t, err)
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(t)
+	registry.EXPECT().GetSchema(mock.Anything, mock.Anything).Return(permissiveSchema, true)
+	service := NewService(zap.NewNop(), registry, WithPendingRequestLimit(2, time.Hour))
+
+	t.Run("Too many requests", func(t *testing.T) {
+		session := service.NewSession()
+		for _, frame := range []string{`[2, "1", "Heartbeat", {}]`, `[2, "2", "Heartbeat", {}]`, `[2, "3", "Heartbeat", {}]`} {
+			_, err := session.ValidateFrame(octx, frame)
+			require.NoError(t, err)
+		}
+
+		// The oldest request was dropped to make room for the last one
+		result, err := session.ValidateFrame(octx, `[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`)
+		require.NoError(t, err)
+		require.Equal(t, []string{noMatchingRequestErr}, result.Errors)
+
+		result, err = session.ValidateFrame(octx, `[3, "3", {"currentTime": "2024-01-01T00:00:00Z"}]`)
+		require.NoError(t, err)
+		require.Equal(t, "Heartbeat", result.Action)
+		require.True(t, result.Valid, result.Errors)
+	})
+
+	t.Run("Requests that are too old", func(t *testing.T) {
+		session := NewService(zap.NewNop(), registry, WithPendingRequestLimit(2, time.Millisecond)).NewSession()
+		_, err := session.ValidateFrame(octx, `[2, "1", "Heartbeat", {}]`)
+		require.NoError(t, err)
+
+		time.Sleep(5 * time.Millisecond)
+
+		result, err := session.ValidateFrame(octx, `[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`)
+		require.NoError(t, err)
+		require.Equal(t, []string{noMatchingRequestErr}, result.Errors)
+	})
+}
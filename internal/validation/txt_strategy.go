@@ -3,7 +3,9 @@ package validation
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/ChargePi/chargeflow/pkg/report"
@@ -26,14 +28,22 @@ func (txtWriter) Write(path string, r *report.Report) error {
 	b.WriteString(fmt.Sprintf("Unparsable messages: %d\n", stats.UnparsableMessages))
 	b.WriteString(fmt.Sprintf("Success rate: %.2f%%\n\n", stats.TotalValidMessagesPercentage()))
 
+	writeTxtCounts(&b, "Results by action (most invalid first)", stats.ByAction)
+	writeTxtOccurrences(&b, "Errors by kind", stats.ByErrorKind)
+	writeTxtOccurrences(&b, "Failing fields", stats.ByField)
+	writeTxtOccurrences(&b, "CALLERROR codes", stats.ByErrorCode)
+	writeTxtCounts(&b, "Results by vendor/model (most invalid first)", stats.ByVendorModel)
+
 	if len(r.InvalidMessages) == 0 && len(r.NonParsableMessages) == 0 {
 		b.WriteString("All messages are valid!\n")
 	} else {
-		for msgID, rr := range r.InvalidMessages {
+		for _, msgID := range r.InvalidMessageIds() {
 			b.WriteString(fmt.Sprintf("Message %s:\n", msgID))
-			for typ, errs := range rr {
+			rr := r.InvalidMessages[msgID]
+			for _, typ := range slices.Sorted(maps.Keys(rr)) {
 				b.WriteString(fmt.Sprintf("  %s:\n", typ))
-				for _, e := range errs {
+				writeTxtOrigin(&b, r.Messages[msgID][typ], "    ")
+				for _, e := range rr[typ] {
 					b.WriteString(fmt.Sprintf("    - %s\n", e))
 				}
 			}
@@ -42,9 +52,10 @@ func (txtWriter) Write(path string, r *report.Report) error {
 
 		if len(r.NonParsableMessages) > 0 {
 			b.WriteString("Non parsable messages:\n")
-			for msgID, errs := range r.NonParsableMessages {
+			for _, msgID := range r.NonParsableKeys() {
 				b.WriteString(fmt.Sprintf("  %s:\n", msgID))
-				for _, e := range errs {
+				writeTxtOrigin(&b, r.NonParsableDetails[msgID], "    ")
+				for _, e := range r.NonParsableMessages[msgID] {
 					b.WriteString(fmt.Sprintf("    - %s\n", e))
 				}
 				b.WriteString("\n")
@@ -58,3 +69,42 @@ func (txtWriter) Write(path string, r *report.Report) error {
 
 	return nil
 }
+
+// writeTxtOrigin writes where the message came from and the original frame, if known.
+func writeTxtOrigin(b *strings.Builder, details report.MessageDetails, indent string) {
+	if location := details.Location(); location != "" {
+		b.WriteString(fmt.Sprintf("%sat %s\n", indent, location))
+	}
+
+	if details.Raw != "" {
+		b.WriteString(fmt.Sprintf("%s> %s\n", indent, details.Raw))
+	}
+}
+
+// writeTxtCounts writes a breakdown of the valid and invalid requests and responses, most invalid first.
+func writeTxtCounts(b *strings.Builder, title string, counts map[string]report.Counts) {
+	if len(counts) == 0 {
+		return
+	}
+
+	b.WriteString(fmt.Sprintf("%s:\n", title))
+	for _, key := range report.MostInvalid(counts) {
+		c := counts[key]
+		b.WriteString(fmt.Sprintf("  %s: requests %d valid, %d invalid; responses %d valid, %d invalid\n",
+			key, c.ValidRequests, c.InvalidRequests, c.ValidResponses, c.InvalidResponses))
+	}
+	b.WriteString("\n")
+}
+
+// writeTxtOccurrences writes a breakdown of occurrences, most frequent first.
+func writeTxtOccurrences(b *strings.Builder, title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+
+	b.WriteString(fmt.Sprintf("%s:\n", title))
+	for _, key := range report.MostFrequent(counts) {
+		b.WriteString(fmt.Sprintf("  %s: %d\n", key, counts[key]))
+	}
+	b.WriteString("\n")
+}
@@ -23,8 +23,15 @@ func TestTXTStrategy_Write(t *testing.T) {
 				"response": []string{"rerr"},
 			},
 		},
+		Messages: map[string]map[string]report.MessageDetails{
+			"mX": {"response": {File: "messages.txt", Line: 5, Raw: `[3, "mX", {}]`}},
+		},
 		NonParsableMessages: map[string][]string{"ln": {"parseerr"}},
-		Statistics:          report.Statistics{ValidRequests: 0, InvalidRequests: 0, ValidResponses: 0, InvalidResponses: 1, UnparsableMessages: 1},
+		Statistics: report.Statistics{
+			ValidRequests: 0, InvalidRequests: 0, ValidResponses: 0, InvalidResponses: 1, UnparsableMessages: 1,
+			ByAction:    map[string]report.Counts{"Authorize": {InvalidResponses: 1}},
+			ByErrorCode: map[string]int{"NotSupported": 2},
+		},
 	}
 
 	s := txtWriter{}
@@ -36,4 +43,8 @@ func TestTXTStrategy_Write(t *testing.T) {
 	content := string(b)
 	require.Contains(t, content, "Invalid responses")
 	require.Contains(t, content, "mX")
+	require.Contains(t, content, "at messages.txt:5")
+	require.Contains(t, content, "  Authorize: requests 0 valid, 0 invalid; responses 0 valid, 1 invalid")
+	require.Contains(t, content, "CALLERROR codes:\n  NotSupported: 2")
+	require.Contains(t, content, `> [3, "mX", {}]`)
 }
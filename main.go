@@ -23,9 +23,15 @@ func main() {
 		syscall.SIGQUIT,
 		syscall.SIGKILL,
 	)
-	defer cancel()
 
-	if err := cmd.Execute(ctx); err != nil {
-		zap.L().Fatal("Unable to run", zap.Error(err))
+	err := cmd.Execute(ctx)
+	cancel()
+
+	code := cmd.ExitCode(err)
+	if code == cmd.ExitToolError {
+		zap.L().Error("Unable to run", zap.Error(err))
 	}
+
+	_ = zap.L().Sync()
+	os.Exit(code)
 }
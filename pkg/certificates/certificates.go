@@ -0,0 +1,166 @@
+// Package certificates parses the X.509 certificates and certificate signing requests (CSRs) exchanged by the OCPP
+// security and ISO 15118 messages, and computes the hash data OCPP identifies certificates by.
+package certificates
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	pemCertificate = "CERTIFICATE"
+	pemCSR         = "CERTIFICATE REQUEST"
+	// pemLegacyCSR is the PEM type of CSRs generated by older tools, e.g. Netscape and Microsoft.
+	pemLegacyCSR = "NEW CERTIFICATE REQUEST"
+)
+
+var (
+	ErrNoCertificate = errors.New("no certificate found")
+	ErrNoCSR         = errors.New("no certificate signing request found")
+)
+
+// Parse parses PEM encoded certificates, or a single base64 encoded DER certificate. PEM data may contain a chain
+// of certificates, which are returned in order.
+func Parse(data string) ([]*x509.Certificate, error) {
+	blocks, err := decode(data, pemCertificate)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(blocks) == 0 {
+		return nil, ErrNoCertificate
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(blocks))
+	for i, block := range blocks {
+		certificate, err := x509.ParseCertificate(block)
+		if err != nil {
+			return nil, errors.Wrapf(err, "certificate %d", i+1)
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+// ParseCSR parses a PEM encoded or base64 encoded DER certificate signing request and verifies its signature.
+func ParseCSR(data string) (*x509.CertificateRequest, error) {
+	blocks, err := decode(data, pemCSR, pemLegacyCSR)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(blocks) {
+	case 0:
+		return nil, ErrNoCSR
+	case 1:
+	default:
+		return nil, errors.Errorf("expected a single certificate signing request, got %d", len(blocks))
+	}
+
+	csr, err := x509.ParseCertificateRequest(blocks[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := csr.CheckSignature(); err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+
+	return csr, nil
+}
+
+// decode returns the DER data of the PEM blocks of the given types. Data that is not PEM encoded is decoded as a
+// single base64 encoded DER block.
+func decode(data string, types ...string) ([][]byte, error) {
+	rest := []byte(strings.TrimSpace(data))
+	if !bytes.HasPrefix(rest, []byte("-----BEGIN")) {
+		der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(data), ""))
+		if err != nil {
+			return nil, errors.New("neither PEM nor base64 encoded")
+		}
+
+		return [][]byte{der}, nil
+	}
+
+	var blocks [][]byte
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if !slices.Contains(types, block.Type) {
+			return nil, errors.Errorf("unexpected PEM block %q", block.Type)
+		}
+
+		blocks = append(blocks, block.Bytes)
+	}
+
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, errors.New("malformed PEM data")
+	}
+
+	return blocks, nil
+}
+
+// Pool holds known certificates, e.g. the root and sub-CA certificates of a CSMS, which the hash data of messages
+// is checked against.
+type Pool struct {
+	certificates []*x509.Certificate
+}
+
+// NewPool creates a pool with the certificates.
+func NewPool(certificates ...*x509.Certificate) *Pool {
+	return &Pool{certificates: certificates}
+}
+
+// Add adds certificates to the pool.
+func (p *Pool) Add(certificates ...*x509.Certificate) {
+	p.certificates = append(p.certificates, certificates...)
+}
+
+// LoadFile adds the PEM encoded certificates of a file to the pool.
+func (p *Pool) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read certificates")
+	}
+
+	certificates, err := Parse(string(data))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse certificates of %s", path)
+	}
+
+	p.Add(certificates...)
+	return nil
+}
+
+// Certificates returns the certificates of the pool.
+func (p *Pool) Certificates() []*x509.Certificate {
+	return p.certificates
+}
+
+// Issuer returns the certificate of the pool that issued the certificate, if any.
+func (p *Pool) Issuer(certificate *x509.Certificate) (*x509.Certificate, bool) {
+	for _, candidate := range p.certificates {
+		if !bytes.Equal(candidate.RawSubject, certificate.RawIssuer) {
+			continue
+		}
+
+		if certificate.CheckSignatureFrom(candidate) == nil {
+			return candidate, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,181 @@
+package certificates
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type certificatesTestSuite struct {
+	suite.Suite
+	root    *x509.Certificate
+	rootKey *ecdsa.PrivateKey
+	leaf    *x509.Certificate
+}
+
+func (s *certificatesTestSuite) SetupSuite() {
+	s.root, s.rootKey = s.certificate(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ACME Root CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	s.leaf, _ = s.certificate(&x509.Certificate{
+		SerialNumber: big.NewInt(0x1a2b),
+		Subject:      pkix.Name{CommonName: "CP001"},
+	}, s.root, s.rootKey)
+}
+
+// certificate creates a certificate, self-signed if the parent is nil.
+func (s *certificatesTestSuite) certificate(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	s.Require().NoError(err)
+
+	certificate, err := x509.ParseCertificate(der)
+	s.Require().NoError(err)
+	return certificate, key
+}
+
+func encode(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func (s *certificatesTestSuite) TestParse() {
+	chain := encode(pemCertificate, s.leaf.Raw) + encode(pemCertificate, s.root.Raw)
+
+	parsed, err := Parse(chain)
+	s.Require().NoError(err)
+	s.Require().Len(parsed, 2)
+	s.Equal("CP001", parsed[0].Subject.CommonName)
+	s.Equal("ACME Root CA", parsed[1].Subject.CommonName)
+
+	parsed, err = Parse(base64.StdEncoding.EncodeToString(s.leaf.Raw))
+	s.Require().NoError(err)
+	s.Equal("CP001", parsed[0].Subject.CommonName)
+
+	_, err = Parse("not a certificate")
+	s.ErrorContains(err, "neither PEM nor base64 encoded")
+
+	_, err = Parse(encode(pemCertificate, s.leaf.Raw[:100]))
+	s.ErrorContains(err, "certificate 1")
+
+	_, err = Parse(encode("PRIVATE KEY", []byte{1}))
+	s.ErrorContains(err, `unexpected PEM block "PRIVATE KEY"`)
+
+	_, err = Parse(strings.TrimSuffix(encode(pemCertificate, s.leaf.Raw), "-----END CERTIFICATE-----\n"))
+	s.ErrorContains(err, "malformed PEM data")
+}
+
+func (s *certificatesTestSuite) TestParseCSR() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "CP001"}}, key)
+	s.Require().NoError(err)
+
+	csr, err := ParseCSR(encode(pemCSR, der))
+	s.Require().NoError(err)
+	s.Equal("CP001", csr.Subject.CommonName)
+
+	_, err = ParseCSR(encode(pemLegacyCSR, der))
+	s.NoError(err)
+
+	_, err = ParseCSR(base64.StdEncoding.EncodeToString(der))
+	s.NoError(err)
+
+	_, err = ParseCSR(encode(pemCertificate, s.leaf.Raw))
+	s.ErrorContains(err, `unexpected PEM block "CERTIFICATE"`)
+
+	// Tamper with the signature
+	tampered := append([]byte{}, der...)
+	tampered[len(tampered)-1] ^= 0xFF
+	_, err = ParseCSR(encode(pemCSR, tampered))
+	s.Error(err)
+}
+
+func (s *certificatesTestSuite) TestHashData() {
+	hashData, err := NewHashData(SHA256, s.leaf, s.root)
+	s.Require().NoError(err)
+	s.Equal("1a2b", hashData.SerialNumber)
+	s.Len(hashData.IssuerNameHash, 64)
+	s.NoError(hashData.Validate())
+
+	pool := NewPool(s.root, s.leaf)
+	s.NoError(pool.Check(hashData))
+
+	// Hash data of unknown certificates is not checked
+	s.NoError(NewPool().Check(hashData))
+
+	// Serial numbers are compared as numbers
+	upper := hashData
+	upper.SerialNumber = "001A2B"
+	s.NoError(pool.Check(upper))
+
+	wrongName := hashData
+	wrongName.IssuerNameHash = strings.Repeat("0", 64)
+	s.ErrorContains(pool.Check(wrongName), "issuerNameHash does not match CN=ACME Root CA, the issuer of certificate 1a2b")
+
+	wrongKey := hashData
+	wrongKey.IssuerKeyHash = strings.Repeat("0", 64)
+	s.ErrorContains(pool.Check(wrongKey), "issuerKeyHash does not match the key of CN=ACME Root CA")
+
+	// The issuer is unknown
+	s.NoError(NewPool(s.leaf).Check(wrongKey))
+
+	invalid := hashData
+	invalid.HashAlgorithm = SHA384
+	s.ErrorContains(invalid.Validate(), "is not a hex encoded SHA384 hash")
+
+	invalid = hashData
+	invalid.SerialNumber = "0x1a2b"
+	s.ErrorContains(invalid.Validate(), `serialNumber "0x1a2b" is not hexadecimal`)
+
+	invalid = hashData
+	invalid.HashAlgorithm = "MD5"
+	s.ErrorContains(invalid.Validate(), `unsupported hashAlgorithm "MD5"`)
+}
+
+func (s *certificatesTestSuite) TestPool() {
+	pool := NewPool()
+
+	path := filepath.Join(s.T().TempDir(), "root.pem")
+	s.Require().NoError(os.WriteFile(path, []byte(encode(pemCertificate, s.root.Raw)), 0o600))
+	s.Require().NoError(pool.LoadFile(path))
+	s.Len(pool.Certificates(), 1)
+
+	issuer, found := pool.Issuer(s.leaf)
+	s.True(found)
+	s.Equal(s.root, issuer)
+
+	_, found = NewPool(s.leaf).Issuer(s.leaf)
+	s.False(found)
+
+	s.ErrorContains(pool.LoadFile(filepath.Join(s.T().TempDir(), "missing.pem")), "failed to read certificates")
+}
+
+func TestCertificates(t *testing.T) {
+	suite.Run(t, new(certificatesTestSuite))
+}
@@ -0,0 +1,155 @@
+package certificates
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HashAlgorithm is the algorithm of the hashes of hash data.
+type HashAlgorithm string
+
+const (
+	SHA256 = HashAlgorithm("SHA256")
+	SHA384 = HashAlgorithm("SHA384")
+	SHA512 = HashAlgorithm("SHA512")
+)
+
+// hashes are the hash functions of the hash algorithms defined by OCPP.
+var hashes = map[HashAlgorithm]crypto.Hash{
+	SHA256: crypto.SHA256,
+	SHA384: crypto.SHA384,
+	SHA512: crypto.SHA512,
+}
+
+// HashData identifies a certificate by its issuer and serial number, like the CertificateHashDataType and
+// OCSPRequestDataType of OCPP.
+type HashData struct {
+	HashAlgorithm HashAlgorithm
+	// IssuerNameHash is the hex encoded hash of the DER encoded issuer name of the certificate.
+	IssuerNameHash string
+	// IssuerKeyHash is the hex encoded hash of the public key of the issuer, without the tag and length.
+	IssuerKeyHash string
+	// SerialNumber is the hex encoded serial number of the certificate.
+	SerialNumber string
+}
+
+// NewHashData computes the hash data of a certificate issued by the issuer.
+func NewHashData(algorithm HashAlgorithm, certificate, issuer *x509.Certificate) (HashData, error) {
+	hash, ok := hashes[algorithm]
+	if !ok {
+		return HashData{}, errors.Errorf("unsupported hashAlgorithm %q", algorithm)
+	}
+
+	keyHash, err := issuerKeyHash(hash, issuer)
+	if err != nil {
+		return HashData{}, err
+	}
+
+	return HashData{
+		HashAlgorithm:  algorithm,
+		IssuerNameHash: digest(hash, certificate.RawIssuer),
+		IssuerKeyHash:  keyHash,
+		SerialNumber:   certificate.SerialNumber.Text(16),
+	}, nil
+}
+
+// Validate checks that the hashes match the size of the hash algorithm and the serial number is hexadecimal.
+func (h HashData) Validate() error {
+	hash, ok := hashes[h.HashAlgorithm]
+	if !ok {
+		return errors.Errorf("unsupported hashAlgorithm %q", h.HashAlgorithm)
+	}
+
+	for _, field := range [][2]string{{"issuerNameHash", h.IssuerNameHash}, {"issuerKeyHash", h.IssuerKeyHash}} {
+		decoded, err := hex.DecodeString(field[1])
+		if err != nil || len(decoded) != hash.Size() {
+			return errors.Errorf("%s %q is not a hex encoded %s hash", field[0], field[1], h.HashAlgorithm)
+		}
+	}
+
+	if _, ok := h.serialNumber(); !ok {
+		return errors.Errorf("serialNumber %q is not hexadecimal", h.SerialNumber)
+	}
+
+	return nil
+}
+
+// serialNumber returns the serial number as a number.
+func (h HashData) serialNumber() (*big.Int, bool) {
+	return new(big.Int).SetString(h.SerialNumber, 16)
+}
+
+// Check checks the hash data against the certificates of the pool with the same serial number: the issuerNameHash
+// must match the issuer of one of them, and the issuerKeyHash the key of its issuer, if the issuer is in the pool.
+// Hash data of certificates that are not in the pool is not checked.
+func (p *Pool) Check(h HashData) error {
+	hash, ok := hashes[h.HashAlgorithm]
+	if !ok {
+		return nil
+	}
+
+	serialNumber, ok := h.serialNumber()
+	if !ok {
+		return nil
+	}
+
+	var candidate *x509.Certificate
+	for _, certificate := range p.certificates {
+		if certificate.SerialNumber.Cmp(serialNumber) != 0 {
+			continue
+		}
+
+		candidate = certificate
+		if !strings.EqualFold(digest(hash, certificate.RawIssuer), h.IssuerNameHash) {
+			continue
+		}
+
+		issuer, found := p.Issuer(certificate)
+		if !found {
+			return nil
+		}
+
+		keyHash, err := issuerKeyHash(hash, issuer)
+		if err != nil || strings.EqualFold(keyHash, h.IssuerKeyHash) {
+			return nil
+		}
+
+		return errors.Errorf("issuerKeyHash does not match the key of %s, the issuer of certificate %s", issuer.Subject, h.SerialNumber)
+	}
+
+	if candidate != nil {
+		return errors.Errorf("issuerNameHash does not match %s, the issuer of certificate %s", candidate.Issuer, h.SerialNumber)
+	}
+
+	return nil
+}
+
+// issuerKeyHash returns the hex encoded hash of the public key of the issuer: the value of the subjectPublicKey bit
+// string, without the tag and length.
+func issuerKeyHash(hash crypto.Hash, issuer *x509.Certificate) (string, error) {
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+		return "", errors.Wrap(err, "failed to parse the public key of the issuer")
+	}
+
+	return digest(hash, publicKeyInfo.PublicKey.RightAlign()), nil
+}
+
+func digest(hash crypto.Hash, data []byte) string {
+	h := hash.New()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,147 @@
+// Package chargeflow is the public Go API for validating OCPP-J messages with the OCPP schemas bundled in
+// chargeflow, e.g. inline in a CSMS or a charging station gateway:
+//
+//	validator, err := chargeflow.New()
+//	if err != nil {
+//		return err
+//	}
+//
+//	result, err := validator.ValidateFrame(ctx, ocpp.OcppContext{Version: ocpp.V16}, frame)
+package chargeflow
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/internal/validation"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/report"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
+	"github.com/ChargePi/chargeflow/pkg/schemas"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+// FrameResult is the outcome of validating a single OCPP-J frame.
+type FrameResult = validation.FrameResult
+
+// SequenceResult is the outcome of checking the rules spanning multiple messages for an exchange of a Session.
+type SequenceResult = validation.SequenceResult
+
+// Session validates the frames of a single connection, pairing responses with the requests seen earlier
+// in the same session. Use one session per charge point connection.
+type Session = validation.Session
+
+// Validator validates OCPP-J messages against the registered schemas. It is safe for concurrent use.
+type Validator struct {
+	logger    *zap.Logger
+	registry  schema_registry.SchemaRegistry
+	service   *validation.Service
+	validator *validator.Validator
+
+	// session pairs frames passed to ValidateFrame.
+	session *Session
+}
+
+// New creates a Validator. Unless configured otherwise, the bundled schemas of all supported OCPP versions
+// are compiled into an in-memory registry.
+func New(opts ...Option) (*Validator, error) {
+	config := options{
+		logger:   zap.NewNop(),
+		versions: schemas.Versions(),
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	registry := config.registry
+	if registry == nil {
+		registry = file_registry.NewFileSchemaRegistry(config.logger, file_registry.WithOverwrite(true))
+	}
+
+	ctx := context.Background()
+	for _, version := range config.versions {
+		if err := schemas.Register(ctx, registry, version); err != nil {
+			return nil, errors.Wrapf(err, "unable to register bundled schemas for OCPP %s", version.Name())
+		}
+	}
+
+	service := validation.NewService(config.logger, registry, validation.WithValidatorOptions(config.validatorOptions...))
+
+	return &Validator{
+		logger:    config.logger,
+		registry:  registry,
+		service:   service,
+		validator: validator.NewValidator(config.logger, registry, config.validatorOptions...),
+		session:   service.NewSession(),
+	}, nil
+}
+
+// Registry returns the schema registry used by the Validator.
+func (v *Validator) Registry() schema_registry.SchemaRegistry {
+	return v.registry
+}
+
+// RegisterSchema registers an additional (e.g. vendor-specific) schema. The action must be suffixed with
+// either "Request" or "Response", e.g. "DataTransferRequest".
+func (v *Validator) RegisterSchema(ctx context.Context, octx ocpp.OcppContext, action string, schema json.RawMessage) error {
+	return v.registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{
+		OcppContext: octx,
+		Action:      action,
+		Schema:      schema,
+	})
+}
+
+// RegisterDataTransferSchema registers a schema for the data of DataTransfer messages with the given vendorId and
+// messageId. The action must be either "DataTransferRequest" or "DataTransferResponse". An empty messageId registers
+// the schema for every message of the vendor.
+func (v *Validator) RegisterDataTransferSchema(ctx context.Context, octx ocpp.OcppContext, action, vendorId, messageId string, schema json.RawMessage) error {
+	return v.registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{
+		OcppContext: octx,
+		Action:      action,
+		VendorId:    vendorId,
+		MessageId:   messageId,
+		Schema:      schema,
+	})
+}
+
+// NewSession creates a session for validating the frames of a single connection.
+func (v *Validator) NewSession() *Session {
+	return v.service.NewSession()
+}
+
+// ValidateFrame parses and validates a single raw OCPP-J frame. Responses are paired with requests previously
+// passed to ValidateFrame; when validating traffic of multiple connections, use a Session per connection instead.
+func (v *Validator) ValidateFrame(ctx context.Context, octx ocpp.OcppContext, frame []byte) (*FrameResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.session.ValidateFrame(octx, string(frame))
+}
+
+// ValidateMessage validates an already parsed OCPP message.
+func (v *Validator) ValidateMessage(ctx context.Context, octx ocpp.OcppContext, message ocpp.Message) (*validator.ValidationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.validator.ValidateMessage(octx, message)
+}
+
+// ValidateMessages validates a batch of raw OCPP-J messages, pairing requests and responses by their unique ID,
+// and returns the aggregated report.
+func (v *Validator) ValidateMessages(ctx context.Context, octx ocpp.OcppContext, messages []string) (*report.Report, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return v.service.Validate(validation.Request{
+		OcppContext: octx,
+		Messages:    messages,
+	})
+}
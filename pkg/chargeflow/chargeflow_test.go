@@ -0,0 +1,119 @@
+package chargeflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+func TestValidator_ValidateFrame(t *testing.T) {
+	ctx := context.Background()
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	v, err := New(WithVersions(ocpp.V16))
+	require.NoError(t, err)
+
+	result, err := v.ValidateFrame(ctx, octx, []byte(`[2, "1", "BootNotification", {"chargePointVendor": "Acme", "chargePointModel": "X1"}]`))
+	require.NoError(t, err)
+	require.True(t, result.Valid, result.Errors)
+	require.True(t, result.IsRequest)
+
+	result, err = v.ValidateFrame(ctx, octx, []byte(`[3, "1", {"status": "Accepted", "currentTime": "2024-01-01T00:00:00Z", "interval": "300"}]`))
+	require.NoError(t, err)
+	require.Equal(t, "BootNotification", result.Action)
+	require.False(t, result.Valid)
+	require.NotEmpty(t, result.Errors)
+
+	// Unknown actions have no schema
+	_, err = v.ValidateFrame(ctx, octx, []byte(`[2, "2", "Unknown", {}]`))
+	require.Error(t, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = v.ValidateFrame(canceled, octx, []byte(`[2, "3", "Heartbeat", {}]`))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestValidator_Sessions(t *testing.T) {
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	v, err := New(WithVersions(ocpp.V16))
+	require.NoError(t, err)
+
+	first, second := v.NewSession(), v.NewSession()
+
+	result, err := first.ValidateFrame(octx, `[2, "1", "Heartbeat", {}]`)
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+
+	// The request was sent on another session, so the response cannot be paired
+	result, err = second.ValidateFrame(octx, `[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`)
+	require.NoError(t, err)
+	require.False(t, result.Valid)
+
+	result, err = first.ValidateFrame(octx, `[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`)
+	require.NoError(t, err)
+	require.True(t, result.Valid, result.Errors)
+}
+
+func TestValidator_ValidateMessages(t *testing.T) {
+	ctx := context.Background()
+
+	v, err := New(WithVersions(ocpp.V16))
+	require.NoError(t, err)
+
+	report, err := v.ValidateMessages(ctx, ocpp.OcppContext{Version: ocpp.V16}, []string{
+		`[2, "1", "Heartbeat", {}]`,
+		`[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`,
+		`[2, "2", "Authorize", {}]`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Statistics.ValidRequests)
+	require.Equal(t, 1, report.Statistics.InvalidRequests)
+	require.Contains(t, report.InvalidMessages, "2")
+}
+
+func TestValidator_RegisterSchema(t *testing.T) {
+	ctx := context.Background()
+	octx := ocpp.OcppContext{Version: ocpp.V16, Vendor: "Acme"}
+
+	v, err := New(WithVersions(ocpp.V16))
+	require.NoError(t, err)
+
+	err = v.RegisterSchema(ctx, octx, "HeartbeatRequest", json.RawMessage(`{"type": "object", "required": ["vendorField"]}`))
+	require.NoError(t, err)
+
+	result, err := v.NewSession().ValidateFrame(octx, `[2, "1", "Heartbeat", {}]`)
+	require.NoError(t, err)
+	require.False(t, result.Valid)
+
+	result, err = v.NewSession().ValidateFrame(ocpp.OcppContext{Version: ocpp.V16}, `[2, "1", "Heartbeat", {}]`)
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+}
+
+func TestValidator_RegisterDataTransferSchema(t *testing.T) {
+	ctx := context.Background()
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	v, err := New(WithVersions(ocpp.V16))
+	require.NoError(t, err)
+
+	err = v.RegisterDataTransferSchema(ctx, octx, "DataTransferRequest", "com.acme", "SetLevel", json.RawMessage(`{"type": "object", "required": ["level"]}`))
+	require.NoError(t, err)
+
+	result, err := v.NewSession().ValidateFrame(octx, `[2, "1", "DataTransfer", {"vendorId": "com.acme", "messageId": "SetLevel", "data": "{\"level\": 3}"}]`)
+	require.NoError(t, err)
+	require.True(t, result.Valid)
+
+	result, err = v.NewSession().ValidateFrame(octx, `[2, "1", "DataTransfer", {"vendorId": "com.acme", "messageId": "SetLevel", "data": "{}"}]`)
+	require.NoError(t, err)
+	require.False(t, result.Valid)
+
+	err = v.RegisterDataTransferSchema(ctx, octx, "HeartbeatRequest", "com.acme", "", json.RawMessage(`{"type": "object"}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package chargeflow
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+type options struct {
+	logger   *zap.Logger
+	registry schema_registry.SchemaRegistry
+	versions []ocpp.Version
+
+	validatorOptions []validator.Option
+}
+
+type Option func(*options)
+
+// WithLogger sets the logger used by the Validator. Logging is disabled by default.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithRegistry replaces the default in-memory registry, e.g. with a remote schema registry.
+// The bundled schemas are registered in the provided registry as well, unless disabled with WithVersions.
+func WithRegistry(registry schema_registry.SchemaRegistry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// WithVersions limits the bundled schemas that are registered to the given OCPP versions.
+// Calling it without versions skips registering bundled schemas altogether.
+func WithVersions(versions ...ocpp.Version) Option {
+	return func(o *options) {
+		o.versions = versions
+	}
+}
+
+// WithValidatorOptions configures the validation, e.g. which semantic rules are checked:
+//
+//	chargeflow.New(chargeflow.WithValidatorOptions(validator.WithDisabledRules("ocpp16.stack-level")))
+func WithValidatorOptions(opts ...validator.Option) Option {
+	return func(o *options) {
+		o.validatorOptions = append(o.validatorOptions, opts...)
+	}
+}
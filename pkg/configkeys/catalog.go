@@ -0,0 +1,148 @@
+// Package configkeys is a catalog of the OCPP 1.6 configuration keys, used to check that ChangeConfiguration and
+// GetConfiguration refer to known keys with valid values.
+package configkeys
+
+import (
+	_ "embed"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Type is the type of a configuration key value.
+type Type string
+
+const (
+	Boolean = Type("boolean")
+	Integer = Type("integer")
+	String  = Type("string")
+	// CSL is a comma-separated list, e.g. "Energy.Active.Import.Register,SoC".
+	CSL = Type("CSL")
+)
+
+// types are the types of configuration key values defined by OCPP 1.6.
+var types = []Type{Boolean, Integer, String, CSL}
+
+// Key is a configuration key.
+type Key struct {
+	Name string `yaml:"name"`
+	// Profile is the feature profile the key belongs to, e.g. "SmartCharging". Optional.
+	Profile string `yaml:"profile,omitempty"`
+	Type    Type   `yaml:"type"`
+	// Unit of the value, e.g. "s". Optional.
+	Unit string `yaml:"unit,omitempty"`
+	// ReadOnly keys can't be changed with a ChangeConfiguration.
+	ReadOnly bool `yaml:"readOnly,omitempty"`
+	// WriteOnly keys are not returned by a GetConfiguration, e.g. the AuthorizationKey.
+	WriteOnly bool `yaml:"writeOnly,omitempty"`
+	// Min and Max limit the values of integer keys. Optional.
+	Min *int `yaml:"min,omitempty"`
+	Max *int `yaml:"max,omitempty"`
+	// Values are the allowed values of string keys and the allowed items of CSL keys. Any value is allowed if empty.
+	Values []string `yaml:"values,omitempty"`
+}
+
+// File is the format of a catalog file. Keys of a file with a vendor and/or model only apply to the charging
+// stations of the vendor and model.
+type File struct {
+	Vendor string `yaml:"vendor,omitempty"`
+	Model  string `yaml:"model,omitempty"`
+	Keys   []Key  `yaml:"keys"`
+}
+
+//go:embed standard.yaml
+var standardCatalog []byte
+
+// Catalog holds the known configuration keys, both standard and vendor/model-specific ones.
+type Catalog struct {
+	// keys are the keys by scope, see scope, and lowercased name, as keys are case-insensitive CiStrings.
+	keys map[string]map[string]Key
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{keys: make(map[string]map[string]Key)}
+}
+
+// Standard returns a catalog of the standard configuration keys of OCPP 1.6 and the OCPP 1.6 Security Extension.
+func Standard() *Catalog {
+	catalog := NewCatalog()
+	if err := catalog.Parse(standardCatalog); err != nil {
+		panic(errors.Wrap(err, "invalid standard configuration key catalog"))
+	}
+
+	return catalog
+}
+
+// Add adds keys for the charging stations of the vendor and model, replacing the keys with the same name. Keys
+// without a vendor and model apply to every charging station.
+func (c *Catalog) Add(vendor, model string, keys ...Key) {
+	s := scope(vendor, model)
+	if c.keys[s] == nil {
+		c.keys[s] = make(map[string]Key)
+	}
+
+	for _, key := range keys {
+		c.keys[s][strings.ToLower(key.Name)] = key
+	}
+}
+
+// Parse adds the keys of a YAML catalog file.
+func (c *Catalog) Parse(data []byte) error {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return errors.Wrap(err, "failed to parse configuration key catalog")
+	}
+
+	for _, key := range file.Keys {
+		if key.Name == "" {
+			return errors.New("key name is required")
+		}
+
+		if !slices.Contains(types, key.Type) {
+			return errors.Errorf("%s: invalid type %q", key.Name, key.Type)
+		}
+
+		if key.ReadOnly && key.WriteOnly {
+			return errors.Errorf("%s: a key can't be both read-only and write-only", key.Name)
+		}
+	}
+
+	c.Add(file.Vendor, file.Model, file.Keys...)
+	return nil
+}
+
+// LoadFile adds the keys of a YAML catalog file.
+func (c *Catalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read configuration key catalog")
+	}
+
+	return errors.Wrapf(c.Parse(data), "invalid configuration key catalog %s", path)
+}
+
+// Key returns the key for a charging station of the vendor and model. Like vendor/model-specific schemas,
+// keys of the vendor and model take precedence over the keys of the vendor, which take precedence over the
+// keys of every charging station. Names are case-insensitive.
+func (c *Catalog) Key(vendor, model, name string) (Key, bool) {
+	for _, s := range []string{scope(vendor, model), scope(vendor, ""), ""} {
+		if key, found := c.keys[s][strings.ToLower(name)]; found {
+			return key, true
+		}
+	}
+
+	return Key{}, false
+}
+
+// scope returns the scope of vendor/model-specific keys.
+func scope(vendor, model string) string {
+	if model == "" {
+		return vendor
+	}
+
+	return strings.Join([]string{vendor, model}, "|")
+}
@@ -0,0 +1,112 @@
+package configkeys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogTestSuite struct {
+	suite.Suite
+}
+
+func (s *catalogTestSuite) TestStandard() {
+	catalog := Standard()
+
+	key, found := catalog.Key("", "", "HeartbeatInterval")
+	s.True(found)
+	s.Equal(Integer, key.Type)
+	s.Equal("Core", key.Profile)
+	s.False(key.ReadOnly)
+
+	key, found = catalog.Key("ACME", "Wallbox", "NumberOfConnectors")
+	s.True(found)
+	s.True(key.ReadOnly)
+
+	_, found = catalog.Key("", "", "AcmeLedMode")
+	s.False(found)
+
+	// Configuration keys are case-insensitive
+	key, found = catalog.Key("", "", "heartbeatinterval")
+	s.True(found)
+	s.Equal("HeartbeatInterval", key.Name)
+}
+
+func (s *catalogTestSuite) TestVendorKeys() {
+	catalog := Standard()
+	catalog.Add("ACME", "", Key{Name: "AcmeLedMode", Type: String})
+	catalog.Add("ACME", "Wallbox", Key{Name: "AcmeLedMode", Type: Boolean}, Key{Name: "HeartbeatInterval", Type: Integer, ReadOnly: true})
+
+	key, found := catalog.Key("ACME", "Wallbox", "AcmeLedMode")
+	s.True(found)
+	s.Equal(Boolean, key.Type)
+
+	key, found = catalog.Key("ACME", "Charger", "AcmeLedMode")
+	s.True(found)
+	s.Equal(String, key.Type)
+
+	_, found = catalog.Key("Other", "", "AcmeLedMode")
+	s.False(found)
+
+	key, _ = catalog.Key("ACME", "Wallbox", "HeartbeatInterval")
+	s.True(key.ReadOnly)
+	key, _ = catalog.Key("ACME", "", "HeartbeatInterval")
+	s.False(key.ReadOnly)
+}
+
+func (s *catalogTestSuite) TestLoadFile() {
+	path := filepath.Join(s.T().TempDir(), "acme.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte("vendor: ACME\nkeys:\n  - { name: AcmeLedMode, type: string, values: [On, Off] }\n"), 0o600))
+
+	catalog := NewCatalog()
+	s.Require().NoError(catalog.LoadFile(path))
+	key, found := catalog.Key("ACME", "Wallbox", "AcmeLedMode")
+	s.True(found)
+	s.Equal([]string{"On", "Off"}, key.Values)
+
+	s.ErrorContains(catalog.LoadFile(filepath.Join(s.T().TempDir(), "missing.yaml")), "failed to read configuration key catalog")
+	s.ErrorContains(catalog.Parse([]byte("keys: {")), "failed to parse configuration key catalog")
+	s.ErrorContains(catalog.Parse([]byte("keys:\n  - { type: string }\n")), "key name is required")
+	s.ErrorContains(catalog.Parse([]byte("keys:\n  - { name: AcmeLedMode, type: enum }\n")), `AcmeLedMode: invalid type "enum"`)
+	s.ErrorContains(catalog.Parse([]byte("keys:\n  - { name: AcmeKey, type: string, readOnly: true, writeOnly: true }\n")), "both read-only and write-only")
+}
+
+func (s *catalogTestSuite) TestCheckValue() {
+	minimum, maximum := 0, 3
+
+	tests := []struct {
+		name    string
+		key     Key
+		value   string
+		wantErr string
+	}{
+		{name: "Boolean", key: Key{Type: Boolean}, value: "true"},
+		{name: "Invalid boolean", key: Key{Type: Boolean}, value: "1", wantErr: `"1" is not a valid boolean`},
+		{name: "Integer", key: Key{Type: Integer, Min: &minimum, Max: &maximum}, value: "3"},
+		{name: "Invalid integer", key: Key{Type: Integer}, value: "1.5", wantErr: `"1.5" is not a valid integer`},
+		{name: "Below minimum", key: Key{Type: Integer, Min: &minimum}, value: "-1", wantErr: "-1 is less than the minimum 0"},
+		{name: "Above maximum", key: Key{Type: Integer, Max: &maximum}, value: "4", wantErr: "4 is greater than the maximum 3"},
+		{name: "String", key: Key{Type: String}, value: "anything"},
+		{name: "String with values", key: Key{Type: String, Values: []string{"On", "Off"}}, value: "Blink", wantErr: `"Blink" is not one of On,Off`},
+		{name: "CSL", key: Key{Type: CSL, Values: []string{"Current", "Power"}}, value: "Current, Power"},
+		{name: "Empty CSL", key: Key{Type: CSL, Values: []string{"Current", "Power"}}, value: ""},
+		{name: "Invalid CSL item", key: Key{Type: CSL, Values: []string{"Current", "Power"}}, value: "Current,Energy", wantErr: `"Energy" is not one of Current,Power`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := tt.key.CheckValue(tt.value)
+			if tt.wantErr != "" {
+				s.ErrorContains(err, tt.wantErr)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	suite.Run(t, new(catalogTestSuite))
+}
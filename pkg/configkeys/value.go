@@ -0,0 +1,48 @@
+package configkeys
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CheckValue checks that a value matches the type, limits and allowed values of the key.
+func (k Key) CheckValue(value string) error {
+	switch k.Type {
+	case Boolean:
+		if value != "true" && value != "false" {
+			return errors.Errorf("%q is not a valid boolean, expected true or false", value)
+		}
+	case Integer:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Errorf("%q is not a valid integer", value)
+		}
+
+		if k.Min != nil && number < *k.Min {
+			return errors.Errorf("%d is less than the minimum %d", number, *k.Min)
+		}
+
+		if k.Max != nil && number > *k.Max {
+			return errors.Errorf("%d is greater than the maximum %d", number, *k.Max)
+		}
+	case String:
+		if len(k.Values) > 0 && !slices.Contains(k.Values, value) {
+			return errors.Errorf("%q is not one of %s", value, strings.Join(k.Values, ","))
+		}
+	case CSL:
+		if value == "" || len(k.Values) == 0 {
+			return nil
+		}
+
+		for _, item := range strings.Split(value, ",") {
+			if !slices.Contains(k.Values, strings.TrimSpace(item)) {
+				return errors.Errorf("%q is not one of %s", item, strings.Join(k.Values, ","))
+			}
+		}
+	}
+
+	return nil
+}
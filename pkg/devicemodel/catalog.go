@@ -0,0 +1,176 @@
+// Package devicemodel is a catalog of the components and variables of the OCPP 2.0.1 device model, used to check
+// that device model messages (e.g. NotifyReport or SetVariables) refer to known variables with valid values.
+package devicemodel
+
+import (
+	_ "embed"
+	"os"
+	"slices"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DataType is the data type of a variable, as in the dataType of the OCPP 2.0.1 VariableCharacteristicsType.
+type DataType string
+
+const (
+	String        = DataType("string")
+	Decimal       = DataType("decimal")
+	Integer       = DataType("integer")
+	DateTime      = DataType("dateTime")
+	Boolean       = DataType("boolean")
+	OptionList    = DataType("OptionList")
+	SelectionList = DataType("SelectionList")
+	SequenceList  = DataType("SequenceList")
+	MemberList    = DataType("MemberList")
+)
+
+// dataTypes are the data types defined by OCPP 2.0.1.
+var dataTypes = []DataType{String, Decimal, Integer, DateTime, Boolean, OptionList, SelectionList, SequenceList, MemberList}
+
+// Variable is a variable of a component.
+type Variable struct {
+	Name     string   `yaml:"name"`
+	DataType DataType `yaml:"dataType"`
+	// Unit of the value, e.g. "s" or "W". Optional.
+	Unit string `yaml:"unit,omitempty"`
+	// ValuesList are the allowed values of list data types, e.g. OptionList. Any value is allowed if empty.
+	ValuesList []string `yaml:"valuesList,omitempty"`
+}
+
+// Component is a component of the device model, e.g. "OCPPCommCtrlr".
+type Component struct {
+	Name      string     `yaml:"name"`
+	Variables []Variable `yaml:"variables"`
+}
+
+// File is the format of a catalog file.
+type File struct {
+	// Variables can be reported by every component, e.g. "Available".
+	Variables  []Variable  `yaml:"variables"`
+	Components []Component `yaml:"components"`
+}
+
+//go:embed standard.yaml
+var standardCatalog []byte
+
+// Catalog holds the known components and variables.
+type Catalog struct {
+	// variables can be reported by every component.
+	variables  map[string]Variable
+	components map[string]map[string]Variable
+}
+
+// NewCatalog creates an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		variables:  make(map[string]Variable),
+		components: make(map[string]map[string]Variable),
+	}
+}
+
+// Standard returns a catalog of the standardized components and variables of OCPP 2.0.1 (Part 2, Appendix).
+func Standard() *Catalog {
+	catalog := NewCatalog()
+	if err := catalog.Parse(standardCatalog); err != nil {
+		panic(errors.Wrap(err, "invalid standard device model catalog"))
+	}
+
+	return catalog
+}
+
+// Add adds components to the catalog. The variables of a component that is already known are added to it,
+// replacing the variables with the same name.
+func (c *Catalog) Add(components ...Component) {
+	for _, component := range components {
+		if c.components[component.Name] == nil {
+			c.components[component.Name] = make(map[string]Variable)
+		}
+
+		for _, variable := range component.Variables {
+			c.components[component.Name][variable.Name] = variable
+		}
+	}
+}
+
+// AddVariables adds variables every component can report.
+func (c *Catalog) AddVariables(variables ...Variable) {
+	for _, variable := range variables {
+		c.variables[variable.Name] = variable
+	}
+}
+
+// Parse adds the components and variables of a YAML catalog file.
+func (c *Catalog) Parse(data []byte) error {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return errors.Wrap(err, "failed to parse device model catalog")
+	}
+
+	for _, variable := range file.Variables {
+		if err := validateVariable("", variable); err != nil {
+			return err
+		}
+	}
+
+	for _, component := range file.Components {
+		if component.Name == "" {
+			return errors.New("component name is required")
+		}
+
+		for _, variable := range component.Variables {
+			if err := validateVariable(component.Name, variable); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.AddVariables(file.Variables...)
+	c.Add(file.Components...)
+	return nil
+}
+
+// LoadFile adds the components and variables of a YAML catalog file.
+func (c *Catalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read device model catalog")
+	}
+
+	return errors.Wrapf(c.Parse(data), "invalid device model catalog %s", path)
+}
+
+// HasComponent returns true if the component is known.
+func (c *Catalog) HasComponent(name string) bool {
+	_, found := c.components[name]
+	return found
+}
+
+// Variable returns the variable of a component. Variables every component can report are returned for any
+// known component.
+func (c *Catalog) Variable(component, variable string) (Variable, bool) {
+	variables, found := c.components[component]
+	if !found {
+		return Variable{}, false
+	}
+
+	if v, found := variables[variable]; found {
+		return v, true
+	}
+
+	v, found := c.variables[variable]
+	return v, found
+}
+
+func validateVariable(component string, variable Variable) error {
+	if variable.Name == "" {
+		return errors.Errorf("variable name of component %q is required", component)
+	}
+
+	if !slices.Contains(dataTypes, variable.DataType) {
+		return errors.Errorf("%s: invalid dataType %q", variable.Name, variable.DataType)
+	}
+
+	return nil
+}
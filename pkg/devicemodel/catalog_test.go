@@ -0,0 +1,118 @@
+package devicemodel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogTestSuite struct {
+	suite.Suite
+}
+
+func (s *catalogTestSuite) TestStandard() {
+	catalog := Standard()
+
+	s.True(catalog.HasComponent("OCPPCommCtrlr"))
+	s.False(catalog.HasComponent("AcmeCtrlr"))
+
+	variable, found := catalog.Variable("OCPPCommCtrlr", "HeartbeatInterval")
+	s.True(found)
+	s.Equal(Variable{Name: "HeartbeatInterval", DataType: Integer, Unit: "s"}, variable)
+
+	// Variables every component can report
+	variable, found = catalog.Variable("EVSE", "Available")
+	s.True(found)
+	s.Equal(Boolean, variable.DataType)
+
+	_, found = catalog.Variable("EVSE", "HeartbeatInterval")
+	s.False(found)
+
+	_, found = catalog.Variable("AcmeCtrlr", "Available")
+	s.False(found)
+}
+
+func (s *catalogTestSuite) TestAdd() {
+	catalog := Standard()
+	catalog.Add(
+		Component{Name: "AcmeCtrlr", Variables: []Variable{{Name: "Mode", DataType: OptionList, ValuesList: []string{"Eco", "Boost"}}}},
+		Component{Name: "OCPPCommCtrlr", Variables: []Variable{{Name: "HeartbeatInterval", DataType: Decimal}}},
+	)
+
+	variable, found := catalog.Variable("AcmeCtrlr", "Mode")
+	s.True(found)
+	s.Equal([]string{"Eco", "Boost"}, variable.ValuesList)
+
+	// Known components keep their other variables
+	variable, _ = catalog.Variable("OCPPCommCtrlr", "HeartbeatInterval")
+	s.Equal(Decimal, variable.DataType)
+	s.True(catalog.HasComponent("OCPPCommCtrlr"))
+	_, found = catalog.Variable("OCPPCommCtrlr", "MessageTimeout")
+	s.True(found)
+}
+
+func (s *catalogTestSuite) TestLoadFile() {
+	path := filepath.Join(s.T().TempDir(), "catalog.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte("components:\n  - name: AcmeCtrlr\n    variables:\n      - { name: Mode, dataType: string }\n"), 0o600))
+
+	catalog := NewCatalog()
+	s.Require().NoError(catalog.LoadFile(path))
+	_, found := catalog.Variable("AcmeCtrlr", "Mode")
+	s.True(found)
+
+	s.ErrorContains(catalog.LoadFile(filepath.Join(s.T().TempDir(), "missing.yaml")), "failed to read device model catalog")
+	s.ErrorContains(catalog.Parse([]byte("components: {")), "failed to parse device model catalog")
+	s.ErrorContains(catalog.Parse([]byte("components:\n  - variables: []\n")), "component name is required")
+	s.ErrorContains(catalog.Parse([]byte("components:\n  - name: AcmeCtrlr\n    variables:\n      - { dataType: string }\n")), `variable name of component "AcmeCtrlr" is required`)
+	s.ErrorContains(catalog.Parse([]byte("variables:\n  - { name: Mode, dataType: text }\n")), `Mode: invalid dataType "text"`)
+}
+
+func (s *catalogTestSuite) TestCheckValue() {
+	minimum, maximum := 0.0, 10.0
+	limits := Limits{Min: &minimum, Max: &maximum}
+
+	tests := []struct {
+		name       string
+		dataType   DataType
+		valuesList []string
+		limits     Limits
+		value      string
+		wantErr    string
+	}{
+		{name: "String", dataType: String, value: "anything"},
+		{name: "Integer", dataType: Integer, value: "-3"},
+		{name: "Invalid integer", dataType: Integer, value: "1.5", wantErr: `"1.5" is not a valid integer`},
+		{name: "Decimal", dataType: Decimal, value: "1.5"},
+		{name: "Invalid decimal", dataType: Decimal, value: "abc", wantErr: `"abc" is not a valid decimal`},
+		{name: "Within limits", dataType: Integer, limits: limits, value: "10"},
+		{name: "Below minimum", dataType: Integer, limits: limits, value: "-1", wantErr: "-1 is less than the minimum 0"},
+		{name: "Above maximum", dataType: Decimal, limits: limits, value: "10.5", wantErr: "10.5 is greater than the maximum 10"},
+		{name: "Boolean", dataType: Boolean, value: "false"},
+		{name: "Invalid boolean", dataType: Boolean, value: "True", wantErr: `"True" is not a valid boolean`},
+		{name: "DateTime", dataType: DateTime, value: "2024-01-01T12:00:00.000Z"},
+		{name: "Invalid dateTime", dataType: DateTime, value: "2024-01-01", wantErr: `"2024-01-01" is not a valid dateTime`},
+		{name: "OptionList", dataType: OptionList, valuesList: []string{"Eco", "Boost"}, value: "Eco"},
+		{name: "OptionList without values", dataType: OptionList, value: "Eco"},
+		{name: "Invalid option", dataType: OptionList, valuesList: []string{"Eco", "Boost"}, value: "Eco,Boost", wantErr: `"Eco,Boost" is not one of Eco,Boost`},
+		{name: "MemberList", dataType: MemberList, valuesList: []string{"A", "W"}, value: "A,W"},
+		{name: "Empty MemberList", dataType: MemberList, valuesList: []string{"A", "W"}, value: ""},
+		{name: "Invalid member", dataType: SequenceList, valuesList: []string{"A", "W"}, value: "W,kW", wantErr: `"kW" is not one of A,W`},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := CheckValue(tt.dataType, tt.valuesList, tt.limits, tt.value)
+			if tt.wantErr != "" {
+				s.ErrorContains(err, tt.wantErr)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	suite.Run(t, new(catalogTestSuite))
+}
@@ -0,0 +1,66 @@
+package devicemodel
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Limits are the minimum and maximum of numeric values, as in the minLimit and maxLimit of the OCPP 2.0.1
+// VariableCharacteristicsType.
+type Limits struct {
+	Min *float64
+	Max *float64
+}
+
+// CheckValue checks that a variable attribute value matches the data type and values list of the variable.
+// List data types are comma-separated, e.g. "Authorized,EVConnected".
+func (v Variable) CheckValue(value string) error {
+	return CheckValue(v.DataType, v.ValuesList, Limits{}, value)
+}
+
+// CheckValue checks that a value matches the data type, values list and limits.
+func CheckValue(dataType DataType, valuesList []string, limits Limits, value string) error {
+	switch dataType {
+	case Integer, Decimal:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || (dataType == Integer && number != float64(int64(number))) {
+			return errors.Errorf("%q is not a valid %s", value, dataType)
+		}
+
+		if limits.Min != nil && number < *limits.Min {
+			return errors.Errorf("%s is less than the minimum %g", value, *limits.Min)
+		}
+
+		if limits.Max != nil && number > *limits.Max {
+			return errors.Errorf("%s is greater than the maximum %g", value, *limits.Max)
+		}
+	case Boolean:
+		if value != "true" && value != "false" {
+			return errors.Errorf("%q is not a valid boolean, expected true or false", value)
+		}
+	case DateTime:
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return errors.Errorf("%q is not a valid dateTime", value)
+		}
+	case OptionList:
+		if len(valuesList) > 0 && !slices.Contains(valuesList, value) {
+			return errors.Errorf("%q is not one of %s", value, strings.Join(valuesList, ","))
+		}
+	case SelectionList, SequenceList, MemberList:
+		if value == "" || len(valuesList) == 0 {
+			return nil
+		}
+
+		for _, item := range strings.Split(value, ",") {
+			if !slices.Contains(valuesList, strings.TrimSpace(item)) {
+				return errors.Errorf("%q is not one of %s", item, strings.Join(valuesList, ","))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import "sync"
+
+// textMessage is the WebSocket text frame type, used by OCPP-J. Both gorilla/websocket and nhooyr.io/websocket use 1.
+const textMessage = 1
+
+// GorillaConn is the subset of *websocket.Conn from github.com/gorilla/websocket used by GorillaConnWrapper.
+type GorillaConn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+}
+
+// GorillaConnWrapper validates the text frames read from and written to a gorilla/websocket connection.
+// Rejected inbound CALLs are answered with a CALLERROR and are not returned by ReadMessage.
+type GorillaConnWrapper struct {
+	conn         GorillaConn
+	interceptor  *Interceptor
+	connectionID string
+
+	// gorilla/websocket connections support only one concurrent writer, and ReadMessage may write a CALLERROR.
+	writeMu sync.Mutex
+}
+
+// WrapGorilla wraps a gorilla/websocket connection. All writes to the connection must go through the wrapper.
+func WrapGorilla(conn GorillaConn, interceptor *Interceptor, connectionID string) *GorillaConnWrapper {
+	return &GorillaConnWrapper{
+		conn:         conn,
+		interceptor:  interceptor,
+		connectionID: connectionID,
+	}
+}
+
+// ReadMessage reads the next message that was not rejected.
+func (w *GorillaConnWrapper) ReadMessage() (int, []byte, error) {
+	for {
+		messageType, data, err := w.conn.ReadMessage()
+		if err != nil || messageType != textMessage {
+			return messageType, data, err
+		}
+
+		callError := w.interceptor.Inbound(w.connectionID, data)
+		if callError == nil {
+			return messageType, data, nil
+		}
+
+		if err := w.write(textMessage, callError); err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+// WriteMessage validates and writes a message.
+func (w *GorillaConnWrapper) WriteMessage(messageType int, data []byte) error {
+	if messageType == textMessage {
+		w.interceptor.Outbound(w.connectionID, data)
+	}
+
+	return w.write(messageType, data)
+}
+
+// Close drops the validation session of the connection. It does not close the underlying connection.
+func (w *GorillaConnWrapper) Close() {
+	w.interceptor.Close(w.connectionID)
+}
+
+func (w *GorillaConnWrapper) write(messageType int, data []byte) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	return w.conn.WriteMessage(messageType, data)
+}
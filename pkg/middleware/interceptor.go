@@ -0,0 +1,248 @@
+// Package middleware validates OCPP-J traffic inline, as it passes through a WebSocket server. It provides
+// wrappers for gorilla/websocket and nhooyr.io/websocket (coder/websocket) connections, as well as hooks for
+// lorenzodonini/ocpp-go servers. None of these libraries are imported; the wrappers rely on the subset of
+// their APIs described by the interfaces in this package.
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/chargeflow"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+// maxErrorDescriptionLength is the maximum length of a CALLERROR errorDescription in OCPP 2.0.1 and later.
+const maxErrorDescriptionLength = 255
+
+// Direction of a frame, as seen by the server.
+type Direction string
+
+const (
+	// Inbound frames are received from the peer.
+	Inbound = Direction("inbound")
+	// Outbound frames are sent to the peer.
+	Outbound = Direction("outbound")
+)
+
+// Event describes the validation of a single frame.
+type Event struct {
+	ConnectionID string
+	Direction    Direction
+	Frame        []byte
+	Result       *chargeflow.FrameResult
+	// Rejected is true if the frame was not passed on and a CALLERROR was sent back instead.
+	Rejected bool
+	// Sequence are the results of earlier messages of the connection that violate rules spanning multiple messages.
+	// Closing a connection reports the remaining results in an event without a frame.
+	Sequence []chargeflow.SequenceResult
+}
+
+// Interceptor validates the frames of many connections. Each connection gets its own validation session,
+// so responses are only paired with requests of the same connection. An Interceptor is safe for concurrent use.
+type Interceptor struct {
+	logger    *zap.Logger
+	validator *chargeflow.Validator
+	config    options
+	octx      ocpp.OcppContext
+
+	mu       sync.Mutex
+	sessions map[string]*connection
+}
+
+type connection struct {
+	session *chargeflow.Session
+	octx    ocpp.OcppContext
+}
+
+// NewInterceptor creates an Interceptor validating frames against the given OCPP context (by default).
+// Use SetOcppContext to change the context of a single connection, e.g. after its BootNotification.
+func NewInterceptor(validator *chargeflow.Validator, octx ocpp.OcppContext, opts ...Option) *Interceptor {
+	config := options{
+		logger: zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return &Interceptor{
+		logger:    config.logger.Named("middleware"),
+		validator: validator,
+		config:    config,
+		octx:      octx,
+		sessions:  make(map[string]*connection),
+	}
+}
+
+// SetOcppContext overrides the OCPP context used for validating the frames of a connection.
+func (i *Interceptor) SetOcppContext(connectionID string, octx ocpp.OcppContext) {
+	conn := i.connection(connectionID)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	conn.octx = octx
+}
+
+// Close drops the validation session of a connection. It should be called once the connection is closed.
+func (i *Interceptor) Close(connectionID string) {
+	i.mu.Lock()
+	conn, found := i.sessions[connectionID]
+	delete(i.sessions, connectionID)
+	i.mu.Unlock()
+
+	if !found {
+		return
+	}
+
+	if sequence := conn.session.Close(); len(sequence) > 0 {
+		i.notify(Event{ConnectionID: connectionID, Sequence: sequence})
+	}
+}
+
+// Inbound validates a frame received from the peer. If rejecting invalid requests is enabled and the frame is an
+// invalid CALL, the frame should not be processed any further and the returned CALLERROR should be sent back
+// to the peer instead. Otherwise, the returned CALLERROR is nil.
+func (i *Interceptor) Inbound(connectionID string, frame []byte) []byte {
+	event, octx := i.validate(connectionID, Inbound, frame)
+	if event.Result == nil || !i.config.rejectInvalid || !shouldReject(event.Result) {
+		i.notify(event)
+		return nil
+	}
+
+	callError, err := newCallError(octx.Version, event.Result)
+	if err != nil {
+		i.logger.Error("Unable to create a CALLERROR", zap.Error(err), zap.String("connectionId", connectionID))
+		i.notify(event)
+		return nil
+	}
+
+	event.Rejected = true
+	i.notify(event)
+	return callError
+}
+
+// Outbound validates a frame sent to the peer. Outbound frames are never rejected, only reported.
+func (i *Interceptor) Outbound(connectionID string, frame []byte) {
+	event, _ := i.validate(connectionID, Outbound, frame)
+	i.notify(event)
+}
+
+func (i *Interceptor) connection(connectionID string) *connection {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	conn, found := i.sessions[connectionID]
+	if !found {
+		conn = &connection{
+			session: i.validator.NewSession(),
+			octx:    i.octx,
+		}
+		i.sessions[connectionID] = conn
+	}
+
+	return conn
+}
+
+func (i *Interceptor) validate(connectionID string, direction Direction, frame []byte) (Event, ocpp.OcppContext) {
+	event := Event{
+		ConnectionID: connectionID,
+		Direction:    direction,
+		Frame:        frame,
+	}
+
+	conn := i.connection(connectionID)
+	i.mu.Lock()
+	octx := conn.octx
+	i.mu.Unlock()
+
+	result, err := conn.session.ValidateFrame(octx, string(frame))
+	if err != nil {
+		// The frame could not be validated at all (e.g. there is no schema for the action), so it is passed on as-is.
+		i.logger.Debug("Unable to validate frame",
+			zap.Error(err),
+			zap.String("connectionId", connectionID),
+			zap.String("direction", string(direction)),
+		)
+		return event, octx
+	}
+
+	event.Result = result
+	event.Sequence = result.Sequence
+	return event, octx
+}
+
+func (i *Interceptor) notify(event Event) {
+	if i.config.handler != nil {
+		i.config.handler(event)
+		return
+	}
+
+	for _, sequence := range event.Sequence {
+		i.logger.Warn("OCPP message violates the sequence rules",
+			zap.String("connectionId", event.ConnectionID),
+			zap.String("uniqueId", sequence.UniqueId),
+			zap.String("action", sequence.Action),
+			zap.Strings("errors", sequence.Errors),
+		)
+	}
+
+	if event.Result == nil || event.Result.Valid {
+		return
+	}
+
+	i.logger.Warn("Invalid OCPP message",
+		zap.String("connectionId", event.ConnectionID),
+		zap.String("direction", string(event.Direction)),
+		zap.String("uniqueId", event.Result.UniqueId),
+		zap.String("action", event.Result.Action),
+		zap.Strings("errors", event.Result.Errors),
+		zap.Bool("rejected", event.Rejected),
+	)
+}
+
+// newCallError creates a CALLERROR frame rejecting the request.
+func newCallError(version ocpp.Version, result *chargeflow.FrameResult) ([]byte, error) {
+	description := truncate(strings.Join(result.Errors, "; "), maxErrorDescriptionLength)
+
+	return json.Marshal([]any{
+		ocpp.CALL_ERROR,
+		result.UniqueId,
+		formatViolation(version),
+		description,
+		map[string]any{"errors": result.Errors},
+	})
+}
+
+// truncate shortens the text to at most the given number of bytes, without splitting a multi-byte rune.
+func truncate(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+
+	for length > 0 && !utf8.RuneStart(text[length]) {
+		length--
+	}
+
+	return text[:length]
+}
+
+// shouldReject returns true for parsable CALLs that failed validation. Unparsable frames have no reliable
+// unique ID to reply to, so they are passed on to the OCPP implementation.
+func shouldReject(result *chargeflow.FrameResult) bool {
+	return result.MessageType == ocpp.CALL && result.Parsable && !result.Valid
+}
+
+// formatViolation returns the error code for a payload violating the schema of the action, defaulting to the
+// OCPP 2.x error code for invalid versions.
+func formatViolation(version ocpp.Version) ocpp.ErrorCode {
+	if !ocpp.IsValidProtocolVersion(version) {
+		return ocpp.FormatViolationV2
+	}
+
+	return ocpp.FormatErrorType(version)
+}
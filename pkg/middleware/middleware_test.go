@@ -0,0 +1,274 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/ChargePi/chargeflow/pkg/chargeflow"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+const (
+	validHeartbeat     = `[2, "1", "Heartbeat", {}]`
+	invalidHeartbeat   = `[2, "2", "Heartbeat", {"unexpected": true}]`
+	heartbeatResponse  = `[3, "1", {"currentTime": "2024-01-01T00:00:00Z"}]`
+	invalidAuthorize   = `[2, "3", "Authorize", {}]`
+	unparsableFrame    = `{"not": "ocpp"}`
+	binaryMessage      = 2
+	websocketChannelID = "CP1"
+)
+
+type frame struct {
+	messageType int
+	data        []byte
+}
+
+// fakeConn implements both GorillaConn and NhooyrConn.
+type fakeConn struct {
+	inbound []frame
+	written []frame
+}
+
+func (c *fakeConn) ReadMessage() (int, []byte, error) {
+	if len(c.inbound) == 0 {
+		return 0, nil, io.EOF
+	}
+
+	next := c.inbound[0]
+	c.inbound = c.inbound[1:]
+	return next.messageType, next.data, nil
+}
+
+func (c *fakeConn) WriteMessage(messageType int, data []byte) error {
+	c.written = append(c.written, frame{messageType: messageType, data: data})
+	return nil
+}
+
+type nhooyrMessageType int
+
+func (c *fakeConn) Read(_ context.Context) (nhooyrMessageType, []byte, error) {
+	messageType, data, err := c.ReadMessage()
+	return nhooyrMessageType(messageType), data, err
+}
+
+func (c *fakeConn) Write(_ context.Context, typ nhooyrMessageType, p []byte) error {
+	return c.WriteMessage(int(typ), p)
+}
+
+type channel string
+
+func (c channel) ID() string {
+	return string(c)
+}
+
+type middlewareTestSuite struct {
+	suite.Suite
+	validator *chargeflow.Validator
+}
+
+func (s *middlewareTestSuite) SetupSuite() {
+	validator, err := chargeflow.New(chargeflow.WithVersions(ocpp.V16, ocpp.V20))
+	s.Require().NoError(err)
+	s.validator = validator
+}
+
+func (s *middlewareTestSuite) newInterceptor(reject bool, events *[]Event) *Interceptor {
+	return NewInterceptor(
+		s.validator,
+		ocpp.OcppContext{Version: ocpp.V16},
+		WithRejectInvalid(reject),
+		WithEventHandler(func(event Event) { *events = append(*events, event) }),
+	)
+}
+
+func (s *middlewareTestSuite) requireCallError(data []byte, uniqueId string, code ocpp.ErrorCode) {
+	var callError []any
+	s.Require().NoError(json.Unmarshal(data, &callError))
+	s.Require().Len(callError, 5)
+	s.EqualValues(ocpp.CALL_ERROR, callError[0])
+	s.Equal(uniqueId, callError[1])
+	s.Equal(string(code), callError[2])
+	s.NotEmpty(callError[3])
+}
+
+func (s *middlewareTestSuite) TestInterceptor_Inbound() {
+	var events []Event
+	interceptor := s.newInterceptor(true, &events)
+
+	s.Nil(interceptor.Inbound(websocketChannelID, []byte(validHeartbeat)))
+	s.requireCallError(interceptor.Inbound(websocketChannelID, []byte(invalidHeartbeat)), "2", ocpp.FormatViolationV16)
+	// Unparsable frames and frames that can't be validated are passed on
+	s.Nil(interceptor.Inbound(websocketChannelID, []byte(unparsableFrame)))
+	s.Nil(interceptor.Inbound(websocketChannelID, []byte(`[2, "4", "Unknown", {}]`)))
+
+	s.Require().Len(events, 4)
+	s.True(events[0].Result.Valid)
+	s.False(events[1].Result.Valid)
+	s.True(events[1].Rejected)
+	s.False(events[2].Result.Parsable)
+	s.False(events[2].Rejected)
+	s.Nil(events[3].Result)
+
+	// The OCPP version determines the error code
+	interceptor.SetOcppContext("CP2", ocpp.OcppContext{Version: ocpp.V20})
+	s.requireCallError(interceptor.Inbound("CP2", []byte(`[2, "1", "Heartbeat", {"unexpected": true}]`)), "1", ocpp.FormatViolationV2)
+}
+
+func (s *middlewareTestSuite) TestInterceptor_ReportOnly() {
+	var events []Event
+	interceptor := s.newInterceptor(false, &events)
+
+	s.Nil(interceptor.Inbound(websocketChannelID, []byte(invalidHeartbeat)))
+	s.Require().Len(events, 1)
+	s.False(events[0].Result.Valid)
+	s.False(events[0].Rejected)
+}
+
+func (s *middlewareTestSuite) TestInterceptor_PairsPerConnection() {
+	var events []Event
+	interceptor := s.newInterceptor(true, &events)
+
+	s.Nil(interceptor.Inbound(websocketChannelID, []byte(validHeartbeat)))
+	// The response is sent to another connection, so it can't be paired
+	interceptor.Outbound("CP2", []byte(heartbeatResponse))
+	interceptor.Outbound(websocketChannelID, []byte(heartbeatResponse))
+
+	s.Require().Len(events, 3)
+	s.False(events[1].Result.Valid)
+	s.Equal(Outbound, events[2].Direction)
+	s.Equal("Heartbeat", events[2].Result.Action)
+	s.True(events[2].Result.Valid, events[2].Result.Errors)
+
+	// After closing, the session is dropped
+	s.Nil(interceptor.Inbound(websocketChannelID, []byte(validHeartbeat)))
+	interceptor.Close(websocketChannelID)
+	interceptor.Outbound(websocketChannelID, []byte(heartbeatResponse))
+	s.False(events[4].Result.Valid)
+}
+
+func (s *middlewareTestSuite) TestInterceptor_CloseReportsSequence() {
+	var events []Event
+	interceptor := s.newInterceptor(false, &events)
+
+	interceptor.Outbound(websocketChannelID, []byte(`[2, "1", "TriggerMessage", {"requestedMessage": "StatusNotification"}]`))
+	s.Nil(interceptor.Inbound(websocketChannelID, []byte(`[3, "1", {"status": "Accepted"}]`)))
+	interceptor.Close(websocketChannelID)
+
+	// The connection closed before the requested StatusNotification arrived
+	s.Require().Len(events, 3)
+	s.Nil(events[2].Frame)
+	s.Require().Len(events[2].Sequence, 1)
+	s.Equal("1", events[2].Sequence[0].UniqueId)
+	s.Equal("TriggerMessage", events[2].Sequence[0].Action)
+}
+
+func (s *middlewareTestSuite) TestNewCallError_TruncatesOnRuneBoundary() {
+	// 254 bytes followed by a 3-byte rune, which doesn't fit in the description
+	errs := []string{strings.Repeat("a", maxErrorDescriptionLength-1) + "€"}
+
+	data, err := newCallError(ocpp.V16, &chargeflow.FrameResult{UniqueId: "1", Errors: errs})
+	s.Require().NoError(err)
+
+	var callError []any
+	s.Require().NoError(json.Unmarshal(data, &callError))
+	description := callError[3].(string)
+	s.Equal(strings.Repeat("a", maxErrorDescriptionLength-1), description)
+	s.True(utf8.ValidString(description))
+	s.NotContains(description, string(utf8.RuneError))
+}
+
+func (s *middlewareTestSuite) TestGorillaConnWrapper() {
+	var events []Event
+	conn := &fakeConn{inbound: []frame{
+		{messageType: textMessage, data: []byte(invalidHeartbeat)},
+		{messageType: binaryMessage, data: []byte(invalidHeartbeat)},
+		{messageType: textMessage, data: []byte(validHeartbeat)},
+	}}
+	wrapper := WrapGorilla(conn, s.newInterceptor(true, &events), websocketChannelID)
+
+	// The invalid CALL is rejected, binary frames are not validated
+	messageType, data, err := wrapper.ReadMessage()
+	s.Require().NoError(err)
+	s.Equal(binaryMessage, messageType)
+	s.Equal(invalidHeartbeat, string(data))
+
+	_, data, err = wrapper.ReadMessage()
+	s.Require().NoError(err)
+	s.Equal(validHeartbeat, string(data))
+
+	_, _, err = wrapper.ReadMessage()
+	s.ErrorIs(err, io.EOF)
+
+	s.Require().NoError(wrapper.WriteMessage(textMessage, []byte(heartbeatResponse)))
+
+	s.Require().Len(conn.written, 2)
+	s.requireCallError(conn.written[0].data, "2", ocpp.FormatViolationV16)
+	s.Equal(heartbeatResponse, string(conn.written[1].data))
+
+	s.Require().Len(events, 3)
+	s.True(events[2].Result.Valid)
+}
+
+func (s *middlewareTestSuite) TestNhooyrConnWrapper() {
+	ctx := context.Background()
+	var events []Event
+	conn := &fakeConn{inbound: []frame{
+		{messageType: textMessage, data: []byte(invalidAuthorize)},
+		{messageType: textMessage, data: []byte(validHeartbeat)},
+	}}
+	wrapper := WrapNhooyr[nhooyrMessageType](conn, s.newInterceptor(true, &events), websocketChannelID)
+
+	_, data, err := wrapper.Read(ctx)
+	s.Require().NoError(err)
+	s.Equal(validHeartbeat, string(data))
+
+	s.Require().NoError(wrapper.Write(ctx, textMessage, []byte(heartbeatResponse)))
+
+	s.Require().Len(conn.written, 2)
+	s.requireCallError(conn.written[0].data, "3", ocpp.FormatViolationV16)
+	s.Require().Len(events, 3)
+	s.True(events[2].Result.Valid)
+}
+
+func (s *middlewareTestSuite) TestOcppGoHandlers() {
+	var (
+		events   []Event
+		handled  []string
+		written  []string
+		closedCP string
+	)
+	interceptor := s.newInterceptor(true, &events)
+
+	write := WriteHandler(interceptor, func(webSocketId string, data []byte) error {
+		written = append(written, string(data))
+		return nil
+	})
+	handler := MessageHandler(interceptor, func(ws channel, data []byte) error {
+		handled = append(handled, string(data))
+		return write(ws.ID(), []byte(heartbeatResponse))
+	}, write)
+	disconnected := DisconnectedHandler(interceptor, func(ws channel) { closedCP = ws.ID() })
+
+	s.Require().NoError(handler(channel(websocketChannelID), []byte(invalidHeartbeat)))
+	s.Require().NoError(handler(channel(websocketChannelID), []byte(validHeartbeat)))
+	disconnected(channel(websocketChannelID))
+
+	s.Equal([]string{validHeartbeat}, handled)
+	s.Require().Len(written, 2)
+	s.requireCallError([]byte(written[0]), "2", ocpp.FormatViolationV16)
+	s.Equal(websocketChannelID, closedCP)
+
+	// CALLERROR, invalid request, valid request and the paired response
+	s.Require().Len(events, 4)
+	s.True(events[3].Result.Valid, events[3].Result.Errors)
+}
+
+func TestMiddleware(t *testing.T) {
+	suite.Run(t, new(middlewareTestSuite))
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import "context"
+
+// NhooyrConn is the subset of *websocket.Conn from nhooyr.io/websocket (and its successor github.com/coder/websocket)
+// used by NhooyrConnWrapper. T is the library's websocket.MessageType.
+type NhooyrConn[T ~int] interface {
+	Read(ctx context.Context) (T, []byte, error)
+	Write(ctx context.Context, typ T, p []byte) error
+}
+
+// NhooyrConnWrapper validates the text frames read from and written to a nhooyr.io/websocket connection.
+// Rejected inbound CALLs are answered with a CALLERROR and are not returned by Read.
+type NhooyrConnWrapper[T ~int] struct {
+	conn         NhooyrConn[T]
+	interceptor  *Interceptor
+	connectionID string
+}
+
+// WrapNhooyr wraps a nhooyr.io/websocket connection. The message type cannot be inferred, so it must be given explicitly:
+//
+//	conn := middleware.WrapNhooyr[websocket.MessageType](c, interceptor, chargePointID)
+func WrapNhooyr[T ~int](conn NhooyrConn[T], interceptor *Interceptor, connectionID string) *NhooyrConnWrapper[T] {
+	return &NhooyrConnWrapper[T]{
+		conn:         conn,
+		interceptor:  interceptor,
+		connectionID: connectionID,
+	}
+}
+
+// Read reads the next message that was not rejected.
+func (w *NhooyrConnWrapper[T]) Read(ctx context.Context) (T, []byte, error) {
+	for {
+		messageType, data, err := w.conn.Read(ctx)
+		if err != nil || messageType != textMessage {
+			return messageType, data, err
+		}
+
+		callError := w.interceptor.Inbound(w.connectionID, data)
+		if callError == nil {
+			return messageType, data, nil
+		}
+
+		if err := w.conn.Write(ctx, textMessage, callError); err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+// Write validates and writes a message.
+func (w *NhooyrConnWrapper[T]) Write(ctx context.Context, typ T, p []byte) error {
+	if typ == textMessage {
+		w.interceptor.Outbound(w.connectionID, p)
+	}
+
+	return w.conn.Write(ctx, typ, p)
+}
+
+// Close drops the validation session of the connection. It does not close the underlying connection.
+func (w *NhooyrConnWrapper[T]) Close() {
+	w.interceptor.Close(w.connectionID)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+// Channel is the subset of ws.Channel from github.com/lorenzodonini/ocpp-go/ws used by the ocpp-go hooks.
+type Channel interface {
+	ID() string
+}
+
+// MessageHandler wraps the message handler of an ocpp-go ws.Server (see ws.Server.SetMessageHandler), validating
+// inbound frames before they reach the OCPP-J layer. Rejected CALLs are answered with a CALLERROR using write
+// (typically ws.Server.Write) and are not passed to next.
+func MessageHandler[C Channel](
+	interceptor *Interceptor,
+	next func(ws C, data []byte) error,
+	write func(webSocketId string, data []byte) error,
+) func(ws C, data []byte) error {
+	return func(ws C, data []byte) error {
+		callError := interceptor.Inbound(ws.ID(), data)
+		if callError == nil {
+			return next(ws, data)
+		}
+
+		return write(ws.ID(), callError)
+	}
+}
+
+// WriteHandler wraps the write function of an ocpp-go ws.Server or ws.Client (e.g. ws.Server.Write),
+// validating outbound frames.
+func WriteHandler(interceptor *Interceptor, write func(webSocketId string, data []byte) error) func(webSocketId string, data []byte) error {
+	return func(webSocketId string, data []byte) error {
+		interceptor.Outbound(webSocketId, data)
+		return write(webSocketId, data)
+	}
+}
+
+// DisconnectedHandler wraps the disconnection handler of an ocpp-go ws.Server (see ws.Server.SetDisconnectedClientHandler),
+// dropping the validation session of the disconnected charge point. next may be nil.
+func DisconnectedHandler[C Channel](interceptor *Interceptor, next func(ws C)) func(ws C) {
+	return func(ws C) {
+		interceptor.Close(ws.ID())
+		if next != nil {
+			next(ws)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import "go.uber.org/zap"
+
+type options struct {
+	logger        *zap.Logger
+	rejectInvalid bool
+	handler       func(Event)
+}
+
+type Option func(*options)
+
+// WithLogger sets the logger used by the Interceptor. Logging is disabled by default.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithRejectInvalid enables rejecting invalid inbound CALLs with a CALLERROR instead of passing them on.
+func WithRejectInvalid(reject bool) Option {
+	return func(o *options) {
+		o.rejectInvalid = reject
+	}
+}
+
+// WithEventHandler sets a function called for every validated frame, e.g. for collecting metrics.
+// It replaces the default handler, which logs invalid frames.
+func WithEventHandler(handler func(Event)) Option {
+	return func(o *options) {
+		o.handler = handler
+	}
+}
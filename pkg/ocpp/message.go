@@ -26,6 +26,8 @@ type Call struct {
 	UniqueId      string      `json:"uniqueId" validate:"required,max=36"`
 	Action        string      `json:"action" validate:"required,max=36"`
 	Payload       interface{} `json:"payload" validate:"required"`
+	// ChargeBoxIdentity is the chargeBoxIdentity header of OCPP 1.5 SOAP messages, empty for OCPP-J messages.
+	ChargeBoxIdentity string `json:"chargeBoxIdentity,omitempty"`
 }
 
 func (call *Call) GetMessageTypeId() MessageType {
@@ -52,6 +54,8 @@ type CallResult struct {
 	UniqueId      string      `json:"uniqueId"`
 	Payload       interface{} `json:"payload"`
 	Action        string      `json:"action"`
+	// ChargeBoxIdentity is the chargeBoxIdentity header of OCPP 1.5 SOAP messages, empty for OCPP-J messages.
+	ChargeBoxIdentity string `json:"chargeBoxIdentity,omitempty"`
 }
 
 func (callResult *CallResult) GetMessageTypeId() MessageType {
@@ -79,6 +83,8 @@ type CallError struct {
 	ErrorCode        ErrorCode   `json:"errorCode"`
 	ErrorDescription string      `json:"errorDescription"`
 	ErrorDetails     interface{} `json:"errorDetails"`
+	// ChargeBoxIdentity is the chargeBoxIdentity header of OCPP 1.5 SOAP messages, empty for OCPP-J messages.
+	ChargeBoxIdentity string `json:"chargeBoxIdentity,omitempty"`
 }
 
 func (callError *CallError) GetMessageTypeId() MessageType {
@@ -152,6 +158,21 @@ func (callError *CallResultError) GetAction() string {
 	return string(callError.ErrorCode)
 }
 
+// ChargeBoxIdentity returns the chargeBoxIdentity header of an OCPP 1.5 SOAP message, or an empty string if the
+// message has none.
+func ChargeBoxIdentity(message Message) string {
+	switch message := message.(type) {
+	case *Call:
+		return message.ChargeBoxIdentity
+	case *CallResult:
+		return message.ChargeBoxIdentity
+	case *CallError:
+		return message.ChargeBoxIdentity
+	default:
+		return ""
+	}
+}
+
 type ErrorCode string
 
 const (
@@ -170,22 +191,26 @@ const (
 	FormatViolationV16               ErrorCode = "FormationViolation"            // Payload for Action is syntactically incorrect or not conform the PDU structure for Action. This is only valid for OCPP 1.6
 )
 
+// FormatErrorType returns the error code for a syntactically incorrect payload in the OCPP version.
+// It panics if the version is invalid.
 func FormatErrorType(version Version) ErrorCode {
 	switch version {
-	case V16:
+	case V15, V16:
 		return FormatViolationV16
-	case V20:
+	case V20, V21:
 		return FormatViolationV2
 	default:
 		panic("invalid dialect")
 	}
 }
 
+// OccurrenceConstraintErrorType returns the error code for a payload violating occurrence constraints in the
+// OCPP version. It panics if the version is invalid.
 func OccurrenceConstraintErrorType(version Version) ErrorCode {
 	switch version {
-	case V16:
+	case V15, V16:
 		return OccurrenceConstraintViolationV16
-	case V20:
+	case V20, V21:
 		return OccurrenceConstraintViolationV2
 	default:
 		panic("invalid dialect")
@@ -22,6 +22,11 @@ func TestFormatErrorType(t *testing.T) {
 			version:  V20,
 			expected: FormatViolationV2,
 		},
+		{
+			name:     "OCPP 2.1",
+			version:  V21,
+			expected: FormatViolationV2,
+		},
 		{
 			name:    "Invalid Version",
 			version: "",
@@ -1,19 +1,117 @@
 package ocpp
 
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 const (
 	V15 = Version("1.5")
 	V16 = Version("1.6")
+	// V20 is OCPP 2.0.1. OCPP 2.0 was superseded by 2.0.1, so the identifier refers to 2.0.1.
 	V20 = Version("2.0")
 	V21 = Version("2.1")
 )
 
-// Version OCPP version of the central system or charge point
+// Version OCPP version of the central system or charge point. The value identifies the version, e.g. in schema
+// registries; use Name for the name the version is published under, e.g. in logs, metrics and responses.
 type Version string
 
+// versionAliases maps the accepted spellings of each version, without the "ocpp" prefix, to the version.
+var versionAliases = map[string]Version{
+	"1.5":   V15,
+	"15":    V15,
+	"1.6":   V16,
+	"16":    V16,
+	"1.6j":  V16,
+	"2.0":   V20,
+	"20":    V20,
+	"2.0.1": V20,
+	"201":   V20,
+	"2.1":   V21,
+	"21":    V21,
+}
+
+// ParseVersion parses an OCPP version. Besides the identifiers, it accepts the published names (e.g. "2.0.1"),
+// the names without dots (e.g. "201") and the WebSocket subprotocols (e.g. "ocpp2.0.1"), case-insensitive.
+func ParseVersion(version string) (Version, error) {
+	alias := strings.ToLower(strings.TrimSpace(version))
+	alias = strings.TrimSpace(strings.TrimPrefix(alias, "ocpp"))
+
+	parsed, found := versionAliases[alias]
+	if !found {
+		return "", errors.Errorf("invalid OCPP version %q, supported versions are 1.5, 1.6, 2.0.1 and 2.1", version)
+	}
+
+	return parsed, nil
+}
+
+// String returns the identifier of the version, e.g. "2.0" for OCPP 2.0.1. It only forms storage keys, such as
+// schema registry subject names, and is not shown to users; use Name instead.
 func (v Version) String() string {
 	return string(v)
 }
 
+// Name returns the name the version is published under, e.g. "2.0.1".
+func (v Version) Name() string {
+	if v == V20 {
+		return "2.0.1"
+	}
+
+	return string(v)
+}
+
+// Subprotocol returns the WebSocket subprotocol of the version, e.g. "ocpp2.0.1".
+func (v Version) Subprotocol() string {
+	return "ocpp" + v.Name()
+}
+
+// Features are the optional parts of the protocol a version supports.
+type Features struct {
+	// Send is true if SEND messages are supported.
+	Send bool
+	// CallResultError is true if CALLRESULTERROR messages are supported.
+	CallResultError bool
+	// SecurityExtension is true if the version can be extended with the OCPP 1.6 Security Extension. Later
+	// versions include the security features in the core protocol.
+	SecurityExtension bool
+	// Soap is true if the messages are SOAP envelopes instead of OCPP-J frames. SOAP faults are mapped to CALLERRORs,
+	// but don't use the OCPP-J error codes.
+	Soap bool
+}
+
+// Features returns the features the version supports.
+func (v Version) Features() Features {
+	return Features{
+		Send:              v == V21,
+		CallResultError:   v == V21,
+		SecurityExtension: v == V16,
+		Soap:              v == V15,
+	}
+}
+
+// securityExtensionActions are the actions added to OCPP 1.6 by the Security Extension.
+var securityExtensionActions = []string{
+	"CertificateSigned",
+	"DeleteCertificate",
+	"ExtendedTriggerMessage",
+	"GetInstalledCertificateIds",
+	"GetLog",
+	"InstallCertificate",
+	"LogStatusNotification",
+	"SecurityEventNotification",
+	"SignCertificate",
+	"SignedFirmwareStatusNotification",
+	"SignedUpdateFirmware",
+}
+
+// IsSecurityExtensionAction returns true if the action was added to OCPP 1.6 by the Security Extension.
+func IsSecurityExtensionAction(action string) bool {
+	return slices.Contains(securityExtensionActions, action)
+}
+
 func IsValidProtocolVersion(version Version) bool {
 	switch version {
 	case V15, V16,
@@ -45,3 +45,90 @@ func TestIsOcppVersionValid(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    Version
+		wantErr bool
+	}{
+		{
+			name:    "1.5",
+			version: "1.5",
+			want:    V15,
+		},
+		{
+			name:    "1.6 without dots",
+			version: "16",
+			want:    V16,
+		},
+		{
+			name:    "1.6 JSON subprotocol",
+			version: "ocpp1.6j",
+			want:    V16,
+		},
+		{
+			name:    "2.0 identifier",
+			version: "2.0",
+			want:    V20,
+		},
+		{
+			name:    "2.0.1 published name",
+			version: "2.0.1",
+			want:    V20,
+		},
+		{
+			name:    "2.0.1 subprotocol",
+			version: "OCPP2.0.1",
+			want:    V20,
+		},
+		{
+			name:    "2.1 with whitespace",
+			version: " 2.1 ",
+			want:    V21,
+		},
+		{
+			name:    "empty version",
+			version: "",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported version",
+			version: "2.2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := ParseVersion(tt.version)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "invalid OCPP version")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, version)
+		})
+	}
+}
+
+func TestVersionNames(t *testing.T) {
+	assert.Equal(t, "1.6", V16.Name())
+	assert.Equal(t, "2.0.1", V20.Name())
+	assert.Equal(t, "2.0", V20.String())
+	assert.Equal(t, "ocpp1.6", V16.Subprotocol())
+	assert.Equal(t, "ocpp2.0.1", V20.Subprotocol())
+	assert.Equal(t, "ocpp2.1", V21.Subprotocol())
+}
+
+func TestVersionFeatures(t *testing.T) {
+	assert.Equal(t, Features{Soap: true}, V15.Features())
+	assert.Equal(t, Features{SecurityExtension: true}, V16.Features())
+	assert.Equal(t, Features{}, V20.Features())
+	assert.Equal(t, Features{Send: true, CallResultError: true}, V21.Features())
+
+	assert.True(t, IsSecurityExtensionAction("SignCertificate"))
+	assert.False(t, IsSecurityExtensionAction("BootNotification"))
+}
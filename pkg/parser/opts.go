@@ -0,0 +1,14 @@
+package parser
+
+type options struct {
+	file string
+}
+
+type Option func(*options)
+
+// WithFile sets the file the messages are read from, so that every result points back to it.
+func WithFile(file string) Option {
+	return func(o *options) {
+		o.file = file
+	}
+}
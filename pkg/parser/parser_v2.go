@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 
@@ -10,7 +11,7 @@ import (
 	"github.com/ChargePi/chargeflow/pkg/ocpp"
 )
 
-// ParserV2 is used for parsing multiple OCPP-J messages. It is stateful, as it will all results of the parsing process.
+// ParserV2 is used for parsing multiple OCPP-J messages, or OCPP 1.5 SOAP envelopes (see SoapMessage). It is stateful, as it will all results of the parsing process.
 // Particularly needed for parsing files that contain request-response pairs, where the response is dependent on the request.
 // It will return a map of unique IDs to RequestResponseResult, where RequestResponseResult is a struct that contains the parsed message and any errors that occurred during parsing.
 type ParserV2 struct {
@@ -25,13 +26,22 @@ type ParserV2 struct {
 	// - Missing unique ID (responses only)
 	// - Invalid message type (e.g. not CALL, CALL_RESULT, CALL_ERROR)
 	nonParsable map[string]Result
+
+	// file the messages are read from, recorded as the origin of every result.
+	file string
 }
 
-func NewParserV2(logger *zap.Logger) *ParserV2 {
+func NewParserV2(logger *zap.Logger, opts ...Option) *ParserV2 {
+	config := options{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
 	return &ParserV2{
 		logger:      logger.Named("file_parser"),
 		results:     make(map[string]RequestResponseResult),
 		nonParsable: make(map[string]Result),
+		file:        config.file,
 	}
 }
 
@@ -43,34 +53,88 @@ func (fp *ParserV2) Parse(data []string) (map[string]RequestResponseResult, map[
 	}
 
 	// Process each message, but dont return an error if one fails to be parsed
-	for i, message := range data {
-		logger := fp.logger.With(
-			zap.String("message", message),
-			zap.Int("line", i+1),
-		)
-		logger.Info("Parsing message")
-
-		// Parse the message as JSON
-		parsedMessage, err := ParseJsonMessage(message)
-		if err != nil {
-			logger.Error("Failed to parse message", zap.Error(err))
-			result := NewResult()
-			result.AddError("Message is not a valid OCPP message")
-			key := fmt.Sprintf("line %d", i+1)
-			fp.nonParsable[key] = *result
+	for i := 0; i < len(data); i++ {
+		line, message := i+1, data[i]
+
+		// Blank lines, such as the one after a trailing newline, aren't messages
+		if strings.TrimSpace(message) == "" {
 			continue
 		}
 
-		// Actually parse the message
-		fp.parse(i+1, parsedMessage)
+		// SOAP envelopes usually span multiple lines. A truncated envelope is reported on its own,
+		// without the messages following it.
+		if IsSoapMessage(message) {
+			for joined := 1; !soapEnvelopeEnd.MatchString(message) && i+1 < len(data) && joined < maxSoapEnvelopeLines; joined++ {
+				if !continuesSoapEnvelope(data[i+1]) {
+					break
+				}
+
+				i++
+				message += "\n" + data[i]
+			}
+		}
+
+		fp.ParseNext(line, message)
 	}
 
 	return fp.results, fp.nonParsable, nil
 }
 
+// ParseNext parses a single message found at the given line and pairs it with previously parsed messages
+// sharing the same unique ID. Used for incremental parsing of streams, where messages arrive one at a time.
+// It returns the key the result is stored under (the unique ID, or "line N" for non-parsable messages),
+// the message type and whether the message was parsable.
+func (fp *ParserV2) ParseNext(line int, message string) (string, ocpp.MessageType, bool) {
+	logger := fp.logger.With(
+		zap.String("message", message),
+		zap.Int("line", line),
+	)
+	logger.Info("Parsing message")
+
+	if IsSoapMessage(message) {
+		return fp.parseSoap(line, message)
+	}
+
+	// Parse the message as JSON
+	parsedMessage, err := ParseJsonMessage(message)
+	if err != nil {
+		logger.Error("Failed to parse message", zap.Error(err))
+		result := NewResult()
+		result.AddError("Message is not a valid OCPP message")
+		result.SetOrigin(fp.file, line, message)
+		key := fmt.Sprintf("line %d", line)
+		fp.nonParsable[key] = *result
+		return key, 0, false
+	}
+
+	// Actually parse the message
+	return fp.parse(line, message, parsedMessage)
+}
+
+// Result returns the paired result stored under the given unique ID.
+func (fp *ParserV2) Result(uniqueId string) (RequestResponseResult, bool) {
+	result, found := fp.results[uniqueId]
+	return result, found
+}
+
+// NonParsable returns the non-parsable result stored under the given key.
+func (fp *ParserV2) NonParsable(key string) (Result, bool) {
+	result, found := fp.nonParsable[key]
+	return result, found
+}
+
+// Forget removes all results stored under the given key, so long-running streams don't retain
+// messages that were already processed.
+func (fp *ParserV2) Forget(key string) {
+	delete(fp.results, key)
+	delete(fp.nonParsable, key)
+}
+
 // Parses an OCPP-J message. The function expects an array of elements, as contained in the JSON message.
-func (fp *ParserV2) parse(index int, arr []interface{}) {
+// Returns the key the result was stored under, the message type and whether the message was parsable.
+func (fp *ParserV2) parse(index int, raw string, arr []interface{}) (string, ocpp.MessageType, bool) {
 	result := NewResult()
+	result.SetOrigin(fp.file, index, raw)
 	line := fmt.Sprintf("line %d", index)
 
 	// Checking message fields
@@ -78,14 +142,14 @@ func (fp *ParserV2) parse(index int, arr []interface{}) {
 		// Add to non-parsable messages if the message is too short
 		result.AddError(fmt.Sprintf("Expected at least 3 elements in the message, got %d", len(arr)))
 		fp.nonParsable[line] = *result
-		return
+		return line, 0, false
 	}
 
 	rawTypeId, ok := arr[0].(float64)
 	if !ok {
 		result.AddError("Expected first element to be a number (message type ID)")
 		fp.nonParsable[line] = *result
-		return
+		return line, 0, false
 	}
 
 	typeId := ocpp.MessageType(rawTypeId)
@@ -93,7 +157,7 @@ func (fp *ParserV2) parse(index int, arr []interface{}) {
 	if !ok {
 		result.AddError("Expected second element to be a string (unique ID)")
 		fp.nonParsable[line] = *result
-		return
+		return line, typeId, false
 	}
 
 	if uniqueId == "" {
@@ -309,5 +373,30 @@ func (fp *ParserV2) parse(index int, arr []interface{}) {
 		fp.logger.Error("Unknown message type", zap.Int("typeId", int(typeId)))
 		result.AddError(fmt.Sprintf("Unknown message type: %d", typeId))
 		fp.nonParsable[uniqueId] = *result
+		return uniqueId, typeId, false
 	}
+
+	fp.setOrigin(uniqueId, typeId, index, raw)
+
+	return uniqueId, typeId, true
+}
+
+// setOrigin records the origin of the message on the request or response it was parsed into. The request
+// and response of a message usually come from different lines.
+func (fp *ParserV2) setOrigin(uniqueId string, typeId ocpp.MessageType, line int, raw string) {
+	results, found := fp.results[uniqueId]
+	if !found {
+		return
+	}
+
+	switch typeId {
+	case ocpp.CALL, ocpp.SEND:
+		results.Request.SetOrigin(fp.file, line, raw)
+	case ocpp.CALL_RESULT, ocpp.CALL_ERROR:
+		results.Response.SetOrigin(fp.file, line, raw)
+	case ocpp.CALL_RESULT_ERROR:
+		results.ResponseError.SetOrigin(fp.file, line, raw)
+	}
+
+	fp.results[uniqueId] = results
 }
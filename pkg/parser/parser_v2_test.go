@@ -305,6 +305,20 @@ func (s *parserSuite) TestParse() {
 			parser := NewParserV2(logger)
 
 			results, nonParsedMessages, err := parser.Parse(test.data)
+
+			// Every parsed part must point back to the line it was parsed from
+			for uniqueId, result := range results {
+				for _, part := range []*Result{&result.Request, &result.Response, &result.ResponseError} {
+					s.assertOrigin(test.data, part)
+				}
+				results[uniqueId] = result
+			}
+
+			for key, result := range nonParsedMessages {
+				s.assertOrigin(test.data, &result)
+				nonParsedMessages[key] = result
+			}
+
 			s.Equal(test.expectedResults, results)
 			s.Equal(test.expectedNonParsedMessages, nonParsedMessages)
 			if test.expectedErr != nil {
@@ -316,6 +330,80 @@ func (s *parserSuite) TestParse() {
 	}
 }
 
+// assertOrigin checks that a parsed part points back to its line in the data, and clears the origin
+// so the rest of the result can be compared with the expected result.
+func (s *parserSuite) assertOrigin(data []string, part *Result) {
+	if part.message == nil && part.line == 0 {
+		return
+	}
+
+	s.Require().Positive(part.Line())
+	s.Require().LessOrEqual(part.Line(), len(data))
+	s.Equal(data[part.Line()-1], part.Raw())
+	s.Empty(part.File())
+
+	part.SetOrigin("", 0, "")
+}
+
+func (s *parserSuite) TestParseNext() {
+	parser := NewParserV2(zap.NewNop())
+
+	key, typeId, ok := parser.ParseNext(1, `[2,"1234", "BootNotification", {"chargePointVendor": "TestVendor", "chargePointModel": "TestModel"}]`)
+	s.True(ok)
+	s.Equal("1234", key)
+	s.Equal(ocpp.CALL, typeId)
+
+	key, typeId, ok = parser.ParseNext(2, `[3,"1234", {"status": "Accepted"}]`)
+	s.True(ok)
+	s.Equal("1234", key)
+	s.Equal(ocpp.CALL_RESULT, typeId)
+
+	result, found := parser.Result("1234")
+	s.Require().True(found)
+	response, found := result.GetResponse()
+	s.Require().True(found)
+	s.Equal("BootNotification", response.GetAction())
+
+	parser.Forget("1234")
+	_, found = parser.Result("1234")
+	s.False(found)
+
+	key, _, ok = parser.ParseNext(3, `{"invalid": "json"}`)
+	s.False(ok)
+	s.Equal("line 3", key)
+
+	nonParsable, found := parser.NonParsable(key)
+	s.Require().True(found)
+	s.Equal([]string{"Message is not a valid OCPP message"}, nonParsable.Errors())
+	s.Equal(3, nonParsable.Line())
+	s.Equal(`{"invalid": "json"}`, nonParsable.Raw())
+}
+
+func (s *parserSuite) TestParseOrigin() {
+	parser := NewParserV2(zap.NewNop(), WithFile("traffic.log"))
+
+	request := `[2,"1234", "Heartbeat", {}]`
+	response := `[3,"1234", {"currentTime": "2024-01-01T00:00:00Z"}]`
+
+	// The response arrives before the request
+	_, _, err := parser.Parse([]string{response, "not a message", request})
+	s.Require().NoError(err)
+
+	result, found := parser.Result("1234")
+	s.Require().True(found)
+	s.Equal("traffic.log", result.Request.File())
+	s.Equal(3, result.Request.Line())
+	s.Equal(request, result.Request.Raw())
+	s.Equal("traffic.log", result.Response.File())
+	s.Equal(1, result.Response.Line())
+	s.Equal(response, result.Response.Raw())
+
+	nonParsable, found := parser.NonParsable("line 2")
+	s.Require().True(found)
+	s.Equal("traffic.log", nonParsable.File())
+	s.Equal(2, nonParsable.Line())
+}
+
 func TestParserV2(t *testing.T) {
 	suite.Run(t, new(parserSuite))
 }
@@ -6,6 +6,11 @@ type Result struct {
 	message ocpp.Message
 	isValid bool
 	errors  []string
+
+	// Origin of the message
+	file string
+	line int
+	raw  string
 }
 
 // NewResult creates a new Result with the given validity and errors.
@@ -40,6 +45,28 @@ func (v *Result) SetMessage(message ocpp.Message) {
 	v.message = message
 }
 
+// SetOrigin sets where the message came from: the file (if any), the line within the input and the raw frame.
+func (v *Result) SetOrigin(file string, line int, raw string) {
+	v.file = file
+	v.line = line
+	v.raw = raw
+}
+
+// File returns the file the message was read from, or an empty string if it wasn't read from a file.
+func (v *Result) File() string {
+	return v.file
+}
+
+// Line returns the line of the message within the input, or 0 if unknown.
+func (v *Result) Line() int {
+	return v.line
+}
+
+// Raw returns the raw frame the message was parsed from.
+func (v *Result) Raw() string {
+	return v.raw
+}
+
 type RequestResponseResult struct {
 	// Request is the parsed OCPP request message.
 	Request Result
@@ -0,0 +1,411 @@
+package parser
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schemas"
+)
+
+const (
+	// soapValueField holds the text of elements with attributes, e.g. the value of an OCPP 1.5 meter value.
+	soapValueField = "value"
+	soapFault      = "Fault"
+)
+
+// maxSoapEnvelopeLines is the maximum number of lines a SOAP envelope is joined from, so a truncated
+// envelope doesn't swallow the rest of the file.
+const maxSoapEnvelopeLines = 1000
+
+var (
+	// soapEnvelopeStart matches the start of a SOAP envelope, e.g. "<soap:Envelope xmlns:soap=...>", optionally
+	// preceded by an XML declaration.
+	soapEnvelopeStart = regexp.MustCompile(`^\s*(<\?xml[^>]*\?>\s*)?<([\w.-]+:)?Envelope[\s/>]`)
+	// soapEnvelopeEnd matches the end of a SOAP envelope, e.g. "</soap:Envelope>".
+	soapEnvelopeEnd = regexp.MustCompile(`</([\w.-]+:)?Envelope\s*>`)
+)
+
+// xmlElement is a generic XML element.
+type xmlElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Text     string       `xml:",chardata"`
+	Children []xmlElement `xml:",any"`
+}
+
+// child returns the first child element with the local name.
+func (e xmlElement) child(name string) (xmlElement, bool) {
+	for _, child := range e.Children {
+		if child.XMLName.Local == name {
+			return child, true
+		}
+	}
+
+	return xmlElement{}, false
+}
+
+// childText returns the trimmed text of the first child element with the local name.
+func (e xmlElement) childText(name string) string {
+	child, _ := e.child(name)
+	return strings.TrimSpace(child.Text)
+}
+
+// attributes returns the attributes, without namespace declarations and XML schema instance attributes.
+func (e xmlElement) attributes() []xml.Attr {
+	var attrs []xml.Attr
+	for _, attr := range e.Attrs {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Space == "http://www.w3.org/2001/XMLSchema-instance" {
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+
+	return attrs
+}
+
+// SoapMessage is an OCPP 1.5 (or 1.2) SOAP message. The message ID and action are read from the WS-Addressing headers.
+type SoapMessage struct {
+	// Action is the OCPP action, e.g. "BootNotification".
+	Action string
+	// MessageId is the MessageID header of a request.
+	MessageId string
+	// RelatesTo is the RelatesTo header of a response, which is the message ID of the request.
+	RelatesTo         string
+	ChargeBoxIdentity string
+	IsResponse        bool
+	// body is the element in the SOAP body, e.g. bootNotificationRequest or a Fault.
+	body xmlElement
+}
+
+// IsSoapMessage returns true if the message opens a SOAP envelope rather than being an OCPP-J frame.
+func IsSoapMessage(message string) bool {
+	return soapEnvelopeStart.MatchString(message)
+}
+
+// continuesSoapEnvelope returns true if the line can be part of a SOAP envelope spanning multiple lines.
+// OCPP-J frames and the start of another envelope end a truncated envelope.
+func continuesSoapEnvelope(line string) bool {
+	return !strings.HasPrefix(strings.TrimSpace(line), "[") && !IsSoapMessage(line)
+}
+
+// ParseSoapMessage parses a SOAP envelope of an OCPP 1.5 (or 1.2) message.
+func ParseSoapMessage(message string) (*SoapMessage, error) {
+	var envelope xmlElement
+	if err := xml.Unmarshal([]byte(message), &envelope); err != nil {
+		return nil, errors.Wrap(err, "invalid XML")
+	}
+
+	if envelope.XMLName.Local != "Envelope" {
+		return nil, errors.Errorf("expected a SOAP Envelope, got %s", envelope.XMLName.Local)
+	}
+
+	body, found := envelope.child("Body")
+	if !found || len(body.Children) == 0 {
+		return nil, errors.New("SOAP Body is missing or empty")
+	}
+
+	header, _ := envelope.child("Header")
+	soapMessage := &SoapMessage{
+		MessageId:         header.childText("MessageID"),
+		RelatesTo:         header.childText("RelatesTo"),
+		ChargeBoxIdentity: header.childText("chargeBoxIdentity"),
+		body:              body.Children[0],
+	}
+
+	// The action header is a path, e.g. "/BootNotification" or "/BootNotificationResponse"
+	action := header.childText("Action")
+	if index := strings.LastIndex(action, "/"); index >= 0 {
+		action = action[index+1:]
+	}
+
+	bodyName := soapMessage.body.XMLName.Local
+	switch {
+	case bodyName == soapFault:
+		// Faults use the WS-Addressing fault action, the action of the request is used instead
+		soapMessage.IsResponse = true
+		action = ""
+	case action == "":
+		// Derive the action from the body, e.g. bootNotificationRequest
+		soapMessage.IsResponse = strings.HasSuffix(bodyName, "Response")
+		action = upperFirst(strings.TrimSuffix(strings.TrimSuffix(bodyName, "Request"), "Response"))
+	default:
+		action, soapMessage.IsResponse = strings.CutSuffix(action, "Response")
+	}
+
+	soapMessage.Action = action
+	return soapMessage, nil
+}
+
+// IsFault returns true if the message is a SOAP fault.
+func (m *SoapMessage) IsFault() bool {
+	return m.body.XMLName.Local == soapFault
+}
+
+// BodyError returns an error if the element in the SOAP body doesn't belong to the action, e.g. an
+// authorizeRequest with the BootNotification action.
+func (m *SoapMessage) BodyError() error {
+	if m.IsFault() || m.Action == "" {
+		return nil
+	}
+
+	expected := lowerFirst(m.Action) + "Request"
+	if m.IsResponse {
+		expected = lowerFirst(m.Action) + "Response"
+	}
+
+	if m.body.XMLName.Local != expected {
+		return errors.Errorf("SOAP body contains %s, expected %s", m.body.XMLName.Local, expected)
+	}
+
+	return nil
+}
+
+// Payload converts the element in the SOAP body to a JSON payload. Values are typed according to the bundled
+// OCPP 1.5 schema of the action (e.g. connectorId is a number and values is an array). Without a schema, values
+// are strings and repeated elements are arrays.
+func (m *SoapMessage) Payload() interface{} {
+	suffix := "Request"
+	if m.IsResponse {
+		suffix = "Response"
+	}
+
+	var schema map[string]interface{}
+	if data, found := schemas.Schema(ocpp.V15, m.Action+suffix); found {
+		_ = json.Unmarshal(data, &schema)
+	}
+
+	return toPayload(m.body, schema)
+}
+
+// Fault returns the code and reason of a SOAP 1.2 (or 1.1) fault. The code is the subcode if there is one,
+// e.g. "IdentityMismatch", without the namespace prefix.
+func (m *SoapMessage) Fault() (ocpp.ErrorCode, string, interface{}) {
+	fault := m.body
+
+	code := fault.childText("faultcode")
+	if faultCode, found := fault.child("Code"); found {
+		code = faultCode.childText("Value")
+		if subcode, found := faultCode.child("Subcode"); found {
+			code = subcode.childText("Value")
+		}
+	}
+
+	if index := strings.LastIndex(code, ":"); index >= 0 {
+		code = code[index+1:]
+	}
+
+	description := fault.childText("faultstring")
+	if reason, found := fault.child("Reason"); found {
+		description = reason.childText("Text")
+	}
+
+	var details interface{}
+	if detail, found := fault.child("Detail"); found {
+		details = toPayload(detail, nil)
+	} else if detail, found := fault.child("detail"); found {
+		details = toPayload(detail, nil)
+	}
+
+	return ocpp.ErrorCode(code), description, details
+}
+
+// toPayload converts an XML element to a JSON value, shaped by its JSON schema (if any).
+func toPayload(element xmlElement, schema map[string]interface{}) interface{} {
+	attrs := element.attributes()
+	text := strings.TrimSpace(element.Text)
+	schemaType := schemaTypeOf(schema)
+
+	if len(element.Children) == 0 && len(attrs) == 0 && schemaType != "object" {
+		return toScalar(text, schemaType)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	property := func(name string) map[string]interface{} {
+		propertySchema, _ := properties[name].(map[string]interface{})
+		return propertySchema
+	}
+
+	payload := make(map[string]interface{})
+	for _, attr := range attrs {
+		payload[attr.Name.Local] = toScalar(attr.Value, schemaTypeOf(property(attr.Name.Local)))
+	}
+
+	if text != "" && len(element.Children) == 0 {
+		payload[soapValueField] = toScalar(text, schemaTypeOf(property(soapValueField)))
+	}
+
+	counts := make(map[string]int)
+	for _, child := range element.Children {
+		counts[child.XMLName.Local]++
+	}
+
+	for _, child := range element.Children {
+		name := child.XMLName.Local
+		childSchema := property(name)
+
+		if schemaTypeOf(childSchema) == "array" || (childSchema == nil && counts[name] > 1) {
+			items, _ := childSchema["items"].(map[string]interface{})
+			values, _ := payload[name].([]interface{})
+			payload[name] = append(values, toPayload(child, items))
+			continue
+		}
+
+		payload[name] = toPayload(child, childSchema)
+	}
+
+	return payload
+}
+
+// toScalar converts the text of an element or attribute to the JSON schema type. Values that can't be
+// converted are left as strings, so the schema reports them.
+func toScalar(text, schemaType string) interface{} {
+	switch schemaType {
+	case "integer", "number":
+		if value, err := strconv.ParseFloat(text, 64); err == nil {
+			return value
+		}
+	case "boolean":
+		if value, err := strconv.ParseBool(text); err == nil {
+			return value
+		}
+	}
+
+	return text
+}
+
+func schemaTypeOf(schema map[string]interface{}) string {
+	schemaType, _ := schema["type"].(string)
+	return schemaType
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return string(unicode.ToUpper(rune(s[0]))) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return string(unicode.ToLower(rune(s[0]))) + s[1:]
+}
+
+// parseSoap parses a SOAP envelope found at the given line and pairs it with previously parsed messages,
+// using the MessageID header of requests and the RelatesTo header of responses as the unique ID.
+func (fp *ParserV2) parseSoap(index int, raw string) (string, ocpp.MessageType, bool) {
+	result := NewResult()
+	result.SetOrigin(fp.file, index, raw)
+	line := fmt.Sprintf("line %d", index)
+
+	message, err := ParseSoapMessage(raw)
+	if err != nil {
+		fp.logger.Error("Failed to parse SOAP message", zap.Error(err), zap.Int("line", index))
+		result.AddError(fmt.Sprintf("Message is not a valid OCPP SOAP message: %s", err))
+		fp.nonParsable[line] = *result
+		return line, 0, false
+	}
+
+	typeId := ocpp.CALL
+	uniqueId := message.MessageId
+	switch {
+	case message.IsFault():
+		typeId, uniqueId = ocpp.CALL_ERROR, message.RelatesTo
+	case message.IsResponse:
+		typeId, uniqueId = ocpp.CALL_RESULT, message.RelatesTo
+	}
+
+	if uniqueId == "" {
+		if typeId == ocpp.CALL {
+			result.AddError("MessageID header is missing in the message")
+		} else {
+			result.AddError("RelatesTo header is missing in the message")
+		}
+		// Replace the unique ID with the index of the message in the data array
+		uniqueId = line
+	}
+
+	if _, exists := fp.results[uniqueId]; !exists {
+		fp.results[uniqueId] = RequestResponseResult{
+			Request:       *NewResult(),
+			Response:      *NewResult(),
+			ResponseError: *NewResult(),
+		}
+	}
+
+	results := fp.results[uniqueId]
+	if typeId == ocpp.CALL {
+		results.Request = *result
+	} else {
+		results.Response = *result
+	}
+
+	if err := message.BodyError(); err != nil {
+		if typeId == ocpp.CALL {
+			results.AddRequestError(err.Error())
+		} else {
+			results.AddResponseError(err.Error())
+		}
+	}
+
+	switch typeId {
+	case ocpp.CALL:
+		if message.Action == "" {
+			results.AddRequestError("Action header is missing in the message")
+			break
+		}
+
+		results.AddRequest(&ocpp.Call{
+			MessageTypeId:     ocpp.CALL,
+			UniqueId:          uniqueId,
+			Action:            message.Action,
+			Payload:           message.Payload(),
+			ChargeBoxIdentity: message.ChargeBoxIdentity,
+		})
+	case ocpp.CALL_RESULT:
+		// Prefer the action of the request, as for OCPP-J responses
+		if request, found := results.GetRequest(); found {
+			message.Action = request.GetAction()
+		}
+
+		if message.Action == "" {
+			results.AddResponseError("Unable to determine response type for message")
+			break
+		}
+
+		results.AddResponse(&ocpp.CallResult{
+			MessageTypeId:     ocpp.CALL_RESULT,
+			UniqueId:          uniqueId,
+			Action:            message.Action,
+			Payload:           message.Payload(),
+			ChargeBoxIdentity: message.ChargeBoxIdentity,
+		})
+	case ocpp.CALL_ERROR:
+		errorCode, description, details := message.Fault()
+		results.AddResponse(&ocpp.CallError{
+			MessageTypeId:     ocpp.CALL_ERROR,
+			UniqueId:          uniqueId,
+			ErrorCode:         errorCode,
+			ErrorDescription:  description,
+			ErrorDetails:      details,
+			ChargeBoxIdentity: message.ChargeBoxIdentity,
+		})
+	}
+
+	fp.results[uniqueId] = results
+	fp.setOrigin(uniqueId, typeId, index, raw)
+
+	return uniqueId, typeId, true
+}
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+type soapTestSuite struct {
+	suite.Suite
+}
+
+// envelope returns a SOAP 1.2 envelope of an OCPP 1.5 message.
+func envelope(header, body string) string {
+	return `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://www.w3.org/2005/08/addressing" xmlns:cs="urn://Ocpp/Cs/2012/06/">` +
+		"<s:Header>" + header + "</s:Header><s:Body>" + body + "</s:Body></s:Envelope>"
+}
+
+func (s *soapTestSuite) TestParseSoapMessage() {
+	tests := []struct {
+		name       string
+		message    string
+		action     string
+		isResponse bool
+		isFault    bool
+	}{
+		{
+			name:    "Action from the header",
+			message: envelope("<a:Action>/Authorize</a:Action><a:MessageID>1</a:MessageID>", "<cs:authorizeRequest><cs:idTag>TAG</cs:idTag></cs:authorizeRequest>"),
+			action:  "Authorize",
+		},
+		{
+			name:       "Response action from the header",
+			message:    envelope("<a:Action>/AuthorizeResponse</a:Action><a:RelatesTo>1</a:RelatesTo>", "<cs:authorizeResponse/>"),
+			action:     "Authorize",
+			isResponse: true,
+		},
+		{
+			name:       "Action from the body",
+			message:    envelope("<a:RelatesTo>1</a:RelatesTo>", "<cs:heartbeatResponse/>"),
+			action:     "Heartbeat",
+			isResponse: true,
+		},
+		{
+			name:       "Fault",
+			message:    envelope("<a:Action>http://www.w3.org/2005/08/addressing/soap/fault</a:Action>", "<s:Fault/>"),
+			isResponse: true,
+			isFault:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			message, err := ParseSoapMessage(tt.message)
+			s.Require().NoError(err)
+			s.Equal(tt.action, message.Action)
+			s.Equal(tt.isResponse, message.IsResponse)
+			s.Equal(tt.isFault, message.IsFault())
+		})
+	}
+}
+
+func (s *soapTestSuite) TestParseSoapMessage_Unhappy() {
+	_, err := ParseSoapMessage("<cs:authorizeRequest")
+	s.ErrorContains(err, "invalid XML")
+
+	_, err = ParseSoapMessage("<authorizeRequest/>")
+	s.ErrorContains(err, "expected a SOAP Envelope")
+
+	_, err = ParseSoapMessage(envelope("", ""))
+	s.ErrorContains(err, "SOAP Body is missing or empty")
+}
+
+func (s *soapTestSuite) TestPayload() {
+	message, err := ParseSoapMessage(envelope("<a:Action>/StopTransaction</a:Action>", `<cs:stopTransactionRequest>
+		<cs:transactionId>42</cs:transactionId>
+		<cs:timestamp>2013-02-01T15:09:18Z</cs:timestamp>
+		<cs:meterStop>x</cs:meterStop>
+		<cs:transactionData>
+			<cs:values>
+				<cs:timestamp>2013-02-01T15:09:18Z</cs:timestamp>
+				<cs:value unit="Wh">1234</cs:value>
+				<cs:value>5</cs:value>
+			</cs:values>
+		</cs:transactionData>
+	</cs:stopTransactionRequest>`))
+	s.Require().NoError(err)
+
+	// Numbers that can't be parsed are left as strings
+	s.Equal(map[string]interface{}{
+		"transactionId": float64(42),
+		"timestamp":     "2013-02-01T15:09:18Z",
+		"meterStop":     "x",
+		"transactionData": []interface{}{
+			map[string]interface{}{
+				"values": []interface{}{
+					map[string]interface{}{
+						"timestamp": "2013-02-01T15:09:18Z",
+						"value": []interface{}{
+							map[string]interface{}{"value": "1234", "unit": "Wh"},
+							map[string]interface{}{"value": "5"},
+						},
+					},
+				},
+			},
+		},
+	}, message.Payload())
+
+	// Without a schema, values are strings and repeated elements are arrays
+	message, err = ParseSoapMessage(envelope("<a:Action>/Vendor</a:Action>", "<cs:vendorRequest><cs:id>1</cs:id><cs:id>2</cs:id><cs:on>true</cs:on></cs:vendorRequest>"))
+	s.Require().NoError(err)
+	s.Equal(map[string]interface{}{"id": []interface{}{"1", "2"}, "on": "true"}, message.Payload())
+}
+
+func (s *soapTestSuite) TestParse() {
+	request := envelope(
+		"<cs:chargeBoxIdentity>CB1</cs:chargeBoxIdentity><a:Action>/GetConfiguration</a:Action><a:MessageID>1</a:MessageID>",
+		"<cs:getConfigurationRequest><cs:key>HeartbeatInterval</cs:key></cs:getConfigurationRequest>",
+	)
+	fault := envelope(
+		"<cs:chargeBoxIdentity>CB1</cs:chargeBoxIdentity><a:RelatesTo>1</a:RelatesTo>",
+		`<s:Fault><s:Code><s:Value>s:Receiver</s:Value><s:Subcode><s:Value>cs:InternalError</s:Value></s:Subcode></s:Code>
+		<s:Reason><s:Text xml:lang="en">Something went wrong</s:Text></s:Reason></s:Fault>`,
+	)
+
+	// The request spans multiple lines
+	data := append(strings.Split(strings.ReplaceAll(request, "><", ">\n<"), "\n"), fault)
+
+	results, nonParsable, err := NewParserV2(zap.NewNop()).Parse(data)
+	s.Require().NoError(err)
+	s.Empty(nonParsable)
+	s.Require().Contains(results, "1")
+
+	result := results["1"]
+	s.True(result.IsValid())
+	s.Equal(1, result.Request.Line())
+	s.Equal(len(data), result.Response.Line())
+
+	s.Equal(&ocpp.Call{
+		MessageTypeId:     ocpp.CALL,
+		UniqueId:          "1",
+		Action:            "GetConfiguration",
+		Payload:           map[string]interface{}{"key": []interface{}{"HeartbeatInterval"}},
+		ChargeBoxIdentity: "CB1",
+	}, result.Request.Message())
+	s.Equal(&ocpp.CallError{
+		MessageTypeId:     ocpp.CALL_ERROR,
+		UniqueId:          "1",
+		ErrorCode:         ocpp.InternalError,
+		ErrorDescription:  "Something went wrong",
+		ChargeBoxIdentity: "CB1",
+	}, result.Response.Message())
+}
+
+func (s *soapTestSuite) TestParse_Unhappy() {
+	results, nonParsable, err := NewParserV2(zap.NewNop()).Parse([]string{
+		envelope("<a:Action>/Authorize</a:Action>", "<cs:authorizeRequest><cs:idTag>TAG</cs:idTag></cs:authorizeRequest>"),
+		envelope("<a:Action>/Authorize</a:Action><a:MessageID>2</a:MessageID>", "<cs:heartbeatRequest/>"),
+		"<s:Envelope>",
+	})
+	s.Require().NoError(err)
+
+	missingId, mismatch := results["line 1"], results["2"]
+	s.Equal([]string{"MessageID header is missing in the message"}, missingId.Request.Errors())
+	s.Equal([]string{"SOAP body contains heartbeatRequest, expected authorizeRequest"}, mismatch.Request.Errors())
+	s.Contains(nonParsable, "line 3")
+}
+
+func (s *soapTestSuite) TestParse_TruncatedEnvelope() {
+	results, nonParsable, err := NewParserV2(zap.NewNop()).Parse([]string{
+		`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope">`,
+		"<s:Body>",
+		`[2,"1","Heartbeat",{}]`,
+		`<note>not an envelope</note>`,
+		`[3,"1",{"currentTime":"2024-01-01T00:00:00Z"}]`,
+	})
+	s.Require().NoError(err)
+
+	// The truncated envelope doesn't swallow the frames after it
+	s.Require().Contains(nonParsable, "line 1")
+	truncated := nonParsable["line 1"]
+	s.Equal(2, strings.Count(truncated.Raw(), "\n")+1)
+	s.Contains(nonParsable, "line 4")
+
+	s.Require().Contains(results, "1")
+	heartbeat := results["1"]
+	s.True(heartbeat.IsValid())
+	s.Equal(3, heartbeat.Request.Line())
+	s.Equal(5, heartbeat.Response.Line())
+}
+
+func TestSoap(t *testing.T) {
+	suite.Run(t, new(soapTestSuite))
+}
@@ -0,0 +1,178 @@
+package report
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/validator"
+)
+
+const (
+	unknownAction      = "unknown"
+	unknownVendorModel = "unknown"
+	bootNotification   = "BootNotification"
+)
+
+// statistics calculates the statistics and their breakdowns from the aggregated results.
+func (a *Aggregator) statistics() Statistics {
+	stats := Statistics{
+		UnparsableMessages: len(a.nonParsableMessages),
+		ByAction:           map[string]Counts{},
+		ByErrorKind:        map[string]int{},
+		ByField:            map[string]int{},
+		ByErrorCode:        map[string]int{},
+		ByVendorModel:      map[string]Counts{},
+	}
+
+	vendorModels := a.vendorModels()
+
+	for messageId, parts := range a.results {
+		action := resultsAction(parts)
+
+		for part, results := range parts {
+			isRequest := part == requestKey
+			isValid := results.ValidationResult.IsValid() && results.Result.IsValid()
+
+			switch {
+			case isRequest && isValid:
+				stats.ValidRequests++
+			case isRequest:
+				stats.InvalidRequests++
+			case isValid:
+				stats.ValidResponses++
+			default:
+				stats.InvalidResponses++
+			}
+
+			stats.ByAction[action] = stats.ByAction[action].add(isRequest, isValid)
+			stats.ByVendorModel[vendorModels[messageId]] = stats.ByVendorModel[vendorModels[messageId]].add(isRequest, isValid)
+
+			for _, category := range results.ValidationResult.ErrorCategories() {
+				stats.ByErrorKind[string(category)]++
+			}
+
+			// Parser errors are structural errors of the message
+			if errs := results.Result.Errors(); len(errs) > 0 {
+				stats.ByErrorKind[string(validator.CategoryMessage)] += len(errs)
+			}
+
+			for _, field := range results.ValidationResult.Fields() {
+				stats.ByField[action+field]++
+			}
+
+			if errorCode := callErrorCode(results.Result.Message()); errorCode != "" {
+				stats.ByErrorCode[errorCode]++
+			}
+		}
+	}
+
+	if stats.UnparsableMessages > 0 {
+		stats.ByErrorKind[string(validator.CategoryMessage)] += stats.UnparsableMessages
+	}
+
+	return stats
+}
+
+// vendorModels attributes every message to a charging station vendor and model. Unless set upfront, messages
+// are attributed to the vendor and model of the last BootNotification request before them.
+func (a *Aggregator) vendorModels() map[string]string {
+	vendorModels := make(map[string]string, len(a.results))
+
+	messageIds := slices.Collect(maps.Keys(a.results))
+	slices.SortFunc(messageIds, func(x, y string) int {
+		return cmp.Or(cmp.Compare(resultsLine(a.results[x]), resultsLine(a.results[y])), cmp.Compare(x, y))
+	})
+
+	current := cmp.Or(a.vendorModel, unknownVendorModel)
+	for _, messageId := range messageIds {
+		if a.vendorModel == "" {
+			request := a.results[messageId][requestKey]
+			if vendorModel := bootNotificationVendorModel(request.Result.Message()); vendorModel != "" {
+				current = vendorModel
+			}
+		}
+
+		vendorModels[messageId] = current
+	}
+
+	return vendorModels
+}
+
+// resultsAction returns the action of a message. The action is taken from the request, as CALLERROR
+// responses don't carry one.
+func resultsAction(parts map[string]Results) string {
+	for _, part := range []string{requestKey, responseKey} {
+		results := parts[part]
+		message := results.Result.Message()
+		if message == nil || callErrorCode(message) != "" {
+			continue
+		}
+
+		if action := message.GetAction(); action != "" {
+			return action
+		}
+	}
+
+	return unknownAction
+}
+
+// resultsLine returns the first line any part of the message was found at.
+func resultsLine(parts map[string]Results) int {
+	line := math.MaxInt
+	for _, results := range parts {
+		if results.Result.Line() > 0 {
+			line = min(line, results.Result.Line())
+		}
+	}
+
+	return line
+}
+
+// callErrorCode returns the error code of a CALLERROR or CALLRESULTERROR message, or an empty string for other messages.
+func callErrorCode(message ocpp.Message) string {
+	switch m := message.(type) {
+	case *ocpp.CallError:
+		return string(m.ErrorCode)
+	case *ocpp.CallResultError:
+		return string(m.ErrorCode)
+	default:
+		return ""
+	}
+}
+
+// bootNotificationVendorModel returns the vendor and model reported by a BootNotification request, for both
+// OCPP 1.6 (chargePointVendor, chargePointModel) and 2.x (chargingStation.vendorName, chargingStation.model).
+func bootNotificationVendorModel(message ocpp.Message) string {
+	if message == nil || message.GetAction() != bootNotification {
+		return ""
+	}
+
+	payload, ok := message.GetPayload().(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	if chargingStation, ok := payload["chargingStation"].(map[string]interface{}); ok {
+		payload = map[string]interface{}{
+			"chargePointVendor": chargingStation["vendorName"],
+			"chargePointModel":  chargingStation["model"],
+		}
+	}
+
+	vendor, _ := payload["chargePointVendor"].(string)
+	model, _ := payload["chargePointModel"].(string)
+	return formatVendorModel(vendor, model)
+}
+
+// formatVendorModel returns the key of a vendor and model in the statistics, e.g. "ABB/Terra AC".
+func formatVendorModel(vendor, model string) string {
+	switch {
+	case vendor == "" || model == "":
+		return vendor + model
+	default:
+		return vendor + "/" + model
+	}
+}
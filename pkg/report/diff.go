@@ -0,0 +1,114 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// FailureKindNonParsable is the kind of failures of messages that could not be parsed.
+const FailureKindNonParsable = "non_parsable"
+
+// Failure is an error reported for requests, responses or non-parsable messages. Failures are identified by
+// their kind, action and error rather than the message ID, as unique IDs differ between recordings of the same flow.
+type Failure struct {
+	// Kind is either request, response or non_parsable.
+	Kind   string `json:"kind"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error"`
+	// Occurrences are the messages the failure was reported for, e.g. "42 (messages.txt:12)".
+	Occurrences []string `json:"occurrences"`
+}
+
+func (f Failure) key() string {
+	return f.Kind + "\x00" + f.Action + "\x00" + f.Error
+}
+
+// Diff contains the failures that were introduced or resolved between two reports.
+type Diff struct {
+	Introduced []Failure `json:"introduced"`
+	Resolved   []Failure `json:"resolved"`
+}
+
+// HasChanges returns true if any failures were introduced or resolved.
+func (d Diff) HasChanges() bool {
+	return len(d.Introduced) > 0 || len(d.Resolved) > 0
+}
+
+// Compare returns the failures of the current report that are not in the previous report, and vice versa.
+// Useful for tracking regressions across e.g. firmware versions.
+func Compare(previous, current *Report) Diff {
+	previousFailures := previous.failures()
+	currentFailures := current.failures()
+
+	previousKeys := map[string]bool{}
+	for _, failure := range previousFailures {
+		previousKeys[failure.key()] = true
+	}
+
+	currentKeys := map[string]bool{}
+	for _, failure := range currentFailures {
+		currentKeys[failure.key()] = true
+	}
+
+	diff := Diff{Introduced: []Failure{}, Resolved: []Failure{}}
+	for _, failure := range currentFailures {
+		if !previousKeys[failure.key()] {
+			diff.Introduced = append(diff.Introduced, failure)
+		}
+	}
+
+	for _, failure := range previousFailures {
+		if !currentKeys[failure.key()] {
+			diff.Resolved = append(diff.Resolved, failure)
+		}
+	}
+
+	return diff
+}
+
+// failures groups all errors of the report into failures, in the order they first occurred in.
+func (r *Report) failures() []Failure {
+	var failures []Failure
+	indexes := map[string]int{}
+
+	add := func(failure Failure, occurrence string) {
+		index, found := indexes[failure.key()]
+		if !found {
+			index = len(failures)
+			indexes[failure.key()] = index
+			failures = append(failures, failure)
+		}
+
+		failures[index].Occurrences = append(failures[index].Occurrences, occurrence)
+	}
+
+	for _, messageId := range r.InvalidMessageIds() {
+		parts := r.InvalidMessages[messageId]
+		for _, part := range slices.Sorted(maps.Keys(parts)) {
+			details := r.Messages[messageId][part]
+
+			occurrence := messageId
+			if location := details.Location(); location != "" {
+				occurrence = fmt.Sprintf("%s (%s)", messageId, location)
+			}
+
+			for _, err := range parts[part] {
+				add(Failure{Kind: part, Action: details.Action, Error: err}, occurrence)
+			}
+		}
+	}
+
+	for _, key := range r.NonParsableKeys() {
+		occurrence := key
+		if location := r.NonParsableDetails[key].Location(); location != "" {
+			occurrence = location
+		}
+
+		for _, err := range r.NonParsableMessages[key] {
+			add(Failure{Kind: FailureKindNonParsable, Error: err}, occurrence)
+		}
+	}
+
+	return failures
+}
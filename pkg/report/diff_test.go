@@ -0,0 +1,78 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type diffTestSuite struct {
+	suite.Suite
+}
+
+func (s *diffTestSuite) TestCompare() {
+	previous := &Report{
+		InvalidMessages: map[string]map[string][]string{
+			"1": {"request": {"Required property 'chargePointModel' is missing"}},
+			"2": {"response": {"Property 'interval' does not match the schema"}},
+		},
+		Messages: map[string]map[string]MessageDetails{
+			"1": {"request": {Action: "BootNotification", File: "old.txt", Line: 1}},
+			"2": {"response": {Action: "BootNotification", File: "old.txt", Line: 3}},
+		},
+		NonParsableMessages: map[string][]string{"line 4": {"Message is not a valid OCPP message"}},
+	}
+
+	// Unique IDs differ between recordings, failures are matched by their action and error
+	current := &Report{
+		InvalidMessages: map[string]map[string][]string{
+			"a": {"request": {"Required property 'chargePointModel' is missing"}},
+			"b": {"request": {"Required property 'idTag' is missing"}},
+			"c": {"request": {"Required property 'idTag' is missing"}},
+		},
+		Messages: map[string]map[string]MessageDetails{
+			"a": {"request": {Action: "BootNotification", File: "new.txt", Line: 1}},
+			"b": {"request": {Action: "Authorize", File: "new.txt", Line: 2}},
+			"c": {"request": {Action: "Authorize"}},
+		},
+	}
+
+	diff := Compare(previous, current)
+	s.True(diff.HasChanges())
+	s.Equal([]Failure{
+		{
+			Kind:        "request",
+			Action:      "Authorize",
+			Error:       "Required property 'idTag' is missing",
+			Occurrences: []string{"b (new.txt:2)", "c"},
+		},
+	}, diff.Introduced)
+	s.Equal([]Failure{
+		{
+			Kind:        "response",
+			Action:      "BootNotification",
+			Error:       "Property 'interval' does not match the schema",
+			Occurrences: []string{"2 (old.txt:3)"},
+		},
+		{
+			Kind:        FailureKindNonParsable,
+			Error:       "Message is not a valid OCPP message",
+			Occurrences: []string{"line 4"},
+		},
+	}, diff.Resolved)
+}
+
+func (s *diffTestSuite) TestCompareNoChanges() {
+	r := &Report{
+		InvalidMessages: map[string]map[string][]string{"1": {"request": {"e1"}}},
+	}
+
+	diff := Compare(r, r)
+	s.False(diff.HasChanges())
+	s.Empty(diff.Introduced)
+	s.Empty(diff.Resolved)
+}
+
+func TestDiff(t *testing.T) {
+	suite.Run(t, new(diffTestSuite))
+}
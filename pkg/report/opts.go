@@ -0,0 +1,17 @@
+package report
+
+type options struct {
+	vendor string
+	model  string
+}
+
+type Option func(*options)
+
+// WithVendorModel attributes all messages to the given charging station vendor and model in the statistics.
+// Without it, the vendor and model are taken from the BootNotification requests in the traffic.
+func WithVendorModel(vendor, model string) Option {
+	return func(o *options) {
+		o.vendor = vendor
+		o.model = model
+	}
+}
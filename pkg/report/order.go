@@ -0,0 +1,66 @@
+package report
+
+import (
+	"cmp"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SortMessageIds sorts the message IDs by the line the message was found at, then by ID, so the output
+// of the report is the same on every run. Messages without a known line are sorted last.
+func (r *Report) SortMessageIds(ids []string) {
+	slices.SortFunc(ids, func(a, b string) int {
+		return cmp.Or(cmp.Compare(r.messageLine(a), r.messageLine(b)), cmp.Compare(a, b))
+	})
+}
+
+// InvalidMessageIds returns the IDs of the invalid messages, in the order they were found in.
+func (r *Report) InvalidMessageIds() []string {
+	ids := slices.Collect(maps.Keys(r.InvalidMessages))
+	r.SortMessageIds(ids)
+	return ids
+}
+
+// MessageIds returns the IDs of all parsed messages, in the order they were found in.
+func (r *Report) MessageIds() []string {
+	ids := slices.Collect(maps.Keys(r.Messages))
+	r.SortMessageIds(ids)
+	return ids
+}
+
+// NonParsableKeys returns the keys of the non-parsable messages, in the order they were found in.
+func (r *Report) NonParsableKeys() []string {
+	keys := slices.Collect(maps.Keys(r.NonParsableMessages))
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(r.nonParsableLine(a), r.nonParsableLine(b)), cmp.Compare(a, b))
+	})
+	return keys
+}
+
+// messageLine returns the first line any part of the message was found at.
+func (r *Report) messageLine(messageId string) int {
+	line := math.MaxInt
+	for _, details := range r.Messages[messageId] {
+		if details.Line > 0 {
+			line = min(line, details.Line)
+		}
+	}
+
+	return line
+}
+
+// nonParsableLine returns the line of a non-parsable message, falling back to the line in its key ("line N").
+func (r *Report) nonParsableLine(key string) int {
+	if line := r.NonParsableDetails[key].Line; line > 0 {
+		return line
+	}
+
+	if line, err := strconv.Atoi(strings.TrimPrefix(key, "line ")); err == nil {
+		return line
+	}
+
+	return math.MaxInt
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type orderTestSuite struct {
+	suite.Suite
+}
+
+func (s *orderTestSuite) TestInvalidMessageIds() {
+	r := &Report{
+		InvalidMessages: map[string]map[string][]string{
+			"a": {"request": {"e1"}},
+			"b": {"response": {"e2"}},
+			"c": {"request": {"e3"}},
+			"d": {"request": {"e4"}},
+		},
+		Messages: map[string]map[string]MessageDetails{
+			// The earliest part of the message determines its position
+			"a": {"request": {Line: 9}, "response": {Line: 2}},
+			"b": {"response": {Line: 5}},
+			"c": {"request": {Line: 1}},
+		},
+	}
+
+	s.Equal([]string{"c", "a", "b", "d"}, r.InvalidMessageIds())
+}
+
+func (s *orderTestSuite) TestMessageIdsWithoutLines() {
+	r := &Report{
+		Messages: map[string]map[string]MessageDetails{
+			"b": {"request": {}},
+			"a": {"request": {}},
+		},
+	}
+
+	s.Equal([]string{"a", "b"}, r.MessageIds())
+}
+
+func (s *orderTestSuite) TestNonParsableKeys() {
+	r := &Report{
+		NonParsableMessages: map[string][]string{
+			"line 10": {"e1"},
+			"line 9":  {"e2"},
+			"abc":     {"e3"},
+			"line 2":  {"e4"},
+		},
+		NonParsableDetails: map[string]MessageDetails{
+			"abc": {Line: 3},
+		},
+	}
+
+	s.Equal([]string{"line 2", "abc", "line 9", "line 10"}, r.NonParsableKeys())
+}
+
+func TestOrder(t *testing.T) {
+	suite.Run(t, new(orderTestSuite))
+}
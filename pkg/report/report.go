@@ -1,18 +1,97 @@
 package report
 
 import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
 	"github.com/ChargePi/chargeflow/pkg/parser"
 	"github.com/ChargePi/chargeflow/pkg/validator"
 )
 
 type Report struct {
+	// Source is the file the messages were read from, if any.
+	Source string `json:"source,omitempty"`
 	// InvalidMessages contains all the errors per message (request or response)
-	InvalidMessages     map[string]map[string][]string `json:"invalid_messages"`
-	NonParsableMessages map[string][]string            `json:"non_parsable_messages"`
-	Statistics          Statistics                     `json:"statistics"`
+	InvalidMessages map[string]map[string][]string `json:"invalid_messages"`
+	// ValidMessages contains the parts (request and/or response) of each message that passed validation.
+	ValidMessages       map[string][]string `json:"valid_messages,omitempty"`
+	NonParsableMessages map[string][]string `json:"non_parsable_messages"`
+	// Messages contains the details of every request and response, by message ID and then by request/response.
+	Messages map[string]map[string]MessageDetails `json:"messages,omitempty"`
+	// NonParsableDetails contains the details of every non-parsable message, by the same key as NonParsableMessages.
+	NonParsableDetails map[string]MessageDetails `json:"non_parsable_details,omitempty"`
+	Statistics         Statistics                `json:"statistics"`
+}
+
+// MessageDetails holds the details of a single request or response, e.g. for rendering rich reports.
+type MessageDetails struct {
+	// File the message was read from, if any.
+	File string `json:"file,omitempty"`
+	// Line of the message within the input, starting at 1.
+	Line int `json:"line,omitempty"`
+	// Raw is the original frame.
+	Raw    string `json:"raw,omitempty"`
+	Action string `json:"action,omitempty"`
+	// ChargeBoxIdentity is the chargeBoxIdentity header of OCPP 1.5 SOAP messages.
+	ChargeBoxIdentity string          `json:"charge_box_identity,omitempty"`
+	Payload           json.RawMessage `json:"payload,omitempty"`
+	Errors            []ErrorDetails  `json:"errors,omitempty"`
+	// Fields are the JSON pointers of the payload fields that failed validation, e.g. "/idTagInfo/status".
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ErrorDetails is a single error of a request or response, along with its category.
+type ErrorDetails struct {
+	Category validator.ErrorCategory `json:"category"`
+	Message  string                  `json:"message"`
 }
 
 type Results struct {
 	validator.ValidationResult
 	parser.Result
 }
+
+// details returns the details of the request or response.
+func (r Results) details() MessageDetails {
+	details := MessageDetails{
+		File:   r.Result.File(),
+		Line:   r.Result.Line(),
+		Raw:    r.Result.Raw(),
+		Fields: r.ValidationResult.Fields(),
+	}
+
+	if message := r.Result.Message(); message != nil {
+		details.Action = message.GetAction()
+		details.ChargeBoxIdentity = ocpp.ChargeBoxIdentity(message)
+
+		// Payloads that can't be marshalled are simply left out
+		if payload, err := json.Marshal(message.GetPayload()); err == nil {
+			details.Payload = payload
+		}
+	}
+
+	categories := r.ValidationResult.ErrorCategories()
+	for i, err := range r.ValidationResult.Errors() {
+		details.Errors = append(details.Errors, ErrorDetails{Category: categories[i], Message: err})
+	}
+
+	// Parser errors are structural errors of the message
+	for _, err := range r.Result.Errors() {
+		details.Errors = append(details.Errors, ErrorDetails{Category: validator.CategoryMessage, Message: err})
+	}
+
+	return details
+}
+
+// Location returns where the message came from, e.g. "messages.txt:12", "line 12" or an empty string if unknown.
+func (d MessageDetails) Location() string {
+	switch {
+	case d.Line == 0:
+		return d.File
+	case d.File == "":
+		return fmt.Sprintf("line %d", d.Line)
+	default:
+		return fmt.Sprintf("%s:%d", d.File, d.Line)
+	}
+}
@@ -1,6 +1,8 @@
 package report
 
 import (
+	"slices"
+
 	"go.uber.org/zap"
 
 	"github.com/ChargePi/chargeflow/pkg/parser"
@@ -27,19 +29,28 @@ type Aggregator struct {
 
 	// Map by message ID and then by request/response
 	results             map[string]map[string]Results
-	nonParsableMessages map[string][]string
+	nonParsableMessages map[string]parser.Result
+
+	// vendorModel the messages are attributed to, if known upfront.
+	vendorModel string
 
 	reportGenerated bool
 	stats           Statistics
 	report          Report
 }
 
-func NewAggregator(logger *zap.Logger) *Aggregator {
+func NewAggregator(logger *zap.Logger, opts ...Option) *Aggregator {
+	config := options{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
 	return &Aggregator{
 		logger:              logger.Named("result_aggregator"),
+		vendorModel:         formatVendorModel(config.vendor, config.model),
 		stats:               Statistics{},
 		results:             make(map[string]map[string]Results),
-		nonParsableMessages: make(map[string][]string),
+		nonParsableMessages: make(map[string]parser.Result),
 		reportGenerated:     false,
 		report:              Report{},
 	}
@@ -88,7 +99,7 @@ func (a *Aggregator) AddNonParsableMessage(messageId string, parserResult parser
 	}
 
 	a.logger.Debug("Adding non parsable message", zap.String("messageId", messageId))
-	a.nonParsableMessages[messageId] = parserResult.Errors()
+	a.nonParsableMessages[messageId] = parserResult
 }
 
 // CreateReport creates a report based on the collected results.
@@ -103,40 +114,45 @@ func (a *Aggregator) CreateReport() Report {
 
 	report := Report{
 		InvalidMessages:     make(map[string]map[string][]string),
-		NonParsableMessages: a.nonParsableMessages,
+		ValidMessages:       make(map[string][]string),
+		NonParsableMessages: make(map[string][]string, len(a.nonParsableMessages)),
+		Messages:            make(map[string]map[string]MessageDetails),
+		NonParsableDetails:  make(map[string]MessageDetails, len(a.nonParsableMessages)),
+	}
+
+	for key, parserResult := range a.nonParsableMessages {
+		report.NonParsableMessages[key] = parserResult.Errors()
+		report.NonParsableDetails[key] = Results{Result: parserResult}.details()
 	}
 
 	for messageId, reqResponse := range a.results {
 		for r, results := range reqResponse {
-
-			isRequest := r == requestKey
 			isValid := results.ValidationResult.IsValid() && results.Result.IsValid()
 
-			// Keep track of statistics
-			switch {
-			case isRequest && isValid:
-				a.stats.ValidRequests++
-			case isRequest:
-				a.stats.InvalidRequests++
-			case isValid:
-				a.stats.ValidResponses++
-			default:
-				a.stats.InvalidResponses++
+			if report.Messages[messageId] == nil {
+				report.Messages[messageId] = make(map[string]MessageDetails)
 			}
+			report.Messages[messageId][r] = results.details()
 
-			// Request failed validation or parsing
-			if !results.ValidationResult.IsValid() || !results.Result.IsValid() {
-				if report.InvalidMessages[messageId] == nil {
-					report.InvalidMessages[messageId] = make(map[string][]string)
-				}
+			if isValid {
+				report.ValidMessages[messageId] = append(report.ValidMessages[messageId], r)
+				continue
+			}
 
-				report.InvalidMessages[messageId][r] = append(results.ValidationResult.Errors(), results.Result.Errors()...)
+			// Request failed validation or parsing
+			if report.InvalidMessages[messageId] == nil {
+				report.InvalidMessages[messageId] = make(map[string][]string)
 			}
+
+			report.InvalidMessages[messageId][r] = append(results.ValidationResult.Errors(), results.Result.Errors()...)
 		}
 	}
 
-	// Store UnparsableMessages count in stats
-	a.stats.UnparsableMessages = len(a.nonParsableMessages)
+	for _, parts := range report.ValidMessages {
+		slices.Sort(parts)
+	}
+
+	a.stats = a.statistics()
 
 	// Attach statistics to the report
 	report.Statistics = a.stats
@@ -149,29 +165,10 @@ func (a *Aggregator) CreateReport() Report {
 
 // GetStatistics returns the request and response statistics.
 func (a *Aggregator) GetStatistics() Statistics {
+	// If the report has already been generated, stats are already calculated
 	if !a.reportGenerated {
 		a.logger.Debug("Calculating statistics from aggregated results")
-		// If the report has already been generated, stats are already calculated
-		for _, reqResponse := range a.results {
-			for r, results := range reqResponse {
-
-				isRequest := r == requestKey
-				isValid := results.ValidationResult.IsValid() && results.Result.IsValid()
-
-				// Keep track of statistics
-				switch {
-				case isRequest && isValid:
-					a.stats.ValidRequests++
-				case isRequest:
-					a.stats.InvalidRequests++
-				case isValid:
-					a.stats.ValidResponses++
-				default:
-					a.stats.InvalidResponses++
-				}
-			}
-		}
-		a.stats.UnparsableMessages = len(a.nonParsableMessages)
+		a.stats = a.statistics()
 	}
 
 	return a.stats
@@ -182,7 +179,7 @@ func (a *Aggregator) Reset() {
 	a.logger.Debug("Resetting aggregator state")
 
 	a.results = make(map[string]map[string]Results)
-	a.nonParsableMessages = make(map[string][]string)
+	a.nonParsableMessages = make(map[string]parser.Result)
 	a.reportGenerated = false
 	a.stats = Statistics{}
 }
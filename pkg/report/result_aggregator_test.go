@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
 	"github.com/ChargePi/chargeflow/pkg/parser"
 	"github.com/ChargePi/chargeflow/pkg/validator"
 
@@ -123,6 +124,7 @@ func (s *aggregatorTestSuite) TestAddNonParsableMessage() {
 
 			result := parser.NewResult()
 			result.AddError("error in request non parsable")
+			result.SetOrigin("messages.txt", 3, "not json")
 
 			aggregator.AddNonParsableMessage(test.messageId, *result)
 
@@ -131,7 +133,17 @@ func (s *aggregatorTestSuite) TestAddNonParsableMessage() {
 				return
 			}
 
-			s.Equal(result.Errors(), aggregator.nonParsableMessages[test.messageId])
+			nonParsable := aggregator.nonParsableMessages[test.messageId]
+			s.Equal(result.Errors(), nonParsable.Errors())
+
+			report := aggregator.CreateReport()
+			s.Equal(result.Errors(), report.NonParsableMessages[test.messageId])
+			s.Equal(MessageDetails{
+				File:   "messages.txt",
+				Line:   3,
+				Raw:    "not json",
+				Errors: []ErrorDetails{{Category: validator.CategoryMessage, Message: "error in request non parsable"}},
+			}, report.NonParsableDetails[test.messageId])
 		})
 	}
 }
@@ -321,6 +333,8 @@ func (s *aggregatorTestSuite) TestExampleFlow() {
 	s.NotEmpty(report.InvalidMessages)
 	s.NotContains(report.InvalidMessages, messageId)
 	s.Contains(report.InvalidMessages, parseErrorMessageId)
+	s.Equal([]string{"request"}, report.ValidMessages[messageId])
+	s.NotContains(report.ValidMessages, parseErrorMessageId)
 
 	s.NotEmpty(report.NonParsableMessages)
 	s.Contains(report.NonParsableMessages, nonParsableMessageId)
@@ -334,6 +348,90 @@ func (s *aggregatorTestSuite) TestExampleFlow() {
 	s.Empty(aggregator.results)
 }
 
+func (s *aggregatorTestSuite) TestCreateReportMessageDetails() {
+	aggregator := NewAggregator(s.logger)
+
+	parserResult := parser.NewResult()
+	parserResult.SetOrigin("messages.txt", 7, `[2, "1", "Authorize", {"idTag": 1234}]`)
+	parserResult.SetMessage(&ocpp.Call{
+		MessageTypeId: ocpp.CALL,
+		UniqueId:      "1",
+		Action:        "Authorize",
+		Payload:       map[string]interface{}{"idTag": 1234},
+	})
+	aggregator.AddParserResult("1", true, *parserResult)
+
+	validationResult := validator.NewValidationResult()
+	validationResult.AddCategorizedError(validator.ErrorCategory("type"), "Value is integer but should be string")
+	validationResult.AddFields("/idTag")
+	aggregator.AddValidationResults("1", true, *validationResult)
+
+	report := aggregator.CreateReport()
+	s.Require().Contains(report.Messages, "1")
+
+	details := report.Messages["1"]["request"]
+	s.Equal("Authorize", details.Action)
+	s.Equal("messages.txt:7", details.Location())
+	s.Equal(`[2, "1", "Authorize", {"idTag": 1234}]`, details.Raw)
+	s.JSONEq(`{"idTag": 1234}`, string(details.Payload))
+	s.Equal([]string{"/idTag"}, details.Fields)
+	s.Equal([]ErrorDetails{{Category: "type", Message: "Value is integer but should be string"}}, details.Errors)
+}
+
+func (s *aggregatorTestSuite) TestCreateReportStatisticsBreakdown() {
+	aggregator := NewAggregator(s.logger)
+
+	boot := parser.NewResult()
+	boot.SetOrigin("", 1, "")
+	boot.SetMessage(&ocpp.Call{
+		MessageTypeId: ocpp.CALL,
+		UniqueId:      "1",
+		Action:        "BootNotification",
+		Payload:       map[string]interface{}{"chargePointVendor": "ABB", "chargePointModel": "Terra AC", "firmwareVersion": 1},
+	})
+	aggregator.AddParserResult("1", true, *boot)
+
+	bootValidation := validator.NewValidationResult()
+	bootValidation.AddCategorizedError(validator.ErrorCategory("type"), "Value is integer but should be string")
+	bootValidation.AddFields("/firmwareVersion")
+	aggregator.AddValidationResults("1", true, *bootValidation)
+
+	heartbeat := parser.NewResult()
+	heartbeat.SetOrigin("", 2, "")
+	heartbeat.SetMessage(&ocpp.Call{MessageTypeId: ocpp.CALL, UniqueId: "2", Action: "Heartbeat", Payload: map[string]interface{}{}})
+	aggregator.AddParserResult("2", true, *heartbeat)
+
+	callError := parser.NewResult()
+	callError.SetOrigin("", 3, "")
+	callError.SetMessage(&ocpp.CallError{MessageTypeId: ocpp.CALL_ERROR, UniqueId: "2", ErrorCode: ocpp.NotSupported})
+	aggregator.AddParserResult("2", false, *callError)
+	aggregator.AddValidationResults("2", true, *validator.NewValidationResult())
+	aggregator.AddValidationResults("2", false, *validator.NewValidationResult())
+
+	unparsable := parser.NewResult()
+	unparsable.AddError("Message is not a valid OCPP message")
+	aggregator.AddNonParsableMessage("line 4", *unparsable)
+
+	stats := aggregator.CreateReport().Statistics
+	s.Equal(map[string]Counts{
+		"BootNotification": {InvalidRequests: 1},
+		"Heartbeat":        {ValidRequests: 1, ValidResponses: 1},
+	}, stats.ByAction)
+	s.Equal(map[string]int{"type": 1, string(validator.CategoryMessage): 1}, stats.ByErrorKind)
+	s.Equal(map[string]int{"BootNotification/firmwareVersion": 1}, stats.ByField)
+	s.Equal(map[string]int{"NotSupported": 1}, stats.ByErrorCode)
+	s.Equal(map[string]Counts{"ABB/Terra AC": {InvalidRequests: 1, ValidRequests: 1, ValidResponses: 1}}, stats.ByVendorModel)
+	s.Equal([]string{"BootNotification", "Heartbeat"}, MostInvalid(stats.ByAction))
+}
+
+func (s *aggregatorTestSuite) TestStatisticsWithVendorModel() {
+	aggregator := NewAggregator(s.logger, WithVendorModel("Alfen", "Eve"))
+	aggregator.AddParserResult("1", true, *parser.NewResult())
+	aggregator.AddValidationResults("1", true, *validator.NewValidationResult())
+
+	s.Equal(map[string]Counts{"Alfen/Eve": {ValidRequests: 1}}, aggregator.GetStatistics().ByVendorModel)
+}
+
 func TestAggregator(t *testing.T) {
 	suite.Run(t, new(aggregatorTestSuite))
 }
@@ -1,11 +1,88 @@
 package report
 
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
 type Statistics struct {
 	ValidRequests      int
 	ValidResponses     int
 	InvalidRequests    int
 	InvalidResponses   int
 	UnparsableMessages int
+	// ByAction breaks the requests and responses down by action.
+	ByAction map[string]Counts `json:",omitempty"`
+	// ByErrorKind counts the errors by kind, e.g. the failing JSON schema keyword ("required").
+	ByErrorKind map[string]int `json:",omitempty"`
+	// ByField counts the failing fields by action and JSON pointer, e.g. "BootNotification/chargePointModel".
+	ByField map[string]int `json:",omitempty"`
+	// ByErrorCode counts the CALLERROR responses by their error code, e.g. "NotSupported".
+	ByErrorCode map[string]int `json:",omitempty"`
+	// ByVendorModel breaks the requests and responses down by the charging station's vendor and model.
+	ByVendorModel map[string]Counts `json:",omitempty"`
+}
+
+// Counts are the valid and invalid requests and responses of a part of the traffic, e.g. of a single action.
+type Counts struct {
+	ValidRequests    int
+	InvalidRequests  int
+	ValidResponses   int
+	InvalidResponses int
+}
+
+// Total returns the number of requests and responses.
+func (c Counts) Total() int {
+	return c.ValidRequests + c.InvalidRequests + c.ValidResponses + c.InvalidResponses
+}
+
+// Invalid returns the number of invalid requests and responses.
+func (c Counts) Invalid() int {
+	return c.InvalidRequests + c.InvalidResponses
+}
+
+// InvalidPercentage returns the percentage of invalid requests and responses.
+func (c Counts) InvalidPercentage() float64 {
+	if c.Total() == 0 {
+		return 0.0
+	}
+
+	return getPercentage(c.Invalid(), c.Total())
+}
+
+// add counts a request or response.
+func (c Counts) add(isRequest, isValid bool) Counts {
+	switch {
+	case isRequest && isValid:
+		c.ValidRequests++
+	case isRequest:
+		c.InvalidRequests++
+	case isValid:
+		c.ValidResponses++
+	default:
+		c.InvalidResponses++
+	}
+
+	return c
+}
+
+// MostInvalid returns the keys of the breakdown, with the most invalid requests and responses first.
+func MostInvalid(counts map[string]Counts) []string {
+	keys := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b].Invalid(), counts[a].Invalid()), cmp.Compare(a, b))
+	})
+	return keys
+}
+
+// MostFrequent returns the keys of the breakdown, most frequent first.
+func MostFrequent(counts map[string]int) []string {
+	keys := slices.Collect(maps.Keys(counts))
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	return keys
 }
 
 func (s *Statistics) ValidRequestPercentage() float64 {
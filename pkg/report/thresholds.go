@@ -0,0 +1,67 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Outcome is the outcome of evaluating a report against the thresholds.
+type Outcome int
+
+const (
+	// OutcomePassed means the report is within the thresholds.
+	OutcomePassed Outcome = iota
+	// OutcomeInvalid means there are more invalid messages than allowed.
+	OutcomeInvalid
+	// OutcomeUnparsable means some messages could not be parsed.
+	OutcomeUnparsable
+)
+
+// Thresholds decide whether a report fails, e.g. to gate a release in a CI pipeline.
+type Thresholds struct {
+	// FailOnInvalid fails the report if the percentage of invalid requests and responses exceeds MaxInvalidPercent.
+	FailOnInvalid bool
+	// FailOnUnparsable fails the report if any message could not be parsed.
+	FailOnUnparsable bool
+	// MaxInvalidPercent is the percentage of invalid requests and responses that is tolerated.
+	MaxInvalidPercent float64
+	// FailOnActions are actions that must always be valid. Any invalid request or response of these actions
+	// fails the report, regardless of the other thresholds.
+	FailOnActions []string
+}
+
+// Evaluate evaluates the report against the thresholds. Unparsable messages take precedence over invalid ones,
+// as they usually mean the input itself is broken. The reasons explain why the report failed.
+func (t Thresholds) Evaluate(r *Report) (Outcome, []string) {
+	var reasons []string
+	outcome := OutcomePassed
+
+	stats := r.Statistics
+	if t.FailOnInvalid && stats.TotalInvalidMessagesPercentage() > t.MaxInvalidPercent {
+		outcome = OutcomeInvalid
+		reasons = append(reasons, fmt.Sprintf(
+			"%.2f%% of the messages are invalid, at most %.2f%% is allowed",
+			stats.TotalInvalidMessagesPercentage(), t.MaxInvalidPercent,
+		))
+	}
+
+	for _, messageId := range r.InvalidMessageIds() {
+		for _, part := range slices.Sorted(maps.Keys(r.InvalidMessages[messageId])) {
+			action := r.Messages[messageId][part].Action
+			if !slices.Contains(t.FailOnActions, action) {
+				continue
+			}
+
+			outcome = OutcomeInvalid
+			reasons = append(reasons, fmt.Sprintf("%s of message %s (%s) is invalid", part, messageId, action))
+		}
+	}
+
+	if t.FailOnUnparsable && stats.UnparsableMessages > 0 {
+		outcome = OutcomeUnparsable
+		reasons = append(reasons, fmt.Sprintf("%d messages could not be parsed", stats.UnparsableMessages))
+	}
+
+	return outcome, reasons
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type thresholdsTestSuite struct {
+	suite.Suite
+}
+
+func (s *thresholdsTestSuite) TestEvaluate() {
+	// 1 of 4 requests and responses is invalid (25%), 1 message could not be parsed
+	r := &Report{
+		InvalidMessages: map[string]map[string][]string{
+			"1": {"request": {"Required property 'chargePointModel' is missing"}},
+		},
+		Messages: map[string]map[string]MessageDetails{
+			"1": {"request": {Action: "BootNotification"}, "response": {Action: "BootNotification"}},
+			"2": {"request": {Action: "Heartbeat"}, "response": {Action: "Heartbeat"}},
+		},
+		NonParsableMessages: map[string][]string{"line 5": {"Message is not a valid OCPP message"}},
+		Statistics: Statistics{
+			ValidRequests:      1,
+			InvalidRequests:    1,
+			ValidResponses:     2,
+			UnparsableMessages: 1,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		thresholds      Thresholds
+		expectedOutcome Outcome
+		expectedReasons int
+	}{
+		{
+			name:            "No thresholds",
+			thresholds:      Thresholds{},
+			expectedOutcome: OutcomePassed,
+		},
+		{
+			name:            "Fail on invalid",
+			thresholds:      Thresholds{FailOnInvalid: true},
+			expectedOutcome: OutcomeInvalid,
+			expectedReasons: 1,
+		},
+		{
+			name:            "Invalid messages within the threshold",
+			thresholds:      Thresholds{FailOnInvalid: true, MaxInvalidPercent: 25},
+			expectedOutcome: OutcomePassed,
+		},
+		{
+			name:            "Invalid messages exceed the threshold",
+			thresholds:      Thresholds{FailOnInvalid: true, MaxInvalidPercent: 5},
+			expectedOutcome: OutcomeInvalid,
+			expectedReasons: 1,
+		},
+		{
+			name:            "Invalid message of a required action",
+			thresholds:      Thresholds{FailOnActions: []string{"BootNotification"}},
+			expectedOutcome: OutcomeInvalid,
+			expectedReasons: 1,
+		},
+		{
+			name:            "Required action is valid",
+			thresholds:      Thresholds{FailOnActions: []string{"Heartbeat"}},
+			expectedOutcome: OutcomePassed,
+		},
+		{
+			name:            "Unparsable messages take precedence",
+			thresholds:      Thresholds{FailOnInvalid: true, FailOnUnparsable: true},
+			expectedOutcome: OutcomeUnparsable,
+			expectedReasons: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			outcome, reasons := tt.thresholds.Evaluate(r)
+			s.Equal(tt.expectedOutcome, outcome)
+			s.Len(reasons, tt.expectedReasons)
+		})
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	suite.Run(t, new(thresholdsTestSuite))
+}
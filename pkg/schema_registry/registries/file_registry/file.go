@@ -2,6 +2,7 @@ package file_registry
 
 import (
 	"context"
+	"slices"
 	"strings"
 	"sync"
 
@@ -60,7 +61,7 @@ func NewFileSchemaRegistry(logger *zap.Logger, opts ...RegistryOption) *SchemaRe
 // The rawSchema should be a valid JSON schema in raw format.
 // The action is the name of the OCPP action that this schema applies to. Must be suffixed with either "Request" or "Response".
 func (fsr *SchemaRegistry) RegisterSchema(_ context.Context, req schema_registry.CreateSchemaRequest) error {
-	logger := fsr.logger.With(zap.String("ocppVersion", req.OcppContext.Version.String()), zap.String("action", req.Action))
+	logger := fsr.logger.With(zap.String("ocppVersion", req.OcppContext.Version.Name()), zap.String("action", req.Action))
 	logger.Debug("Registering schema")
 
 	// Validate the OCPP version
@@ -73,6 +74,10 @@ func (fsr *SchemaRegistry) RegisterSchema(_ context.Context, req schema_registry
 		return errors.Errorf("action must end with 'Request' or 'Response': %s", req.Action)
 	}
 
+	if err := schema_registry.ValidateSchemaKey(req.Action, req.VendorId, req.MessageId); err != nil {
+		return err
+	}
+
 	logger.Debug("Compiling schema")
 	schema, err := compiler.Compile(req.Schema)
 	if err != nil {
@@ -86,12 +91,12 @@ func (fsr *SchemaRegistry) RegisterSchema(_ context.Context, req schema_registry
 		fsr.schemasPerOcppVersion[req.OcppContext.Version] = make(map[string]*jsonschema.Schema)
 	}
 
-	key := buildStorageKey(req.OcppContext.Vendor, req.OcppContext.Model, req.Action)
+	key := buildStorageKey(req.OcppContext.Vendor, req.OcppContext.Model, schema_registry.SchemaKey(req.Action, req.VendorId, req.MessageId))
 
 	if !fsr.config.overwrite {
 		logger.Debug("Overwriting previous schema")
 		if _, exists := fsr.schemasPerOcppVersion[req.OcppContext.Version][key]; exists {
-			return errors.Errorf("schema for action %s already exists for OCPP version %s", req.Action, req.OcppContext.Version)
+			return errors.Errorf("schema for action %s already exists for OCPP version %s", req.Action, req.OcppContext.Version.Name())
 		}
 	}
 
@@ -102,7 +107,7 @@ func (fsr *SchemaRegistry) RegisterSchema(_ context.Context, req schema_registry
 
 // DeleteSchema removes a schema for a specific OCPP version and action.
 func (fsr *SchemaRegistry) DeleteSchema(_ context.Context, req schema_registry.DeleteSchemaRequest) error {
-	logger := fsr.logger.With(zap.String("ocppVersion", req.OcppContext.Version.String()), zap.String("action", req.Action))
+	logger := fsr.logger.With(zap.String("ocppVersion", req.OcppContext.Version.Name()), zap.String("action", req.Action))
 	logger.Debug("Deleting schema")
 
 	if !ocpp.IsValidProtocolVersion(req.OcppContext.Version) {
@@ -114,12 +119,12 @@ func (fsr *SchemaRegistry) DeleteSchema(_ context.Context, req schema_registry.D
 
 	schemas, exists := fsr.schemasPerOcppVersion[req.OcppContext.Version]
 	if !exists {
-		return errors.Errorf("no schemas registered for OCPP version %s", req.OcppContext.Version)
+		return errors.Errorf("no schemas registered for OCPP version %s", req.OcppContext.Version.Name())
 	}
 
-	key := buildStorageKey(req.OcppContext.Vendor, req.OcppContext.Model, req.Action)
+	key := buildStorageKey(req.OcppContext.Vendor, req.OcppContext.Model, schema_registry.SchemaKey(req.Action, req.VendorId, req.MessageId))
 	if _, exists := schemas[key]; !exists {
-		return errors.Errorf("schema for action %s not found for OCPP version %s", req.Action, req.OcppContext.Version)
+		return errors.Errorf("schema for action %s not found for OCPP version %s", req.Action, req.OcppContext.Version.Name())
 	}
 
 	delete(schemas, key)
@@ -148,7 +153,7 @@ func buildStorageKey(vendor, model, action string) string {
 // schema and falls back to the base OCPP spec schema.
 func (fsr *SchemaRegistry) GetSchema(_ context.Context, req schema_registry.GetSchemaRequest) (*jsonschema.Schema, bool) {
 	fsr.logger.Debug("Getting schema",
-		zap.String("ocppVersion", req.OcppContext.Version.String()),
+		zap.String("ocppVersion", req.OcppContext.Version.Name()),
 		zap.String("action", req.Action),
 		zap.String("vendor", req.OcppContext.Vendor),
 		zap.String("model", req.OcppContext.Model),
@@ -162,21 +167,52 @@ func (fsr *SchemaRegistry) GetSchema(_ context.Context, req schema_registry.GetS
 		return nil, false
 	}
 
+	action := schema_registry.SchemaKey(req.Action, req.VendorId, req.MessageId)
+
 	// Try vendor/model-specific key first when either field is provided.
 	if req.OcppContext.Vendor != "" || req.OcppContext.Model != "" {
-		if schema, ok := schemas[buildStorageKey(req.OcppContext.Vendor, req.OcppContext.Model, req.Action)]; ok {
+		if schema, ok := schemas[buildStorageKey(req.OcppContext.Vendor, req.OcppContext.Model, action)]; ok {
 			return schema, true
 		}
 	}
 
 	// Fall back to the base OCPP spec schema.
-	if schema, ok := schemas[req.Action]; ok {
+	if schema, ok := schemas[action]; ok {
 		return schema, true
 	}
 
 	return nil, false
 }
 
+// ListSchemas returns the actions registered for a specific OCPP version, including
+// vendor/model-specific schemas when Vendor and/or Model are set.
+func (fsr *SchemaRegistry) ListSchemas(_ context.Context, req schema_registry.ListSchemasRequest) ([]string, error) {
+	if !ocpp.IsValidProtocolVersion(req.OcppContext.Version) {
+		return nil, errors.Errorf("invalid OCPP version: %s", req.OcppContext.Version)
+	}
+
+	fsr.mu.RLock()
+	defer fsr.mu.RUnlock()
+
+	specificPrefix := ""
+	if req.OcppContext.Vendor != "" || req.OcppContext.Model != "" {
+		specificPrefix = buildStorageKey(req.OcppContext.Vendor, req.OcppContext.Model, "")
+	}
+
+	actions := make([]string, 0, len(fsr.schemasPerOcppVersion[req.OcppContext.Version]))
+	for key := range fsr.schemasPerOcppVersion[req.OcppContext.Version] {
+		switch {
+		case !strings.Contains(key, "|"):
+			actions = append(actions, key)
+		case specificPrefix != "" && strings.HasPrefix(key, specificPrefix):
+			actions = append(actions, strings.TrimPrefix(key, specificPrefix))
+		}
+	}
+
+	slices.Sort(actions)
+	return slices.Compact(actions), nil
+}
+
 func (fsr *SchemaRegistry) Type() string {
 	return "file"
 }
@@ -370,6 +370,110 @@ func (s *fileRegistryTestSuite) TestRegisterAndDeleteLifecycle() {
 	s.NoError(err)
 }
 
+func (s *fileRegistryTestSuite) TestDataTransferSchemas() {
+	ctx := context.Background()
+	registry := NewFileSchemaRegistry(s.logger)
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+
+	s.Require().NoError(registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{
+		OcppContext: octx,
+		Action:      "DataTransferRequest",
+		Schema:      json.RawMessage(`{"type": "object"}`),
+	}))
+	s.Require().NoError(registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{
+		OcppContext: octx,
+		Action:      "DataTransferRequest",
+		VendorId:    "com.acme",
+		MessageId:   "SetLevel",
+		Schema:      json.RawMessage(`{"type": "object", "required": ["level"]}`),
+	}))
+
+	payloadSchema, found := registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest"})
+	s.Require().True(found)
+	dataSchema, found := registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel"})
+	s.Require().True(found)
+	s.NotSame(payloadSchema, dataSchema)
+
+	// Vendor/model-specific lookups fall back to the data schema, not to the payload schema
+	schema, found := registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: ocpp.OcppContext{Version: ocpp.V16, Vendor: "Acme"}, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel"})
+	s.True(found)
+	s.Same(dataSchema, schema)
+
+	_, found = registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "GetLevel"})
+	s.False(found)
+
+	err := registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{OcppContext: octx, Action: "AuthorizeRequest", VendorId: "com.acme", Schema: json.RawMessage(`{}`)})
+	s.ErrorContains(err, "vendorId and messageId are only supported for DataTransfer schemas, not AuthorizeRequest")
+
+	err = registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{OcppContext: octx, Action: "DataTransferResponse", MessageId: "SetLevel", Schema: json.RawMessage(`{}`)})
+	s.ErrorContains(err, "vendorId is required when a messageId is set")
+
+	s.Require().NoError(registry.DeleteSchema(ctx, schema_registry.DeleteSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel"}))
+	_, found = registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel"})
+	s.False(found)
+	_, found = registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest"})
+	s.True(found)
+}
+
+func (s *fileRegistryTestSuite) TestListSchemas() {
+	ctx := context.Background()
+
+	const emptySchema = `{"type": "object"}`
+
+	registry := NewFileSchemaRegistry(s.logger)
+	for _, req := range []schema_registry.CreateSchemaRequest{
+		{OcppContext: ocpp.OcppContext{Version: ocpp.V16}, Action: "HeartbeatRequest"},
+		{OcppContext: ocpp.OcppContext{Version: ocpp.V16}, Action: "AuthorizeRequest"},
+		{OcppContext: ocpp.OcppContext{Version: ocpp.V16, Vendor: "Acme", Model: "X1"}, Action: "AuthorizeRequest"},
+		{OcppContext: ocpp.OcppContext{Version: ocpp.V16, Vendor: "Acme", Model: "X1"}, Action: "DataTransferRequest"},
+		{OcppContext: ocpp.OcppContext{Version: ocpp.V20}, Action: "CostUpdatedRequest"},
+	} {
+		req.Schema = json.RawMessage(emptySchema)
+		s.Require().NoError(registry.RegisterSchema(ctx, req))
+	}
+
+	tests := []struct {
+		name     string
+		req      schema_registry.ListSchemasRequest
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "Base schemas only",
+			req:      schema_registry.ListSchemasRequest{OcppContext: ocpp.OcppContext{Version: ocpp.V16}},
+			expected: []string{"AuthorizeRequest", "HeartbeatRequest"},
+		},
+		{
+			name:     "Vendor/model-specific schemas are merged with base schemas",
+			req:      schema_registry.ListSchemasRequest{OcppContext: ocpp.OcppContext{Version: ocpp.V16, Vendor: "Acme", Model: "X1"}},
+			expected: []string{"AuthorizeRequest", "DataTransferRequest", "HeartbeatRequest"},
+		},
+		{
+			name:     "No schemas registered for version",
+			req:      schema_registry.ListSchemasRequest{OcppContext: ocpp.OcppContext{Version: ocpp.V21}},
+			expected: []string{},
+		},
+		{
+			name:    "Invalid OCPP version",
+			req:     schema_registry.ListSchemasRequest{OcppContext: ocpp.OcppContext{Version: "unknown_version"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			actions, err := registry.ListSchemas(ctx, tt.req)
+			if tt.wantErr {
+				s.Error(err)
+				return
+			}
+
+			s.NoError(err)
+			s.Equal(tt.expected, actions)
+		})
+	}
+}
+
 func TestInMemoryRegistry(t *testing.T) {
 	suite.Run(t, new(fileRegistryTestSuite))
 }
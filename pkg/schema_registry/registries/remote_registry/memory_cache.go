@@ -3,6 +3,7 @@ package remote_registry
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kaptinlin/jsonschema"
@@ -13,11 +14,20 @@ type cachedSchema struct {
 	cachedAt time.Time
 }
 
+// CacheStats holds the number of cache lookups that returned a schema (hits) and those that did not (misses).
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
 // MemoryCache is a thread-safe in-memory Cache with TTL-based expiry, keyed by subject name.
 type MemoryCache struct {
 	mu      sync.RWMutex
 	entries map[string]*cachedSchema
 	ttl     time.Duration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
 }
 
 func NewMemoryCache(ttl time.Duration) *MemoryCache {
@@ -32,17 +42,23 @@ func (m *MemoryCache) Get(_ context.Context, subject string) (*jsonschema.Schema
 	defer m.mu.RUnlock()
 
 	entry, ok := m.entries[subject]
-	if !ok {
-		return nil, false
-	}
-
-	if time.Since(entry.cachedAt) >= m.ttl {
+	if !ok || time.Since(entry.cachedAt) >= m.ttl {
+		m.misses.Add(1)
 		return nil, false
 	}
 
+	m.hits.Add(1)
 	return entry.schema, true
 }
 
+// Stats returns the number of cache hits and misses since the cache was created. Expired entries count as misses.
+func (m *MemoryCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   m.hits.Load(),
+		Misses: m.misses.Load(),
+	}
+}
+
 func (m *MemoryCache) Set(_ context.Context, subject string, schema *jsonschema.Schema) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,33 @@
+package remote_registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kaptinlin/jsonschema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache_Stats(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(time.Minute)
+
+	_, found := cache.Get(ctx, "ocpp-1.6-HeartbeatRequest")
+	require.False(t, found)
+
+	cache.Set(ctx, "ocpp-1.6-HeartbeatRequest", &jsonschema.Schema{})
+	_, found = cache.Get(ctx, "ocpp-1.6-HeartbeatRequest")
+	require.True(t, found)
+	_, found = cache.Get(ctx, "ocpp-1.6-HeartbeatRequest")
+	require.True(t, found)
+
+	require.Equal(t, CacheStats{Hits: 2, Misses: 1}, cache.Stats())
+
+	// Expired entries count as misses
+	expiring := NewMemoryCache(0)
+	expiring.Set(ctx, "ocpp-1.6-HeartbeatRequest", &jsonschema.Schema{})
+	_, found = expiring.Get(ctx, "ocpp-1.6-HeartbeatRequest")
+	require.False(t, found)
+	require.Equal(t, CacheStats{Misses: 1}, expiring.Stats())
+}
@@ -250,7 +250,7 @@ type registerSchemaPayload struct {
 }
 
 func (r *SchemaRegistry) RegisterSchema(ctx context.Context, req schema_registry.CreateSchemaRequest) error {
-	logger := r.logger.With(zap.String("ocppVersion", req.OcppContext.Version.String()), zap.String("action", req.Action))
+	logger := r.logger.With(zap.String("ocppVersion", req.OcppContext.Version.Name()), zap.String("action", req.Action))
 	logger.Debug("Registering schema to remote registry")
 
 	// Validate the OCPP version
@@ -263,7 +263,11 @@ func (r *SchemaRegistry) RegisterSchema(ctx context.Context, req schema_registry
 		return errors.Errorf("action must end with 'Request' or 'Response': %s", req.Action)
 	}
 
-	subject := buildSubjectName(req.OcppContext.Version, req.Action, req.OcppContext.Vendor, req.OcppContext.Model)
+	if err := schema_registry.ValidateSchemaKey(req.Action, req.VendorId, req.MessageId); err != nil {
+		return err
+	}
+
+	subject := buildSubjectName(req.OcppContext.Version, schema_registry.SchemaKey(req.Action, req.VendorId, req.MessageId), req.OcppContext.Vendor, req.OcppContext.Model)
 
 	ctx, cancel := context.WithTimeout(ctx, r.config.timeout)
 	defer cancel()
@@ -343,7 +347,7 @@ func (r *SchemaRegistry) RegisterSchema(ctx context.Context, req schema_registry
 }
 
 func (r *SchemaRegistry) DeleteSchema(ctx context.Context, req schema_registry.DeleteSchemaRequest) error {
-	logger := r.logger.With(zap.String("ocppVersion", req.OcppContext.Version.String()), zap.String("action", req.Action))
+	logger := r.logger.With(zap.String("ocppVersion", req.OcppContext.Version.Name()), zap.String("action", req.Action))
 	logger.Debug("Deleting schema from remote registry")
 
 	if !ocpp.IsValidProtocolVersion(req.OcppContext.Version) {
@@ -354,7 +358,7 @@ func (r *SchemaRegistry) DeleteSchema(ctx context.Context, req schema_registry.D
 		return errors.Errorf("action must end with 'Request' or 'Response': %s", req.Action)
 	}
 
-	subject := buildSubjectName(req.OcppContext.Version, req.Action, req.OcppContext.Vendor, req.OcppContext.Model)
+	subject := buildSubjectName(req.OcppContext.Version, schema_registry.SchemaKey(req.Action, req.VendorId, req.MessageId), req.OcppContext.Vendor, req.OcppContext.Model)
 
 	ctx, cancel := context.WithTimeout(ctx, r.config.timeout)
 	defer cancel()
@@ -423,7 +427,7 @@ func (r *SchemaRegistry) fetchAndCacheSchema(ctx context.Context, subject string
 
 func (r *SchemaRegistry) GetSchema(ctx context.Context, req schema_registry.GetSchemaRequest) (*jsonschema.Schema, bool) {
 	logger := r.logger.With(
-		zap.String("ocppVersion", req.OcppContext.Version.String()),
+		zap.String("ocppVersion", req.OcppContext.Version.Name()),
 		zap.String("action", req.Action),
 		zap.String("vendor", req.OcppContext.Vendor),
 		zap.String("model", req.OcppContext.Model),
@@ -443,9 +447,11 @@ func (r *SchemaRegistry) GetSchema(ctx context.Context, req schema_registry.GetS
 	ctx, cancel := context.WithTimeout(ctx, r.config.timeout)
 	defer cancel()
 
+	action := schema_registry.SchemaKey(req.Action, req.VendorId, req.MessageId)
+
 	// When vendor or model is provided, try the specific subject first.
 	if req.OcppContext.Vendor != "" || req.OcppContext.Model != "" {
-		specificSubject := buildSubjectName(req.OcppContext.Version, req.Action, req.OcppContext.Vendor, req.OcppContext.Model)
+		specificSubject := buildSubjectName(req.OcppContext.Version, action, req.OcppContext.Vendor, req.OcppContext.Model)
 		if schema, ok := r.cache.Get(ctx, specificSubject); ok {
 			logger.Debug("Returning vendor/model-specific schema from cache")
 			return schema, true
@@ -457,7 +463,7 @@ func (r *SchemaRegistry) GetSchema(ctx context.Context, req schema_registry.GetS
 		logger.Debug("No vendor/model-specific schema found, falling back to base schema")
 	}
 
-	baseSubject := buildSubjectName(req.OcppContext.Version, req.Action, "", "")
+	baseSubject := buildSubjectName(req.OcppContext.Version, action, "", "")
 	if schema, ok := r.cache.Get(ctx, baseSubject); ok {
 		logger.Debug("Returning base schema from cache")
 		return schema, true
@@ -470,6 +476,56 @@ func (r *SchemaRegistry) GetSchema(ctx context.Context, req schema_registry.GetS
 	return schema, ok
 }
 
+// ListSchemas lists the subjects on the remote registry and returns the actions belonging
+// to the requested OCPP version, including vendor/model-specific subjects when Vendor
+// and/or Model are set.
+func (r *SchemaRegistry) ListSchemas(ctx context.Context, req schema_registry.ListSchemasRequest) ([]string, error) {
+	if !ocpp.IsValidProtocolVersion(req.OcppContext.Version) {
+		return nil, errors.Errorf("invalid OCPP version: %s", req.OcppContext.Version)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.config.timeout)
+	defer cancel()
+
+	resp, err := r.doRequest(ctx, http.MethodGet, "subjects", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list subjects")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status code %d when listing subjects", resp.StatusCode)
+	}
+
+	var subjects []string
+	if err := json.NewDecoder(resp.Body).Decode(&subjects); err != nil {
+		return nil, errors.Wrap(err, "failed to parse subjects response")
+	}
+
+	prefixes := []string{buildSubjectName(req.OcppContext.Version, "", "", "")}
+	if req.OcppContext.Vendor != "" || req.OcppContext.Model != "" {
+		prefixes = append(prefixes, buildSubjectName(req.OcppContext.Version, "", req.OcppContext.Vendor, req.OcppContext.Model))
+	}
+
+	actions := make([]string, 0, len(subjects))
+	for _, subject := range subjects {
+		for _, prefix := range prefixes {
+			if action, found := strings.CutPrefix(subject, prefix); found && action != "" {
+				actions = append(actions, action)
+				break
+			}
+		}
+	}
+
+	slices.Sort(actions)
+	return slices.Compact(actions), nil
+}
+
 func (r *SchemaRegistry) Type() string {
 	return "remote"
 }
+
+// Cache returns the cache holding the compiled schemas fetched from the remote registry.
+func (r *SchemaRegistry) Cache() Cache {
+	return r.cache
+}
@@ -538,6 +538,26 @@ func (s *remoteRegistryIntegrationTestSuite) TestRegisterAndDeleteLifecycle() {
 	s.NoError(err)
 }
 
+func (s *remoteRegistryIntegrationTestSuite) TestDataTransferSchemas() {
+	ctx := context.Background()
+	registry, err := NewRemoteSchemaRegistry(s.registryURL, s.logger, WithTimeout(10*time.Second))
+	s.Require().NoError(err)
+
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+	err = registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel", Schema: json.RawMessage(authorizationReqSchema)})
+	s.Require().NoError(err)
+
+	schema, found := registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel"})
+	s.True(found, "data schema should be retrievable by vendorId and messageId")
+	s.NotNil(schema)
+
+	_, found = registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "GetLevel"})
+	s.False(found, "data schema of another messageId should not be found")
+
+	err = registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{OcppContext: octx, Action: "AuthorizeRequest", VendorId: "com.acme", Schema: json.RawMessage(authorizationReqSchema)})
+	s.ErrorContains(err, "only supported for DataTransfer schemas")
+}
+
 func TestRemoteRegistryIntegration(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
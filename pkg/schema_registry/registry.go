@@ -5,24 +5,42 @@ import (
 	"encoding/json"
 
 	"github.com/kaptinlin/jsonschema"
+	"github.com/pkg/errors"
 
 	"github.com/ChargePi/chargeflow/pkg/ocpp"
 )
 
+const (
+	DataTransferRequest  = "DataTransferRequest"
+	DataTransferResponse = "DataTransferResponse"
+)
+
 type CreateSchemaRequest struct {
 	OcppContext ocpp.OcppContext
 	Action      string
-	Schema      json.RawMessage
+	// VendorId and MessageId register a schema of the data of a DataTransfer with the vendorId and messageId
+	// instead of a schema of the payload, see SchemaKey.
+	VendorId  string
+	MessageId string
+	Schema    json.RawMessage
 }
 
 type DeleteSchemaRequest struct {
 	OcppContext ocpp.OcppContext
 	Action      string
+	VendorId    string
+	MessageId   string
 }
 
 type GetSchemaRequest struct {
 	OcppContext ocpp.OcppContext
 	Action      string
+	VendorId    string
+	MessageId   string
+}
+
+type ListSchemasRequest struct {
+	OcppContext ocpp.OcppContext
 }
 
 type SchemaRegistry interface {
@@ -35,3 +53,45 @@ type SchemaRegistry interface {
 	GetSchema(ctx context.Context, req GetSchemaRequest) (*jsonschema.Schema, bool)
 	Type() string
 }
+
+// SchemaLister is implemented by registries that can enumerate the schemas they hold.
+type SchemaLister interface {
+	// ListSchemas returns the sorted action names (e.g. "BootNotificationRequest") available
+	// for the given OCPP version. When Vendor and/or Model are non-empty, vendor/model-specific
+	// schemas are included alongside the base OCPP spec schemas.
+	ListSchemas(ctx context.Context, req ListSchemasRequest) ([]string, error)
+}
+
+// SchemaKey returns the key a schema is stored under: the action, extended with the vendorId and messageId for
+// schemas of DataTransfer data, e.g. "DataTransferRequest:com.acme:Ping". A data schema without a messageId
+// applies to the DataTransfers of the vendor without a more specific schema.
+func SchemaKey(action, vendorId, messageId string) string {
+	if vendorId == "" {
+		return action
+	}
+
+	key := action + ":" + vendorId
+	if messageId != "" {
+		key += ":" + messageId
+	}
+
+	return key
+}
+
+// ValidateSchemaKey checks that only schemas of DataTransfer data have a vendorId and messageId, and that a
+// messageId comes with a vendorId.
+func ValidateSchemaKey(action, vendorId, messageId string) error {
+	if vendorId == "" && messageId == "" {
+		return nil
+	}
+
+	if action != DataTransferRequest && action != DataTransferResponse {
+		return errors.Errorf("vendorId and messageId are only supported for DataTransfer schemas, not %s", action)
+	}
+
+	if vendorId == "" {
+		return errors.New("vendorId is required when a messageId is set")
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package schemas
+
+type options struct {
+	securityExtension bool
+}
+
+type Option func(*options)
+
+// WithSecurityExtension includes or excludes the schemas of the OCPP 1.6 Security Extension. Included by default.
+func WithSecurityExtension(enabled bool) Option {
+	return func(o *options) {
+		o.securityExtension = enabled
+	}
+}
@@ -0,0 +1,111 @@
+// Package schemas bundles the official OCPP JSON schemas, so they can be registered in any
+// schema_registry.SchemaRegistry without shipping schema files alongside the binary.
+package schemas
+
+import (
+	"context"
+	"embed"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+)
+
+// FS contains the bundled schemas, one directory per OCPP version (see Dirs).
+//
+//go:embed ocpp_15/* ocpp_16/* ocpp_16_security/* ocpp_201/* ocpp_21/*
+var FS embed.FS
+
+// securityExtensionDir holds the schemas of the OCPP 1.6 Security Extension.
+const securityExtensionDir = "ocpp_16_security"
+
+// versionDirs maps each OCPP version to the directories holding its schemas.
+// OCPP 1.6 includes the Security Extension schemas. The OCPP 1.5 schemas are derived from the XSDs of its SOAP services.
+var versionDirs = map[ocpp.Version][]string{
+	ocpp.V15: {"ocpp_15"},
+	ocpp.V16: {"ocpp_16", securityExtensionDir},
+	ocpp.V20: {"ocpp_201"},
+	ocpp.V21: {"ocpp_21"},
+}
+
+// Versions returns the OCPP versions with bundled schemas.
+func Versions() []ocpp.Version {
+	return []ocpp.Version{ocpp.V15, ocpp.V16, ocpp.V20, ocpp.V21}
+}
+
+// Dirs returns the directories in FS holding the schemas for the given OCPP version.
+func Dirs(version ocpp.Version) []string {
+	return versionDirs[version]
+}
+
+// Schema returns the bundled schema of an action of the OCPP version, e.g. "BootNotificationRequest".
+func Schema(version ocpp.Version, action string) ([]byte, bool) {
+	for _, dirPath := range Dirs(version) {
+		schemaData, err := FS.ReadFile(path.Join(dirPath, action+".json"))
+		if err == nil {
+			return schemaData, true
+		}
+	}
+
+	return nil, false
+}
+
+// Register registers all bundled schemas of an OCPP version in the registry. The action
+// name is derived from the file name (e.g. "BootNotificationRequest.json").
+// Versions without bundled schemas are ignored, leaving the registry untouched.
+func Register(ctx context.Context, registry schema_registry.SchemaRegistry, version ocpp.Version, opts ...Option) error {
+	config := options{
+		securityExtension: true,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	for _, dirPath := range Dirs(version) {
+		if dirPath == securityExtensionDir && !config.securityExtension {
+			continue
+		}
+
+		if err := registerDir(ctx, registry, version, dirPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func registerDir(ctx context.Context, registry schema_registry.SchemaRegistry, version ocpp.Version, dirPath string) error {
+	dir, err := FS.ReadDir(dirPath)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read OCPP schemas directory for version: %s", version.Name())
+	}
+
+	for _, file := range dir {
+		if file.IsDir() {
+			continue
+		}
+
+		name := file.Name()
+		schemaData, err := FS.ReadFile(path.Join(dirPath, name))
+		if err != nil {
+			return errors.Wrapf(err, "unable to read OCPP schema file: %s", name)
+		}
+
+		// Note: Assuming that the file name is equivalent to the action name
+		action, _ := strings.CutSuffix(name, ".json")
+		err = registry.RegisterSchema(ctx, schema_registry.CreateSchemaRequest{
+			OcppContext: ocpp.OcppContext{Version: version},
+			Action:      action,
+			Schema:      schemaData,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "unable to register OCPP schema: %s", name)
+		}
+	}
+
+	return nil
+}
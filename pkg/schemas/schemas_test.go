@@ -0,0 +1,88 @@
+package schemas
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry/registries/file_registry"
+)
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name            string
+		version         ocpp.Version
+		opts            []Option
+		expectedActions []string
+		unexpected      []string
+	}{
+		{
+			name:            "OCPP 1.6 includes the Security Extension",
+			version:         ocpp.V16,
+			expectedActions: []string{"BootNotificationRequest", "SignCertificateRequest"},
+			unexpected:      []string{"TransactionEventRequest"},
+		},
+		{
+			name:            "OCPP 1.6 without the Security Extension",
+			version:         ocpp.V16,
+			opts:            []Option{WithSecurityExtension(false)},
+			expectedActions: []string{"BootNotificationRequest"},
+			unexpected:      []string{"SignCertificateRequest", "SecurityEventNotificationResponse"},
+		},
+		{
+			name:            "OCPP 2.0.1",
+			version:         ocpp.V20,
+			expectedActions: []string{"TransactionEventRequest", "CostUpdatedResponse"},
+		},
+		{
+			name:            "OCPP 2.1",
+			version:         ocpp.V21,
+			expectedActions: []string{"UsePriorityChargingRequest"},
+		},
+		{
+			name:            "OCPP 1.5",
+			version:         ocpp.V15,
+			expectedActions: []string{"BootNotificationRequest", "SendLocalListResponse"},
+			unexpected:      []string{"SetChargingProfileRequest"},
+		},
+		{
+			name:    "Version without bundled schemas",
+			version: ocpp.Version("1.2"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			registry := file_registry.NewFileSchemaRegistry(zap.NewNop())
+
+			require.NoError(t, Register(ctx, registry, tt.version, tt.opts...))
+
+			for _, action := range tt.expectedActions {
+				_, found := registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: ocpp.OcppContext{Version: tt.version}, Action: action})
+				require.Truef(t, found, "expected schema for %s", action)
+			}
+
+			for _, action := range tt.unexpected {
+				_, found := registry.GetSchema(ctx, schema_registry.GetSchemaRequest{OcppContext: ocpp.OcppContext{Version: tt.version}, Action: action})
+				require.Falsef(t, found, "unexpected schema for %s", action)
+			}
+		})
+	}
+}
+
+func TestSecurityExtensionActions(t *testing.T) {
+	dir, err := FS.ReadDir(securityExtensionDir)
+	require.NoError(t, err)
+
+	for _, file := range dir {
+		action := strings.TrimSuffix(file.Name(), ".json")
+		action = strings.TrimSuffix(strings.TrimSuffix(action, "Request"), "Response")
+		require.Truef(t, ocpp.IsSecurityExtensionAction(action), "%s is not a Security Extension action", action)
+	}
+}
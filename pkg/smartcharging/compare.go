@@ -0,0 +1,76 @@
+package smartcharging
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// Tolerance is the largest difference between an expected and a reported limit that is not a discrepancy, as
+// limits are rounded to one decimal.
+const Tolerance = 0.1
+
+// Discrepancy is a part of a composite schedule in which the reported limit differs from the expected limit.
+type Discrepancy struct {
+	Start    time.Time
+	End      time.Time
+	Expected float64
+	// Reported limit, nil if the reported schedule has no limit.
+	Reported *float64
+}
+
+// Compare compares the reported composite schedule with the simulated segments. Segments without a limit are not
+// compared, as the charging station may report its own limits for them, e.g. the maximum current of its hardware.
+// Reported limits are converted to the unit of the simulation.
+func Compare(expected []Segment, reported Schedule, unit Unit) []Discrepancy {
+	start, ok := reported.Start()
+	if !ok {
+		return nil
+	}
+
+	var discrepancies []Discrepancy
+	for _, segment := range expected {
+		if segment.Limit == nil {
+			continue
+		}
+
+		// Split the segment at the periods of the reported schedule
+		times := []time.Time{segment.Start}
+		for _, period := range reported.Periods {
+			t := start.Add(seconds(period.StartPeriod))
+			if t.After(segment.Start) && t.Before(segment.End) {
+				times = append(times, t)
+			}
+		}
+
+		if reported.Duration != nil {
+			if t := start.Add(seconds(*reported.Duration)); t.After(segment.Start) && t.Before(segment.End) {
+				times = append(times, t)
+			}
+		}
+
+		times = append(times, segment.End)
+		slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+		for i := 0; i+1 < len(times); i++ {
+			var limit *float64
+			if period, found := reported.periodAt(times[i].Sub(start)); found {
+				converted := Convert(period.Limit, reported.ChargingRateUnit, unit, period.NumberPhases)
+				limit = &converted
+			}
+
+			if limit != nil && math.Abs(*limit-*segment.Limit) <= Tolerance {
+				continue
+			}
+
+			if n := len(discrepancies); n > 0 && discrepancies[n-1].End.Equal(times[i]) &&
+				discrepancies[n-1].Expected == *segment.Limit && sameLimit(discrepancies[n-1].Reported, limit) {
+				discrepancies[n-1].End = times[i+1]
+				continue
+			}
+
+			discrepancies = append(discrepancies, Discrepancy{Start: times[i], End: times[i+1], Expected: *segment.Limit, Reported: limit})
+		}
+	}
+
+	return discrepancies
+}
@@ -0,0 +1,295 @@
+// Package smartcharging models OCPP charging profiles, and simulates the composite schedule of a charging station
+// from the profiles installed on it.
+package smartcharging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Unit is the unit of the limits of a charging schedule.
+type Unit string
+
+const (
+	Ampere = Unit("A")
+	Watt   = Unit("W")
+)
+
+// Purpose is the purpose of a charging profile.
+type Purpose string
+
+const (
+	// ChargePointMaxProfile limits the whole charging station in OCPP 1.6.
+	ChargePointMaxProfile = Purpose("ChargePointMaxProfile")
+	// ChargingStationMaxProfile limits the whole charging station in OCPP 2.0.1 and 2.1.
+	ChargingStationMaxProfile          = Purpose("ChargingStationMaxProfile")
+	ChargingStationExternalConstraints = Purpose("ChargingStationExternalConstraints")
+	TxDefaultProfile                   = Purpose("TxDefaultProfile")
+	TxProfile                          = Purpose("TxProfile")
+)
+
+// Kind is the kind of a charging profile, which determines when its schedule starts.
+type Kind string
+
+const (
+	// Absolute schedules start at their startSchedule.
+	Absolute = Kind("Absolute")
+	// Recurring schedules start at their startSchedule and repeat daily or weekly.
+	Recurring = Kind("Recurring")
+	// Relative schedules start at a point determined by the charging station, e.g. the start of the transaction.
+	Relative = Kind("Relative")
+)
+
+// Recurrency is the recurrency of a recurring charging profile.
+type Recurrency string
+
+const (
+	Daily  = Recurrency("Daily")
+	Weekly = Recurrency("Weekly")
+)
+
+// Interval returns the interval a schedule with the recurrency repeats at, or 0 if the recurrency is unknown.
+func (r Recurrency) Interval() time.Duration {
+	switch r {
+	case Daily:
+		return 24 * time.Hour
+	case Weekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Period is a period of a charging schedule.
+type Period struct {
+	// StartPeriod is the start of the period in seconds from the start of the schedule.
+	StartPeriod int     `json:"startPeriod"`
+	Limit       float64 `json:"limit"`
+	// NumberPhases is the number of phases used for charging, 0 if not set.
+	NumberPhases int `json:"numberPhases,omitempty"`
+}
+
+// Schedule is a charging schedule.
+type Schedule struct {
+	// StartSchedule is the start of an Absolute or Recurring schedule, nil if not set.
+	StartSchedule *time.Time `json:"startSchedule,omitempty"`
+	// ScheduleStart is the start of a composite schedule in OCPP 2.0.1, nil if not set.
+	ScheduleStart *time.Time `json:"scheduleStart,omitempty"`
+	// Duration of the schedule in seconds, nil if the last period lasts indefinitely.
+	Duration         *int     `json:"duration,omitempty"`
+	ChargingRateUnit Unit     `json:"chargingRateUnit"`
+	Periods          []Period `json:"chargingSchedulePeriod"`
+}
+
+// Start returns the start of the schedule, if set.
+func (s Schedule) Start() (time.Time, bool) {
+	switch {
+	case s.StartSchedule != nil:
+		return *s.StartSchedule, true
+	case s.ScheduleStart != nil:
+		return *s.ScheduleStart, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// Profile is a charging profile of OCPP 1.6, 2.0.1 or 2.1.
+type Profile struct {
+	// Id of the profile: the chargingProfileId in OCPP 1.6 and the id in OCPP 2.0.1.
+	Id         int
+	StackLevel int
+	Purpose    Purpose
+	Kind       Kind
+	Recurrency Recurrency
+	// ValidFrom and ValidTo limit the validity of the profile, nil if not set.
+	ValidFrom *time.Time
+	ValidTo   *time.Time
+	// TransactionId is the transaction of a TxProfile, empty if not set.
+	TransactionId string
+	Schedule      Schedule
+}
+
+// profileJSON is the JSON representation of a charging profile in both OCPP 1.6 and 2.0.1.
+type profileJSON struct {
+	ChargingProfileId *int        `json:"chargingProfileId"`
+	Id                *int        `json:"id"`
+	StackLevel        int         `json:"stackLevel"`
+	Purpose           Purpose     `json:"chargingProfilePurpose"`
+	Kind              Kind        `json:"chargingProfileKind"`
+	Recurrency        Recurrency  `json:"recurrencyKind"`
+	ValidFrom         *time.Time  `json:"validFrom"`
+	ValidTo           *time.Time  `json:"validTo"`
+	TransactionId     interface{} `json:"transactionId"`
+	// ChargingSchedule is a single schedule in OCPP 1.6, and a list of schedules in OCPP 2.0.1.
+	ChargingSchedule json.RawMessage `json:"chargingSchedule"`
+}
+
+// ParseProfile parses a charging profile payload of OCPP 1.6, 2.0.1 or 2.1. Of the schedules of an OCPP 2.0.1
+// profile, only the first one is used.
+func ParseProfile(payload map[string]interface{}) (Profile, error) {
+	var raw profileJSON
+	if err := convert(payload, &raw); err != nil {
+		return Profile{}, errors.Wrap(err, "invalid charging profile")
+	}
+
+	profile := Profile{
+		StackLevel: raw.StackLevel,
+		Purpose:    raw.Purpose,
+		Kind:       raw.Kind,
+		Recurrency: raw.Recurrency,
+		ValidFrom:  raw.ValidFrom,
+		ValidTo:    raw.ValidTo,
+	}
+
+	switch {
+	case raw.ChargingProfileId != nil:
+		profile.Id = *raw.ChargingProfileId
+	case raw.Id != nil:
+		profile.Id = *raw.Id
+	}
+
+	if raw.TransactionId != nil {
+		profile.TransactionId = fmt.Sprint(raw.TransactionId)
+	}
+
+	var schedules []Schedule
+	if err := json.Unmarshal(raw.ChargingSchedule, &schedules); err != nil {
+		var schedule Schedule
+		if err := json.Unmarshal(raw.ChargingSchedule, &schedule); err != nil {
+			return Profile{}, errors.Wrap(err, "invalid charging schedule")
+		}
+
+		schedules = []Schedule{schedule}
+	}
+
+	if len(schedules) == 0 {
+		return Profile{}, errors.New("charging profile has no charging schedule")
+	}
+
+	profile.Schedule = schedules[0]
+	return profile, nil
+}
+
+// ParseSchedule parses a charging schedule payload, e.g. the composite schedule of a GetCompositeSchedule response.
+func ParseSchedule(payload map[string]interface{}) (Schedule, error) {
+	var schedule Schedule
+	if err := convert(payload, &schedule); err != nil {
+		return Schedule{}, errors.Wrap(err, "invalid charging schedule")
+	}
+
+	return schedule, nil
+}
+
+// convert converts a payload to the value by marshalling it to JSON.
+func convert(payload map[string]interface{}, value interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, value)
+}
+
+// Limit returns the period of the profile active at the time, if any. Relative profiles start at relativeStart,
+// as do Absolute profiles without a startSchedule.
+func (p Profile) Limit(t, relativeStart time.Time) (Period, bool) {
+	if p.ValidFrom != nil && t.Before(*p.ValidFrom) || p.ValidTo != nil && !t.Before(*p.ValidTo) {
+		return Period{}, false
+	}
+
+	start, ok := p.start(t, relativeStart)
+	if !ok {
+		return Period{}, false
+	}
+
+	return p.Schedule.periodAt(t.Sub(start))
+}
+
+// start returns the start of the schedule (occurrence) active at the time.
+func (p Profile) start(t, relativeStart time.Time) (time.Time, bool) {
+	startSchedule := relativeStart
+	if p.Schedule.StartSchedule != nil && p.Kind != Relative {
+		startSchedule = *p.Schedule.StartSchedule
+	}
+
+	switch p.Kind {
+	case Absolute, Relative:
+		return startSchedule, true
+	case Recurring:
+		interval := p.Recurrency.Interval()
+		if interval == 0 || t.Before(startSchedule) {
+			return time.Time{}, false
+		}
+
+		// The latest occurrence that started at or before the time
+		occurrences := t.Sub(startSchedule) / interval
+		return startSchedule.Add(occurrences * interval), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// periodAt returns the period active at the offset from the start of the schedule, if any.
+func (s Schedule) periodAt(offset time.Duration) (Period, bool) {
+	if offset < 0 || s.Duration != nil && offset >= seconds(*s.Duration) {
+		return Period{}, false
+	}
+
+	var active *Period
+	for i, period := range s.Periods {
+		if seconds(period.StartPeriod) > offset {
+			break
+		}
+
+		active = &s.Periods[i]
+	}
+
+	if active == nil {
+		return Period{}, false
+	}
+
+	return *active, true
+}
+
+// boundaries returns the times within the window at which the limit of the profile may change.
+func (p Profile) boundaries(from, to, relativeStart time.Time) []time.Time {
+	var times []time.Time
+	for _, validity := range []*time.Time{p.ValidFrom, p.ValidTo} {
+		if validity != nil {
+			times = append(times, *validity)
+		}
+	}
+
+	addOccurrence := func(start time.Time) {
+		for _, period := range p.Schedule.Periods {
+			times = append(times, start.Add(seconds(period.StartPeriod)))
+		}
+
+		if p.Schedule.Duration != nil {
+			times = append(times, start.Add(seconds(*p.Schedule.Duration)))
+		}
+	}
+
+	start, ok := p.start(from, relativeStart)
+	if !ok {
+		return times
+	}
+
+	if p.Kind != Recurring {
+		addOccurrence(start)
+		return times
+	}
+
+	for interval := p.Recurrency.Interval(); start.Before(to); start = start.Add(interval) {
+		addOccurrence(start)
+	}
+
+	return times
+}
+
+func seconds(s int) time.Duration {
+	return time.Duration(s) * time.Second
+}
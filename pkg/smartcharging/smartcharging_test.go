@@ -0,0 +1,197 @@
+package smartcharging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type smartChargingTestSuite struct {
+	suite.Suite
+	start time.Time
+}
+
+func (s *smartChargingTestSuite) SetupSuite() {
+	s.start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+// profile returns an Absolute profile starting at the start of the suite.
+func (s *smartChargingTestSuite) profile(id, stackLevel int, purpose Purpose, periods ...Period) Profile {
+	return Profile{
+		Id:         id,
+		StackLevel: stackLevel,
+		Purpose:    purpose,
+		Kind:       Absolute,
+		Schedule:   Schedule{StartSchedule: &s.start, ChargingRateUnit: Ampere, Periods: periods},
+	}
+}
+
+func limit(l float64) *float64 {
+	return &l
+}
+
+func (s *smartChargingTestSuite) TestParseProfile() {
+	profile, err := ParseProfile(map[string]interface{}{
+		"chargingProfileId":      float64(1),
+		"transactionId":          float64(42),
+		"stackLevel":             float64(2),
+		"chargingProfilePurpose": "TxProfile",
+		"chargingProfileKind":    "Recurring",
+		"recurrencyKind":         "Daily",
+		"validTo":                "2024-02-01T00:00:00Z",
+		"chargingSchedule": map[string]interface{}{
+			"duration":         float64(3600),
+			"startSchedule":    "2024-01-01T12:00:00Z",
+			"chargingRateUnit": "A",
+			"chargingSchedulePeriod": []interface{}{
+				map[string]interface{}{"startPeriod": float64(0), "limit": 16.0, "numberPhases": float64(1)},
+			},
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(1, profile.Id)
+	s.Equal("42", profile.TransactionId)
+	s.Equal(Recurring, profile.Kind)
+	s.Equal(Daily, profile.Recurrency)
+	s.Equal(s.start, *profile.Schedule.StartSchedule)
+	s.Equal(3600, *profile.Schedule.Duration)
+	s.Equal([]Period{{StartPeriod: 0, Limit: 16, NumberPhases: 1}}, profile.Schedule.Periods)
+
+	// OCPP 2.0.1 profiles have a list of schedules
+	profile, err = ParseProfile(map[string]interface{}{
+		"id":                     float64(2),
+		"stackLevel":             float64(0),
+		"chargingProfilePurpose": "ChargingStationMaxProfile",
+		"chargingProfileKind":    "Relative",
+		"chargingSchedule": []interface{}{
+			map[string]interface{}{"id": float64(1), "chargingRateUnit": "W", "chargingSchedulePeriod": []interface{}{map[string]interface{}{"startPeriod": float64(0), "limit": 11000.0}}},
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(2, profile.Id)
+	s.Equal(Watt, profile.Schedule.ChargingRateUnit)
+
+	_, err = ParseProfile(map[string]interface{}{"chargingSchedule": []interface{}{}})
+	s.ErrorContains(err, "no charging schedule")
+
+	_, err = ParseProfile(map[string]interface{}{"chargingSchedule": "none"})
+	s.ErrorContains(err, "invalid charging schedule")
+}
+
+func (s *smartChargingTestSuite) TestLimit() {
+	duration := 7200
+	profile := s.profile(1, 0, TxDefaultProfile, Period{StartPeriod: 0, Limit: 32}, Period{StartPeriod: 3600, Limit: 16})
+	profile.Schedule.Duration = &duration
+
+	_, found := profile.Limit(s.start.Add(-time.Second), s.start)
+	s.False(found)
+	period, _ := profile.Limit(s.start, s.start)
+	s.Equal(32.0, period.Limit)
+	period, _ = profile.Limit(s.start.Add(time.Hour), s.start)
+	s.Equal(16.0, period.Limit)
+	_, found = profile.Limit(s.start.Add(2*time.Hour), s.start)
+	s.False(found)
+
+	// Recurring schedules repeat from their start
+	profile.Kind = Recurring
+	profile.Recurrency = Daily
+	period, _ = profile.Limit(s.start.Add(25*time.Hour), s.start)
+	s.Equal(16.0, period.Limit)
+	_, found = profile.Limit(s.start.Add(-23*time.Hour), s.start)
+	s.False(found)
+
+	// Relative schedules start at the relative start
+	profile.Kind = Relative
+	period, _ = profile.Limit(s.start.Add(2*time.Hour), s.start.Add(time.Hour))
+	s.Equal(16.0, period.Limit)
+
+	validTo := s.start.Add(30 * time.Minute)
+	profile.ValidTo = &validTo
+	_, found = profile.Limit(s.start.Add(time.Hour), s.start)
+	s.False(found)
+}
+
+func (s *smartChargingTestSuite) TestComposite() {
+	station := NewStation()
+	station.Set(0, s.profile(1, 0, ChargePointMaxProfile, Period{StartPeriod: 0, Limit: 32}))
+	station.Set(0, s.profile(2, 0, TxDefaultProfile, Period{StartPeriod: 0, Limit: 20}, Period{StartPeriod: 1800, Limit: 40}))
+	station.Set(1, s.profile(3, 1, TxProfile, Period{StartPeriod: 3600, Limit: 10}))
+
+	s.Equal([]Segment{
+		{Start: s.start, End: s.start.Add(30 * time.Minute), Limit: limit(20)},
+		{Start: s.start.Add(30 * time.Minute), End: s.start.Add(time.Hour), Limit: limit(32)},
+		{Start: s.start.Add(time.Hour), End: s.start.Add(2 * time.Hour), Limit: limit(10)},
+	}, station.Composite(1, s.start, 2*time.Hour, Ampere))
+
+	// The whole charging station is only limited by its maximum profiles
+	s.Equal([]Segment{
+		{Start: s.start, End: s.start.Add(time.Hour), Limit: limit(32)},
+	}, station.Composite(0, s.start, time.Hour, Ampere))
+
+	// Higher stack levels take precedence, and replace profiles with the same stack level and purpose
+	station.Set(0, s.profile(4, 1, TxDefaultProfile, Period{StartPeriod: 0, Limit: 25}))
+	station.Set(0, s.profile(5, 0, TxDefaultProfile, Period{StartPeriod: 0, Limit: 6}))
+	s.Equal(4, station.Len())
+	s.Equal([]Segment{
+		{Start: s.start, End: s.start.Add(time.Hour), Limit: limit(25)},
+	}, station.Composite(2, s.start, time.Hour, Ampere))
+
+	// Limits are converted
+	s.Equal([]Segment{
+		{Start: s.start, End: s.start.Add(time.Hour), Limit: limit(25 * 230 * 3)},
+	}, station.Composite(2, s.start, time.Hour, Watt))
+
+	station.EndTransaction(1)
+	purpose := TxDefaultProfile
+	station.Clear(ClearCriteria{Purpose: &purpose})
+	s.Equal(1, station.Len())
+	s.Equal([]Segment{
+		{Start: s.start, End: s.start.Add(time.Hour), Limit: limit(32)},
+	}, station.Composite(1, s.start, time.Hour, Ampere))
+
+	id := 1
+	station.Clear(ClearCriteria{Id: &id})
+	s.Equal([]Segment{
+		{Start: s.start, End: s.start.Add(time.Hour)},
+	}, station.Composite(1, s.start, time.Hour, Ampere))
+}
+
+func (s *smartChargingTestSuite) TestCompare() {
+	expected := []Segment{
+		{Start: s.start, End: s.start.Add(30 * time.Minute), Limit: limit(20)},
+		{Start: s.start.Add(30 * time.Minute), End: s.start.Add(time.Hour)},
+		{Start: s.start.Add(time.Hour), End: s.start.Add(2 * time.Hour), Limit: limit(10)},
+	}
+
+	duration := 5400
+	reported := Schedule{
+		StartSchedule:    &s.start,
+		Duration:         &duration,
+		ChargingRateUnit: Ampere,
+		Periods: []Period{
+			{StartPeriod: 0, Limit: 20.05},
+			{StartPeriod: 1800, Limit: 32},
+			{StartPeriod: 4500, Limit: 16},
+		},
+	}
+
+	s.Equal([]Discrepancy{
+		{Start: s.start.Add(time.Hour), End: s.start.Add(75 * time.Minute), Expected: 10, Reported: limit(32)},
+		{Start: s.start.Add(75 * time.Minute), End: s.start.Add(90 * time.Minute), Expected: 10, Reported: limit(16)},
+		{Start: s.start.Add(90 * time.Minute), End: s.start.Add(2 * time.Hour), Expected: 10},
+	}, Compare(expected, reported, Ampere))
+
+	// Reported limits are converted
+	s.Empty(Compare([]Segment{{Start: s.start, End: s.start.Add(time.Hour), Limit: limit(6900)}}, Schedule{
+		ScheduleStart:    &s.start,
+		ChargingRateUnit: Ampere,
+		Periods:          []Period{{StartPeriod: 0, Limit: 10}},
+	}, Watt))
+
+	s.Empty(Compare(expected, Schedule{}, Ampere))
+}
+
+func TestSmartCharging(t *testing.T) {
+	suite.Run(t, new(smartChargingTestSuite))
+}
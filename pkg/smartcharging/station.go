@@ -0,0 +1,211 @@
+package smartcharging
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// NominalVoltage is the voltage used to convert limits between A and W.
+const NominalVoltage = 230.0
+
+// defaultNumberPhases is the number of phases assumed for periods without numberPhases.
+const defaultNumberPhases = 3
+
+// installedProfile is a charging profile installed on a connector or EVSE. Location 0 is the whole charging station.
+type installedProfile struct {
+	location int
+	profile  Profile
+}
+
+// Station holds the charging profiles installed on a charging station.
+type Station struct {
+	profiles []installedProfile
+}
+
+// NewStation creates a charging station without charging profiles.
+func NewStation() *Station {
+	return &Station{}
+}
+
+// Len returns the number of installed charging profiles.
+func (s *Station) Len() int {
+	return len(s.profiles)
+}
+
+// Set installs a charging profile on the connector or EVSE, replacing the profile with the same id, and the profile
+// with the same stackLevel and purpose on the same connector or EVSE.
+func (s *Station) Set(location int, profile Profile) {
+	s.profiles = slices.DeleteFunc(s.profiles, func(installed installedProfile) bool {
+		if installed.profile.Id == profile.Id {
+			return true
+		}
+
+		return installed.location == location &&
+			installed.profile.StackLevel == profile.StackLevel &&
+			installed.profile.Purpose == profile.Purpose
+	})
+
+	s.profiles = append(s.profiles, installedProfile{location: location, profile: profile})
+}
+
+// ClearCriteria selects the charging profiles to clear. Nil criteria match any profile.
+type ClearCriteria struct {
+	Id         *int
+	Location   *int
+	Purpose    *Purpose
+	StackLevel *int
+}
+
+// Clear removes the charging profiles matching the criteria. A profile id only matches the profile with the id,
+// regardless of the other criteria.
+func (s *Station) Clear(criteria ClearCriteria) {
+	s.profiles = slices.DeleteFunc(s.profiles, func(installed installedProfile) bool {
+		if criteria.Id != nil {
+			return installed.profile.Id == *criteria.Id
+		}
+
+		return (criteria.Location == nil || installed.location == *criteria.Location) &&
+			(criteria.Purpose == nil || installed.profile.Purpose == *criteria.Purpose) &&
+			(criteria.StackLevel == nil || installed.profile.StackLevel == *criteria.StackLevel)
+	})
+}
+
+// EndTransaction removes the TxProfiles of the transaction on the connector or EVSE, as they only apply to the
+// transaction.
+func (s *Station) EndTransaction(location int) {
+	s.profiles = slices.DeleteFunc(s.profiles, func(installed installedProfile) bool {
+		return installed.location == location && installed.profile.Purpose == TxProfile
+	})
+}
+
+// Segment is a part of a composite schedule with a constant limit.
+type Segment struct {
+	Start time.Time
+	End   time.Time
+	// Limit in the unit of the composite schedule, nil if no charging profile limits the segment.
+	Limit *float64
+}
+
+// Composite simulates the composite schedule of the connector or EVSE from the start for the duration, in the unit.
+// Relative profiles are assumed to start at the start of the composite schedule.
+//
+// The limit of a purpose is the limit of its profile with the highest stackLevel that has a limit at the time.
+// The composite limit of a connector or EVSE is the lowest of the limits of the charging station maximum profiles,
+// the external constraints and either the TxProfile or, without one, the TxDefaultProfile, where TxDefaultProfiles
+// of the connector or EVSE take precedence over those of the whole charging station. The composite schedule of the
+// whole charging station (location 0) is limited by its maximum profiles and external constraints only.
+func (s *Station) Composite(location int, start time.Time, duration time.Duration, unit Unit) []Segment {
+	end := start.Add(duration)
+
+	times := []time.Time{start, end}
+	for _, installed := range s.profiles {
+		times = append(times, installed.profile.boundaries(start, end, start)...)
+	}
+
+	times = slices.DeleteFunc(times, func(t time.Time) bool { return t.Before(start) || t.After(end) })
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+	times = slices.CompactFunc(times, func(a, b time.Time) bool { return a.Equal(b) })
+
+	var segments []Segment
+	for i := 0; i+1 < len(times); i++ {
+		limit := s.limitAt(location, times[i], start, unit)
+		if n := len(segments); n > 0 && sameLimit(segments[n-1].Limit, limit) {
+			segments[n-1].End = times[i+1]
+			continue
+		}
+
+		segments = append(segments, Segment{Start: times[i], End: times[i+1], Limit: limit})
+	}
+
+	return segments
+}
+
+// limitAt returns the composite limit of the connector or EVSE at the time, nil if it isn't limited.
+func (s *Station) limitAt(location int, t, relativeStart time.Time, unit Unit) *float64 {
+	limits := []*float64{
+		s.purposeLimit([]int{0}, []Purpose{ChargePointMaxProfile, ChargingStationMaxProfile}, t, relativeStart, unit),
+		s.purposeLimit([]int{location, 0}, []Purpose{ChargingStationExternalConstraints}, t, relativeStart, unit),
+	}
+
+	if location != 0 {
+		transactionLimit := s.purposeLimit([]int{location}, []Purpose{TxProfile}, t, relativeStart, unit)
+		if transactionLimit == nil {
+			transactionLimit = s.purposeLimit([]int{location}, []Purpose{TxDefaultProfile}, t, relativeStart, unit)
+		}
+
+		if transactionLimit == nil {
+			transactionLimit = s.purposeLimit([]int{0}, []Purpose{TxDefaultProfile}, t, relativeStart, unit)
+		}
+
+		limits = append(limits, transactionLimit)
+	}
+
+	var lowest *float64
+	for _, limit := range limits {
+		if limit != nil && (lowest == nil || *limit < *lowest) {
+			lowest = limit
+		}
+	}
+
+	return lowest
+}
+
+// purposeLimit returns the limit of the profile with the highest stackLevel of the purposes on the locations that
+// has a limit at the time, converted to the unit.
+func (s *Station) purposeLimit(locations []int, purposes []Purpose, t, relativeStart time.Time, unit Unit) *float64 {
+	var (
+		best      *float64
+		bestLevel int
+		bestIndex = math.MaxInt
+	)
+
+	for _, installed := range s.profiles {
+		index := slices.Index(locations, installed.location)
+		if index < 0 || !slices.Contains(purposes, installed.profile.Purpose) {
+			continue
+		}
+
+		period, ok := installed.profile.Limit(t, relativeStart)
+		if !ok {
+			continue
+		}
+
+		// Higher stack levels win, and profiles of the first locations win over those with the same level
+		level := installed.profile.StackLevel
+		if best != nil && (level < bestLevel || level == bestLevel && index >= bestIndex) {
+			continue
+		}
+
+		limit := Convert(period.Limit, installed.profile.Schedule.ChargingRateUnit, unit, period.NumberPhases)
+		best, bestLevel, bestIndex = &limit, level, index
+	}
+
+	return best
+}
+
+// Convert converts a limit between A and W, at the NominalVoltage. Periods without a number of phases are assumed
+// to use 3 phases.
+func Convert(limit float64, from, to Unit, numberPhases int) float64 {
+	if from == to || from == "" || to == "" {
+		return limit
+	}
+
+	if numberPhases == 0 {
+		numberPhases = defaultNumberPhases
+	}
+
+	if from == Ampere {
+		return limit * NominalVoltage * float64(numberPhases)
+	}
+
+	return limit / (NominalVoltage * float64(numberPhases))
+}
+
+func sameLimit(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
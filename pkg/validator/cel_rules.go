@@ -0,0 +1,212 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+const (
+	directionRequest  = "request"
+	directionResponse = "response"
+)
+
+// RuleDefinition is a user-defined rule, written as a CEL expression (https://cel.dev) that must evaluate to true
+// for the payload to be valid. Expressions have access to the following variables:
+//   - payload: the payload being checked,
+//   - request: the payload of the request (the payload itself when checking a request),
+//   - response: the payload of the response (empty when checking a request),
+//   - action, version, vendor and model: the action and the OCPP context of the message, with the version
+//     as published, e.g. "2.0.1".
+type RuleDefinition struct {
+	// Name identifies the rule and prefixes its violations, e.g. "heartbeat-interval".
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Expression is the CEL expression, e.g. "response.interval >= 60".
+	Expression string `yaml:"expression"`
+	// Message describes a violation. Defaults to the expression.
+	Message string `yaml:"message"`
+	// Field is the JSON pointer of the field the violation is reported at, e.g. "/interval". Optional.
+	Field string `yaml:"field"`
+	// Direction is the part of the message the rule checks, either "request" (default) or "response".
+	Direction string `yaml:"direction"`
+	// Actions the rule applies to, without the "Request"/"Response" suffix. Rules without actions apply to every action.
+	Actions []string `yaml:"actions"`
+	// Versions the rule applies to. Rules without versions apply to every version.
+	Versions []string `yaml:"versions"`
+	Vendor   string   `yaml:"vendor"`
+	Model    string   `yaml:"model"`
+	Optional bool     `yaml:"optional"`
+}
+
+// RulesFile is the format of a rules file.
+type RulesFile struct {
+	Rules []RuleDefinition `yaml:"rules"`
+}
+
+// LoadRulesFile reads and compiles the rules of a YAML rules file.
+func LoadRulesFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read rules file")
+	}
+
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid rules file %s", path)
+	}
+
+	return rules, nil
+}
+
+// ParseRules compiles the rules of a YAML rules file.
+func ParseRules(data []byte) ([]Rule, error) {
+	var file RulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "failed to parse rules")
+	}
+
+	rules := make([]Rule, 0, len(file.Rules))
+	for i, definition := range file.Rules {
+		if slices.ContainsFunc(rules, func(rule Rule) bool { return rule.ID == definition.Name }) {
+			return nil, errors.Errorf("rule %q is defined more than once", definition.Name)
+		}
+
+		rule, err := NewCELRule(definition)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rule %d", i+1)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// NewCELRule compiles a user-defined rule.
+func NewCELRule(definition RuleDefinition) (Rule, error) {
+	if definition.Name == "" {
+		return Rule{}, errors.New("name is required")
+	}
+
+	suffix := "Request"
+	switch definition.Direction {
+	case "", directionRequest:
+	case directionResponse:
+		suffix = "Response"
+	default:
+		return Rule{}, errors.Errorf("%s: invalid direction %q, must be %q or %q", definition.Name, definition.Direction, directionRequest, directionResponse)
+	}
+
+	if definition.Model != "" && definition.Vendor == "" {
+		return Rule{}, errors.Errorf("%s: vendor must be set if model is set", definition.Name)
+	}
+
+	versions := make([]ocpp.Version, 0, len(definition.Versions))
+	for _, version := range definition.Versions {
+		parsed, err := ocpp.ParseVersion(version)
+		if err != nil {
+			return Rule{}, errors.Wrap(err, definition.Name)
+		}
+		versions = append(versions, parsed)
+	}
+
+	actions := make([]string, 0, len(definition.Actions))
+	for _, action := range definition.Actions {
+		actions = append(actions, action+suffix)
+	}
+
+	program, err := compileExpression(definition.Expression)
+	if err != nil {
+		return Rule{}, errors.Wrap(err, definition.Name)
+	}
+
+	message := definition.Message
+	if message == "" {
+		message = fmt.Sprintf("%s is not satisfied", strings.TrimSpace(definition.Expression))
+	}
+
+	return Rule{
+		ID:          definition.Name,
+		Description: definition.Description,
+		Versions:    versions,
+		Actions:     actions,
+		Vendor:      definition.Vendor,
+		Model:       definition.Model,
+		Optional:    definition.Optional,
+		Check: func(ctx RuleContext, payload map[string]interface{}) []Violation {
+			request, response := payload, map[string]interface{}{}
+			if strings.HasSuffix(ctx.Action, "Response") {
+				request, response = ctx.Request, payload
+				if request == nil {
+					request = map[string]interface{}{}
+				}
+			}
+
+			value, _, err := program.Eval(map[string]interface{}{
+				"payload":  payload,
+				"request":  request,
+				"response": response,
+				"action":   strings.TrimSuffix(strings.TrimSuffix(ctx.Action, "Request"), "Response"),
+				"version":  ctx.Version.Name(),
+				"vendor":   ctx.Vendor,
+				"model":    ctx.Model,
+			})
+
+			switch {
+			case err != nil:
+				return []Violation{{Field: definition.Field, Message: fmt.Sprintf("could not be evaluated: %s", err)}}
+			case value.Value() != true:
+				return []Violation{{Field: definition.Field, Message: message}}
+			default:
+				return nil
+			}
+		},
+	}, nil
+}
+
+// compileExpression compiles a CEL expression that evaluates to a boolean.
+func compileExpression(expression string) (cel.Program, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errors.New("expression is required")
+	}
+
+	env, err := cel.NewEnv(
+		cel.Variable("payload", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("response", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("action", cel.StringType),
+		cel.Variable("version", cel.StringType),
+		cel.Variable("vendor", cel.StringType),
+		cel.Variable("model", cel.StringType),
+		cel.CrossTypeNumericComparisons(true),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CEL environment")
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrap(issues.Err(), "invalid expression")
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, errors.Errorf("expression must evaluate to a bool, not %s", ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid expression")
+	}
+
+	return program, nil
+}
@@ -0,0 +1,202 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kaptinlin/jsonschema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	mock_schema_registry "github.com/ChargePi/chargeflow/gen/mocks/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+const rulesFile = `
+rules:
+  - name: acme.heartbeat-interval
+    description: The heartbeat interval must be between 60 and 900 seconds
+    direction: response
+    actions: [BootNotification]
+    versions: ["1.6"]
+    expression: response.interval >= 60 && response.interval <= 900
+    message: interval must be between 60 and 900 seconds
+    field: /interval
+  - name: acme.firmware-version
+    actions: [BootNotification]
+    vendor: ACME
+    model: Wallbox
+    expression: has(request.firmwareVersion) && request.firmwareVersion.startsWith(vendor.lowerAscii())
+`
+
+type celRulesTestSuite struct {
+	suite.Suite
+}
+
+func (s *celRulesTestSuite) TestParseRules() {
+	rules, err := ParseRules([]byte(rulesFile))
+	s.Require().NoError(err)
+	s.Require().Len(rules, 2)
+
+	s.Equal("acme.heartbeat-interval", rules[0].ID)
+	s.Equal("The heartbeat interval must be between 60 and 900 seconds", rules[0].Description)
+	s.Equal([]ocpp.Version{ocpp.V16}, rules[0].Versions)
+	s.Equal([]string{"BootNotificationResponse"}, rules[0].Actions)
+
+	s.Equal([]string{"BootNotificationRequest"}, rules[1].Actions)
+	s.Equal("ACME", rules[1].Vendor)
+	s.Equal("Wallbox", rules[1].Model)
+	s.Empty(rules[1].Versions)
+}
+
+func (s *celRulesTestSuite) TestParseRules_Invalid() {
+	tests := []struct {
+		name  string
+		rules string
+		err   string
+	}{
+		{
+			name:  "Missing name",
+			rules: "rules:\n  - expression: 'true'",
+			err:   "name is required",
+		},
+		{
+			name:  "Missing expression",
+			rules: "rules:\n  - name: a",
+			err:   "expression is required",
+		},
+		{
+			name:  "Invalid expression",
+			rules: "rules:\n  - name: a\n    expression: payload.",
+			err:   "invalid expression",
+		},
+		{
+			name:  "Not a boolean",
+			rules: "rules:\n  - name: a\n    expression: action",
+			err:   "expression must evaluate to a bool",
+		},
+		{
+			name:  "Invalid direction",
+			rules: "rules:\n  - name: a\n    direction: both\n    expression: 'true'",
+			err:   `invalid direction "both"`,
+		},
+		{
+			name:  "Invalid version",
+			rules: "rules:\n  - name: a\n    versions: ['3.0']\n    expression: 'true'",
+			err:   `invalid OCPP version "3.0"`,
+		},
+		{
+			name:  "Model without vendor",
+			rules: "rules:\n  - name: a\n    model: Wallbox\n    expression: 'true'",
+			err:   "vendor must be set if model is set",
+		},
+		{
+			name:  "Duplicate name",
+			rules: "rules:\n  - name: a\n    expression: 'true'\n  - name: a\n    expression: 'true'",
+			err:   `rule "a" is defined more than once`,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := ParseRules([]byte(tt.rules))
+			s.ErrorContains(err, tt.err)
+		})
+	}
+}
+
+func (s *celRulesTestSuite) TestCheck() {
+	rules, err := ParseRules([]byte(rulesFile))
+	s.Require().NoError(err)
+
+	interval, firmware := rules[0], rules[1]
+
+	ctx := RuleContext{OcppContext: ocpp.OcppContext{Version: ocpp.V16}, Action: "BootNotificationResponse"}
+	s.Empty(interval.Check(ctx, map[string]interface{}{"interval": float64(300)}))
+	s.Equal([]Violation{{Field: "/interval", Message: "interval must be between 60 and 900 seconds"}},
+		interval.Check(ctx, map[string]interface{}{"interval": float64(30)}))
+	s.Equal([]Violation{{Field: "/interval", Message: "could not be evaluated: no such key: interval"}},
+		interval.Check(ctx, map[string]interface{}{}))
+
+	ctx = RuleContext{OcppContext: ocpp.OcppContext{Version: ocpp.V16, Vendor: "ACME", Model: "Wallbox"}, Action: "BootNotificationRequest"}
+	s.Empty(firmware.Check(ctx, map[string]interface{}{"firmwareVersion": "acme-1.2.3"}))
+	s.Equal([]Violation{{Message: "has(request.firmwareVersion) && request.firmwareVersion.startsWith(vendor.lowerAscii()) is not satisfied"}},
+		firmware.Check(ctx, map[string]interface{}{}))
+}
+
+func (s *celRulesTestSuite) TestCheck_RequestResponsePair() {
+	rule, err := NewCELRule(RuleDefinition{
+		Name:       "acme.echo",
+		Direction:  "response",
+		Actions:    []string{"DataTransfer"},
+		Expression: "response.data == request.data",
+	})
+	s.Require().NoError(err)
+
+	ctx := RuleContext{Action: "DataTransferResponse", Request: map[string]interface{}{"data": "ping"}}
+	s.Empty(rule.Check(ctx, map[string]interface{}{"data": "ping"}))
+	s.Len(rule.Check(ctx, map[string]interface{}{"data": "pong"}), 1)
+}
+
+func (s *celRulesTestSuite) TestValidateResponse() {
+	rules, err := ParseRules([]byte(`
+rules:
+  - name: acme.accepted-interval
+    direction: response
+    actions: [BootNotification]
+    vendor: ACME
+    expression: response.status != 'Accepted' || response.interval >= request.minInterval
+    message: the interval is shorter than requested
+`))
+	s.Require().NoError(err)
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(s.T())
+	schemaFromCompiler, err := jsonschema.NewCompiler().Compile(permissiveSchema)
+	s.Require().NoError(err)
+	registry.EXPECT().GetSchema(mock.Anything, mock.Anything).Return(schemaFromCompiler, true)
+
+	uniqueId := uuid.NewString()
+	request := &ocpp.Call{
+		MessageTypeId: ocpp.CALL,
+		UniqueId:      uniqueId,
+		Action:        "BootNotification",
+		Payload:       map[string]interface{}{"minInterval": float64(300)},
+	}
+	response := &ocpp.CallResult{
+		MessageTypeId: ocpp.CALL_RESULT,
+		UniqueId:      uniqueId,
+		Action:        "BootNotification",
+		Payload:       map[string]interface{}{"status": "Accepted", "interval": float64(60)},
+	}
+
+	validator := NewValidator(zap.NewNop(), registry, WithRules(rules...))
+
+	result, err := validator.ValidateResponse(ocpp.OcppContext{Version: ocpp.V16, Vendor: "ACME"}, request, response)
+	s.Require().NoError(err)
+	s.Equal([]string{"acme.accepted-interval: the interval is shorter than requested"}, result.Errors())
+	s.Equal([]ErrorCategory{CategoryRule}, result.ErrorCategories())
+
+	// Rules scoped to a vendor are not checked for other vendors
+	result, err = validator.ValidateResponse(ocpp.OcppContext{Version: ocpp.V16, Vendor: "Other"}, request, response)
+	s.Require().NoError(err)
+	s.True(result.IsValid())
+}
+
+func (s *celRulesTestSuite) TestLoadRulesFile() {
+	path := filepath.Join(s.T().TempDir(), "rules.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte(rulesFile), 0o600))
+
+	rules, err := LoadRulesFile(path)
+	s.Require().NoError(err)
+	s.Len(rules, 2)
+
+	_, err = LoadRulesFile(filepath.Join(s.T().TempDir(), "missing.yaml"))
+	s.ErrorContains(err, "failed to read rules file")
+}
+
+func TestCELRules(t *testing.T) {
+	suite.Run(t, new(celRulesTestSuite))
+}
@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"time"
+
+	"github.com/ChargePi/chargeflow/pkg/certificates"
+	"github.com/ChargePi/chargeflow/pkg/configkeys"
+	"github.com/ChargePi/chargeflow/pkg/devicemodel"
+)
+
+type options struct {
+	rules         []Rule
+	enabledRules  []string
+	disabledRules []string
+	now           func() time.Time
+	// securityExtension enables the actions of the OCPP 1.6 Security Extension.
+	securityExtension bool
+	deviceModel       *devicemodel.Catalog
+	configurationKeys *configkeys.Catalog
+	certificates      *certificates.Pool
+}
+
+type Option func(*options)
+
+// WithRules adds custom rules. A custom rule with the ID of a built-in rule replaces the built-in rule.
+func WithRules(rules ...Rule) Option {
+	return func(o *options) {
+		o.rules = append(o.rules, rules...)
+	}
+}
+
+// WithEnabledRules enables optional rules by their IDs.
+func WithEnabledRules(ids ...string) Option {
+	return func(o *options) {
+		o.enabledRules = append(o.enabledRules, ids...)
+	}
+}
+
+// WithDisabledRules disables rules by their IDs.
+func WithDisabledRules(ids ...string) Option {
+	return func(o *options) {
+		o.disabledRules = append(o.disabledRules, ids...)
+	}
+}
+
+// WithClock sets the clock rules compare timestamps against. Defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithSecurityExtension enables or disables the OCPP 1.6 Security Extension. When disabled, the actions it adds
+// (e.g. SignCertificate) are reported as unsupported in OCPP 1.6. Enabled by default.
+func WithSecurityExtension(enabled bool) Option {
+	return func(o *options) {
+		o.securityExtension = enabled
+	}
+}
+
+// WithDeviceModel sets the catalog of OCPP 2.0.1 components and variables the device model rules check against.
+// Defaults to the standardized components and variables, see devicemodel.Standard.
+func WithDeviceModel(catalog *devicemodel.Catalog) Option {
+	return func(o *options) {
+		o.deviceModel = catalog
+	}
+}
+
+// WithConfigurationKeys sets the catalog of OCPP 1.6 configuration keys the configuration rules check against.
+// Defaults to the standard configuration keys, see configkeys.Standard.
+func WithConfigurationKeys(catalog *configkeys.Catalog) Option {
+	return func(o *options) {
+		o.configurationKeys = catalog
+	}
+}
+
+// WithCertificates sets the known certificates, e.g. the root and sub-CA certificates of the CSMS, that the hash data
+// of certificates in messages is checked against.
+func WithCertificates(pool *certificates.Pool) Option {
+	return func(o *options) {
+		o.certificates = pool
+	}
+}
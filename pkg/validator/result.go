@@ -1,14 +1,41 @@
 package validator
 
+import "slices"
+
 const (
 	payloadEmptyErr  = "payload is empty"
 	actionEmptyErr   = "action is empty"
 	uniqueIdEmptyErr = "unique id is empty"
 )
 
+// ErrorCategory classifies validation errors, e.g. for metrics. Errors from validating the payload against
+// the schema are categorized by the failing JSON schema keyword (e.g. "required" or "additionalProperties").
+type ErrorCategory string
+
+const (
+	// CategoryMessage is used for structural errors of the message, e.g. a missing unique ID or action.
+	CategoryMessage = ErrorCategory("message")
+	// CategoryMessageType is used for message types that are not supported by the OCPP version.
+	CategoryMessageType = ErrorCategory("message_type")
+	// CategoryUnsupportedAction is used for actions of optional features that are disabled, e.g. the OCPP 1.6 Security Extension.
+	CategoryUnsupportedAction = ErrorCategory("unsupported_action")
+	// CategoryErrorCode is used for CALLERRORs with an invalid error code.
+	CategoryErrorCode = ErrorCategory("error_code")
+	// CategoryOCMF is used for invalid OCMF signed meter values.
+	CategoryOCMF = ErrorCategory("ocmf")
+	// CategoryDataTransfer is used for DataTransfer data that doesn't match the schema of its vendorId and messageId.
+	CategoryDataTransfer = ErrorCategory("data_transfer")
+	// CategoryRule is used for violations of semantic rules, see Rule.
+	CategoryRule = ErrorCategory("rule")
+	// CategoryOther is used for errors added without a category.
+	CategoryOther = ErrorCategory("other")
+)
+
 type ValidationResult struct {
-	isValid bool
-	errors  []string
+	isValid    bool
+	errors     []string
+	categories []ErrorCategory
+	fields     []string
 }
 
 // NewValidationResult creates a new ValidationResult with the given validity and errors.
@@ -20,8 +47,14 @@ func NewValidationResult() *ValidationResult {
 }
 
 func (v *ValidationResult) AddError(err string) {
+	v.AddCategorizedError(CategoryOther, err)
+}
+
+// AddCategorizedError adds an error along with its category.
+func (v *ValidationResult) AddCategorizedError(category ErrorCategory, err string) {
 	v.isValid = false
 	v.errors = append(v.errors, err)
+	v.categories = append(v.categories, category)
 }
 
 // IsValid returns true if the validation result is valid, false otherwise.
@@ -33,3 +66,35 @@ func (v *ValidationResult) IsValid() bool {
 func (v *ValidationResult) Errors() []string {
 	return v.errors
 }
+
+// ErrorCategories returns the category of each error, in the same order as Errors.
+func (v *ValidationResult) ErrorCategories() []ErrorCategory {
+	return v.categories
+}
+
+// AddFields records the fields of the payload that failed validation, as JSON pointers (e.g. "/idTag").
+func (v *ValidationResult) AddFields(fields ...string) {
+	for _, field := range fields {
+		if !slices.Contains(v.fields, field) {
+			v.fields = append(v.fields, field)
+		}
+	}
+}
+
+// Fields returns the fields of the payload that failed validation, as JSON pointers.
+func (v *ValidationResult) Fields() []string {
+	return v.fields
+}
+
+// Merge adds the errors and failing fields of another result.
+func (v *ValidationResult) Merge(other *ValidationResult) {
+	if other == nil {
+		return
+	}
+
+	for i, err := range other.errors {
+		v.AddCategorizedError(other.categories[i], err)
+	}
+
+	v.AddFields(other.fields...)
+}
@@ -33,6 +33,19 @@ func (s *resultTestSuite) TestErrors() {
 	s.NotContains(errors, "third error")
 }
 
+func (s *resultTestSuite) TestErrorCategories() {
+	result := NewValidationResult()
+	s.Empty(result.ErrorCategories())
+
+	result.AddError("first error")
+	result.AddCategorizedError(CategoryMessage, "second error")
+	result.AddCategorizedError(ErrorCategory("required"), "third error")
+
+	s.False(result.IsValid())
+	s.Equal([]ErrorCategory{CategoryOther, CategoryMessage, ErrorCategory("required")}, result.ErrorCategories())
+	s.Len(result.Errors(), 3)
+}
+
 func TestResult(t *testing.T) {
 	suite.Run(t, new(resultTestSuite))
 }
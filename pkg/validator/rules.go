@@ -0,0 +1,367 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/certificates"
+	"github.com/ChargePi/chargeflow/pkg/configkeys"
+	"github.com/ChargePi/chargeflow/pkg/devicemodel"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+)
+
+// Rule is a semantic (business) rule of the OCPP specification that can't be expressed with JSON schema,
+// e.g. that the periods of a charging schedule must start at 0 and be in increasing order. Rules are
+// checked after the payload has been validated against the schema.
+type Rule struct {
+	// ID identifies the rule, e.g. "ocpp16.stack-level", so it can be enabled or disabled.
+	ID string
+	// Description describes the rule in a single sentence.
+	Description string
+	// Versions are the OCPP versions the rule applies to.
+	Versions []ocpp.Version
+	// Actions the rule applies to, suffixed with either "Request" or "Response", e.g. "SetChargingProfileRequest".
+	// Rules without actions apply to every action.
+	Actions []string
+	// Vendor and Model limit the rule to the messages of a specific charging station. Empty values match any.
+	Vendor string
+	Model  string
+	// Optional rules are only checked when enabled explicitly, e.g. because they depend on the time of validation.
+	Optional bool
+	// Check returns the violations of the rule, if any.
+	Check func(ctx RuleContext, payload map[string]interface{}) []Violation
+	// CheckSequence checks rules spanning multiple messages, e.g. that an accepted remote start is followed by a
+	// transaction. It's called with the exchanges of a file in the order they occurred, and returns the violations
+	// per message ID, which are reported on the response of the exchange. Actions don't apply to sequence checks.
+	CheckSequence func(ctx RuleContext, exchanges []Exchange) map[string][]Violation
+}
+
+// RuleContext is the context a rule is checked in.
+type RuleContext struct {
+	ocpp.OcppContext
+	// Action of the payload, suffixed with either "Request" or "Response".
+	Action string
+	// Now is the time of validation.
+	Now time.Time
+	// Request is the payload of the request a response answers. Nil when checking a request, or when the
+	// request is unknown.
+	Request map[string]interface{}
+	// DeviceModel is the catalog of known OCPP 2.0.1 components and variables. Nil if unknown.
+	DeviceModel *devicemodel.Catalog
+	// ConfigurationKeys is the catalog of known OCPP 1.6 configuration keys. Nil if unknown.
+	ConfigurationKeys *configkeys.Catalog
+	// Certificates are the known certificates the hash data of messages is checked against. Nil if unknown.
+	Certificates *certificates.Pool
+}
+
+// Violation is a single violation of a rule.
+type Violation struct {
+	// Field is the JSON pointer of the violating field, e.g. "/chargingProfile/stackLevel". Optional.
+	Field string
+	// Message describes the violation.
+	Message string
+}
+
+// appliesTo returns true if the rule should be checked for the action in the OCPP context.
+func (r Rule) appliesTo(octx ocpp.OcppContext, action string) bool {
+	if !r.appliesToContext(octx) {
+		return false
+	}
+
+	return len(r.Actions) == 0 || slices.Contains(r.Actions, action)
+}
+
+// appliesToContext returns true if the rule should be checked for messages in the OCPP context.
+func (r Rule) appliesToContext(octx ocpp.OcppContext) bool {
+	if len(r.Versions) > 0 && !slices.Contains(r.Versions, octx.Version) {
+		return false
+	}
+
+	return (r.Vendor == "" || r.Vendor == octx.Vendor) && (r.Model == "" || r.Model == octx.Model)
+}
+
+// BuiltinRules returns the rules bundled with chargeflow, for all OCPP versions.
+func BuiltinRules() []Rule {
+	return slices.Concat(ocpp16Rules(), ocpp201Rules())
+}
+
+// resolveRules returns the rules to check: the built-in rules, replaced by custom rules with the same ID,
+// without the disabled rules. Optional rules are only returned if they were enabled.
+func resolveRules(config options) []Rule {
+	rules := BuiltinRules()
+	for _, custom := range config.rules {
+		index := slices.IndexFunc(rules, func(rule Rule) bool { return rule.ID == custom.ID })
+		if index >= 0 {
+			rules[index] = custom
+		} else {
+			rules = append(rules, custom)
+		}
+	}
+
+	return slices.DeleteFunc(rules, func(rule Rule) bool {
+		if slices.Contains(config.disabledRules, rule.ID) {
+			return true
+		}
+
+		return rule.Optional && !slices.Contains(config.enabledRules, rule.ID)
+	})
+}
+
+// Rules returns the rules checked by the validator.
+func (v *Validator) Rules() []Rule {
+	return slices.Clone(v.rules)
+}
+
+// checkRules checks the payload against the rules applying to the action. The request payload is only
+// set when checking a response.
+func (v *Validator) checkRules(octx ocpp.OcppContext, action string, payload, request interface{}, validationResults *ValidationResult) {
+	object, ok := payload.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	requestObject, _ := request.(map[string]interface{})
+	ctx := RuleContext{
+		OcppContext:       octx,
+		Action:            action,
+		Now:               v.now(),
+		Request:           requestObject,
+		DeviceModel:       v.deviceModel,
+		ConfigurationKeys: v.configurationKeys,
+		Certificates:      v.certificates,
+	}
+	for _, rule := range v.rules {
+		if rule.Check == nil || !rule.appliesTo(octx, action) {
+			continue
+		}
+
+		for _, violation := range rule.Check(ctx, object) {
+			v.logger.Debug("Rule violated", zap.String("rule", rule.ID), zap.String("field", violation.Field))
+			validationResults.AddCategorizedError(CategoryRule, fmt.Sprintf("%s: %s", rule.ID, violation.Message))
+			if violation.Field != "" {
+				validationResults.AddFields(violation.Field)
+			}
+		}
+	}
+}
+
+// walkObjects calls fn for every object in the value, including the value itself, along with its JSON pointer.
+func walkObjects(value interface{}, path string, fn func(path string, object map[string]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fn(path, v)
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			walkObjects(v[key], path+"/"+escapePointer(key), fn)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walkObjects(item, path+"/"+strconv.Itoa(i), fn)
+		}
+	}
+}
+
+// escapePointer escapes a key for use in a JSON pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// number returns the numeric value of a field. Fields that are missing or not numbers are reported as not found.
+func number(object map[string]interface{}, key string) (float64, bool) {
+	switch v := object[key].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// str returns the string value of a field, or an empty string if it's missing or not a string.
+func str(object map[string]interface{}, key string) string {
+	s, _ := object[key].(string)
+	return s
+}
+
+// child returns the object value of a field, or nil if it's missing or not an object.
+func child(object map[string]interface{}, key string) map[string]interface{} {
+	c, _ := object[key].(map[string]interface{})
+	return c
+}
+
+// children returns the objects of an array field, skipping items that are not objects.
+func children(object map[string]interface{}, key string) []map[string]interface{} {
+	items, _ := object[key].([]interface{})
+
+	objects := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if c, ok := item.(map[string]interface{}); ok {
+			objects = append(objects, c)
+		}
+	}
+
+	return objects
+}
+
+// strs returns the strings of an array field, skipping items that are not strings.
+func strs(object map[string]interface{}, key string) []string {
+	items, _ := object[key].([]interface{})
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+
+	return values
+}
+
+// checkChargingSchedulePeriods checks that the periods of every charging schedule in the payload start at 0
+// and are in strictly increasing order.
+func checkChargingSchedulePeriods(_ RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	walkObjects(payload, "", func(path string, object map[string]interface{}) {
+		periods, ok := object["chargingSchedulePeriod"].([]interface{})
+		if !ok {
+			return
+		}
+
+		previous := 0.0
+		for i, item := range periods {
+			period, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			startPeriod, found := number(period, "startPeriod")
+			if !found {
+				continue
+			}
+
+			field := fmt.Sprintf("%s/chargingSchedulePeriod/%d/startPeriod", path, i)
+			switch {
+			case i == 0 && startPeriod != 0:
+				violations = append(violations, Violation{
+					Field:   field,
+					Message: fmt.Sprintf("the first charging schedule period must start at 0, but starts at %g", startPeriod),
+				})
+			case i > 0 && startPeriod <= previous:
+				violations = append(violations, Violation{
+					Field:   field,
+					Message: fmt.Sprintf("charging schedule period %d starts at %g, which is not after the previous period (%g)", i, startPeriod, previous),
+				})
+			}
+
+			previous = startPeriod
+		}
+	})
+
+	return violations
+}
+
+// checkStackLevel checks that no stack level in the payload is negative, as 0 is the lowest level.
+func checkStackLevel(_ RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	walkObjects(payload, "", func(path string, object map[string]interface{}) {
+		if stackLevel, found := number(object, "stackLevel"); found && stackLevel < 0 {
+			violations = append(violations, Violation{
+				Field:   path + "/stackLevel",
+				Message: fmt.Sprintf("stackLevel must not be negative, got %g", stackLevel),
+			})
+		}
+	})
+
+	return violations
+}
+
+// checkTransactionIdPurpose checks that only charging profiles with the TxProfile purpose refer to a transaction.
+func checkTransactionIdPurpose(_ RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	walkObjects(payload, "", func(path string, object map[string]interface{}) {
+		purpose := str(object, "chargingProfilePurpose")
+		if _, found := object["transactionId"]; found && purpose != "" && purpose != txProfile {
+			violations = append(violations, Violation{
+				Field:   path + "/transactionId",
+				Message: fmt.Sprintf("transactionId may only be set for a %s, not for a %s", txProfile, purpose),
+			})
+		}
+	})
+
+	return violations
+}
+
+// checkStartProfilePurpose checks that the charging profile of a remote start has the TxProfile purpose.
+func checkStartProfilePurpose(_ RuleContext, payload map[string]interface{}) []Violation {
+	profile := child(payload, "chargingProfile")
+	if purpose := str(profile, "chargingProfilePurpose"); purpose != "" && purpose != txProfile {
+		return []Violation{{
+			Field:   "/chargingProfile/chargingProfilePurpose",
+			Message: fmt.Sprintf("the charging profile of a remote start must be a %s, not a %s", txProfile, purpose),
+		}}
+	}
+
+	return nil
+}
+
+// checkProfileLocation returns a check that charging profiles with the maximum profile purpose (e.g.
+// ChargePointMaxProfile) are set on the whole charging station (0) and TxProfiles on a specific connector or EVSE.
+func checkProfileLocation(locationKey, profileKey, maxProfile string) func(RuleContext, map[string]interface{}) []Violation {
+	return func(_ RuleContext, payload map[string]interface{}) []Violation {
+		location, found := number(payload, locationKey)
+		if !found {
+			return nil
+		}
+
+		switch purpose := str(child(payload, profileKey), "chargingProfilePurpose"); {
+		case purpose == maxProfile && location != 0:
+			return []Violation{{
+				Field:   "/" + locationKey,
+				Message: fmt.Sprintf("a %s can only be set with %s 0, got %g", maxProfile, locationKey, location),
+			}}
+		case purpose == txProfile && location == 0:
+			return []Violation{{
+				Field:   "/" + locationKey,
+				Message: fmt.Sprintf("a %s can't be set with %s 0", txProfile, locationKey),
+			}}
+		default:
+			return nil
+		}
+	}
+}
+
+// checkAuthorizationExpiry returns a check that accepted authorizations (e.g. idTagInfo) don't expire before
+// the time of validation.
+func checkAuthorizationExpiry(infoKey, expiryKey string) func(RuleContext, map[string]interface{}) []Violation {
+	return func(ctx RuleContext, payload map[string]interface{}) []Violation {
+		var violations []Violation
+		walkObjects(payload, "", func(path string, object map[string]interface{}) {
+			info := child(object, infoKey)
+			if str(info, "status") != accepted {
+				return
+			}
+
+			expiry, err := time.Parse(time.RFC3339, str(info, expiryKey))
+			if err != nil || expiry.After(ctx.Now) {
+				return
+			}
+
+			violations = append(violations, Violation{
+				Field:   path + "/" + infoKey + "/" + expiryKey,
+				Message: fmt.Sprintf("%s is Accepted, but expired at %s", infoKey, expiry.Format(time.RFC3339)),
+			})
+		})
+
+		return violations
+	}
+}
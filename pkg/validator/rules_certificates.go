@@ -0,0 +1,163 @@
+package validator
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ChargePi/chargeflow/pkg/certificates"
+)
+
+// csrField is the field of a SignCertificate request holding the PEM encoded certificate signing request.
+const csrField = "csr"
+
+// certificateFields are the fields of the requests holding PEM encoded X.509 certificates.
+var certificateFields = map[string]string{
+	"CertificateSignedRequest":  "certificateChain",
+	"InstallCertificateRequest": "certificate",
+	"AuthorizeRequest":          "certificate",
+}
+
+// exiFields are the fields of the ISO 15118 messages holding base64 encoded EXI streams.
+var exiFields = map[string]string{
+	"Get15118EVCertificateRequest":  "exiRequest",
+	"Get15118EVCertificateResponse": "exiResponse",
+}
+
+// exiCookie is the optional cookie an EXI stream starts with.
+const exiCookie = "$EXI"
+
+// messageCertificates returns the certificates of the payload, or nil if it has none or they can't be parsed.
+func messageCertificates(action string, payload map[string]interface{}) []*x509.Certificate {
+	value := str(payload, certificateFields[action])
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := certificates.Parse(value)
+	if err != nil {
+		return nil
+	}
+
+	return parsed
+}
+
+// checkCertificates checks that certificates are valid PEM or DER encoded X.509 certificates, that every certificate
+// of a chain is issued by the next one, and that CSRs are valid and signed.
+func checkCertificates(ctx RuleContext, payload map[string]interface{}) []Violation {
+	if csr := str(payload, csrField); csr != "" {
+		if _, err := certificates.ParseCSR(csr); err != nil {
+			return []Violation{{Field: "/" + csrField, Message: fmt.Sprintf("csr is not a valid certificate signing request: %v", err)}}
+		}
+
+		return nil
+	}
+
+	field := certificateFields[ctx.Action]
+	value := str(payload, field)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := certificates.Parse(value)
+	if err != nil {
+		return []Violation{{Field: "/" + field, Message: fmt.Sprintf("%s is not a valid X.509 certificate: %v", field, err)}}
+	}
+
+	var violations []Violation
+	for i := 0; i+1 < len(parsed); i++ {
+		if err := parsed[i].CheckSignatureFrom(parsed[i+1]); err != nil {
+			violations = append(violations, Violation{
+				Field:   "/" + field,
+				Message: fmt.Sprintf("certificate %d of the %s is not issued by certificate %d: %v", i+1, field, i+2, err),
+			})
+		}
+	}
+
+	return violations
+}
+
+// checkCertificateExpiry checks that the certificates are valid at the time of validation.
+func checkCertificateExpiry(ctx RuleContext, payload map[string]interface{}) []Violation {
+	field := certificateFields[ctx.Action]
+
+	var violations []Violation
+	for _, certificate := range messageCertificates(ctx.Action, payload) {
+		switch {
+		case ctx.Now.After(certificate.NotAfter):
+			violations = append(violations, Violation{
+				Field:   "/" + field,
+				Message: fmt.Sprintf("certificate %q expired at %s", certificate.Subject, certificate.NotAfter.UTC().Format(time.RFC3339)),
+			})
+		case ctx.Now.Before(certificate.NotBefore):
+			violations = append(violations, Violation{
+				Field:   "/" + field,
+				Message: fmt.Sprintf("certificate %q is not valid before %s", certificate.Subject, certificate.NotBefore.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+
+	return violations
+}
+
+// checkCertificateHashData checks the format of the certificate hash data, and checks it against the known
+// certificates and the certificates of the message.
+func checkCertificateHashData(ctx RuleContext, payload map[string]interface{}) []Violation {
+	var known []*x509.Certificate
+	if ctx.Certificates != nil {
+		known = ctx.Certificates.Certificates()
+	}
+
+	pool := certificates.NewPool(slices.Concat(known, messageCertificates(ctx.Action, payload))...)
+
+	var violations []Violation
+	walkObjects(payload, "", func(path string, object map[string]interface{}) {
+		if _, ok := object["issuerNameHash"]; !ok {
+			return
+		}
+
+		hashData := certificates.HashData{
+			HashAlgorithm:  certificates.HashAlgorithm(str(object, "hashAlgorithm")),
+			IssuerNameHash: str(object, "issuerNameHash"),
+			IssuerKeyHash:  str(object, "issuerKeyHash"),
+			SerialNumber:   str(object, "serialNumber"),
+		}
+
+		err := hashData.Validate()
+		if err == nil {
+			err = pool.Check(hashData)
+		}
+
+		if err != nil {
+			violations = append(violations, Violation{Field: path, Message: err.Error()})
+		}
+	})
+
+	return violations
+}
+
+// checkExiStreams checks that the EXI streams of ISO 15118 messages are base64 encoded and start with an EXI header.
+// The streams themselves are not decoded.
+func checkExiStreams(ctx RuleContext, payload map[string]interface{}) []Violation {
+	field := exiFields[ctx.Action]
+	value := str(payload, field)
+	if value == "" {
+		return nil
+	}
+
+	stream, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return []Violation{{Field: "/" + field, Message: fmt.Sprintf("%s is not base64 encoded", field)}}
+	}
+
+	// The header starts with the distinguishing bits 10, after the optional cookie
+	header := bytes.TrimPrefix(stream, []byte(exiCookie))
+	if len(header) == 0 || header[0]&0xC0 != 0x80 {
+		return []Violation{{Field: "/" + field, Message: fmt.Sprintf("%s is not an EXI stream", field)}}
+	}
+
+	return nil
+}
@@ -0,0 +1,384 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/ChargePi/chargeflow/pkg/configkeys"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/smartcharging"
+)
+
+const (
+	txProfile = "TxProfile"
+	accepted  = "Accepted"
+)
+
+// ocpp16Rules returns the built-in rules for OCPP 1.6.
+func ocpp16Rules() []Rule {
+	versions := []ocpp.Version{ocpp.V16}
+
+	return []Rule{
+		{
+			ID:          "ocpp16.charging-schedule-periods",
+			Description: "The periods of a charging schedule start at 0 and are in strictly increasing order.",
+			Versions:    versions,
+			Check:       checkChargingSchedulePeriods,
+		},
+		{
+			ID:          "ocpp16.stack-level",
+			Description: "Stack levels of charging profiles are not negative.",
+			Versions:    versions,
+			Check:       checkStackLevel,
+		},
+		{
+			ID:          "ocpp16.charging-profile-connector",
+			Description: "A ChargePointMaxProfile is only set on connector 0 and a TxProfile only on a specific connector.",
+			Versions:    versions,
+			Actions:     []string{"SetChargingProfileRequest"},
+			Check:       checkProfileLocation("connectorId", "csChargingProfiles", "ChargePointMaxProfile"),
+		},
+		{
+			ID:          "ocpp16.charging-profile-transaction-id",
+			Description: "Only charging profiles with the TxProfile purpose have a transactionId.",
+			Versions:    versions,
+			Check:       checkTransactionIdPurpose,
+		},
+		{
+			ID:          "ocpp16.remote-start-profile-purpose",
+			Description: "The charging profile of a RemoteStartTransaction is a TxProfile.",
+			Versions:    versions,
+			Actions:     []string{"RemoteStartTransactionRequest"},
+			Check:       checkStartProfilePurpose,
+		},
+		{
+			ID:          "ocpp16.charging-profile-validity",
+			Description: "The validFrom of a charging profile is before its validTo.",
+			Versions:    versions,
+			Check:       checkChargingProfileValidity,
+		},
+		{
+			ID:          "ocpp16.charging-profile-schedule",
+			Description: "Charging schedules match the kind and recurrency of their profile, and their periods start within their duration.",
+			Versions:    versions,
+			Check:       checkChargingProfileSchedule(false),
+		},
+		{
+			ID:          "ocpp16.charging-schedule-phases",
+			Description: "The numberPhases of charging schedule periods is between 1 and 3.",
+			Versions:    versions,
+			Check:       checkChargingSchedulePhases,
+		},
+		{
+			ID:          "ocpp16.connector-id",
+			Description: "Transactions are started on a specific connector, not on connector 0.",
+			Versions:    versions,
+			Actions:     []string{"StartTransactionRequest", "RemoteStartTransactionRequest"},
+			Check:       checkTransactionConnectorId,
+		},
+		{
+			ID:          "ocpp16.id-tag-expiry",
+			Description: "An accepted idTagInfo has not expired at the time of validation.",
+			Versions:    versions,
+			Optional:    true,
+			Check:       checkAuthorizationExpiry("idTagInfo", "expiryDate"),
+		},
+		{
+			ID:          "ocpp16.get-configuration-keys",
+			Description: "A GetConfiguration response only returns the requested keys, and every requested key is either returned or unknown.",
+			Versions:    versions,
+			Actions:     []string{"GetConfigurationResponse"},
+			Check:       checkGetConfigurationKeys,
+		},
+		{
+			ID:          "ocpp16.configuration-values",
+			Description: "Configuration values in ChangeConfiguration and GetConfiguration match the type and range of the key.",
+			Versions:    versions,
+			Actions:     []string{"ChangeConfigurationRequest", "GetConfigurationResponse"},
+			Check:       checkConfigurationValues,
+		},
+		{
+			ID:          "ocpp16.configuration-read-only",
+			Description: "Read-only configuration keys are not changed or reported as writable, and write-only keys are not reported.",
+			Versions:    versions,
+			Actions:     []string{"ChangeConfigurationRequest", "GetConfigurationResponse"},
+			Check:       checkConfigurationAccess,
+		},
+		{
+			ID:          "ocpp16.configuration-keys",
+			Description: "ChangeConfiguration and GetConfiguration only refer to configuration keys of the catalog.",
+			Versions:    versions,
+			Actions:     []string{"ChangeConfigurationRequest", "GetConfigurationRequest", "GetConfigurationResponse"},
+			Optional:    true,
+			Check:       checkConfigurationKeys,
+		},
+		{
+			ID:          "ocpp16.certificates",
+			Description: "Certificates and CSRs of the Security Extension are valid X.509 structures, and chains are in order.",
+			Versions:    versions,
+			Actions:     []string{"SignCertificateRequest", "CertificateSignedRequest", "InstallCertificateRequest"},
+			Optional:    true,
+			Check:       checkCertificates,
+		},
+		{
+			ID:          "ocpp16.certificate-expiry",
+			Description: "Certificates of the Security Extension are valid at the time of validation.",
+			Versions:    versions,
+			Actions:     []string{"CertificateSignedRequest", "InstallCertificateRequest"},
+			Optional:    true,
+			Check:       checkCertificateExpiry,
+		},
+		{
+			ID:          "ocpp16.certificate-hash-data",
+			Description: "Certificate hash data is well-formed and matches the known certificates.",
+			Versions:    versions,
+			Actions:     []string{"DeleteCertificateRequest", "GetInstalledCertificateIdsResponse"},
+			Optional:    true,
+			Check:       checkCertificateHashData,
+		},
+		{
+			ID:            "ocpp16.remote-start-transaction",
+			Description:   "An accepted RemoteStartTransaction is followed by a StartTransaction for the idTag.",
+			Versions:      versions,
+			CheckSequence: checkFollowedBy("RemoteStartTransaction", expectStartTransaction),
+		},
+		{
+			ID:            "ocpp16.composite-schedule",
+			Description:   "GetCompositeSchedule responses match the composite schedule simulated from the charging profiles that were set.",
+			Versions:      versions,
+			CheckSequence: checkCompositeSchedule(smartChargingMessages16),
+		},
+		{
+			ID:            "ocpp16.trigger-message",
+			Description:   "An accepted TriggerMessage is followed by the requested message.",
+			Versions:      versions,
+			CheckSequence: checkFollowedBy("TriggerMessage", expectTriggeredMessage16),
+		},
+	}
+}
+
+// checkGetConfigurationKeys checks that the response to a GetConfiguration for specific keys only contains those keys,
+// and that all of them are either returned or reported as unknown.
+func checkGetConfigurationKeys(ctx RuleContext, payload map[string]interface{}) []Violation {
+	requested := strs(ctx.Request, "key")
+	if len(requested) == 0 {
+		// All keys were requested
+		return nil
+	}
+
+	var violations []Violation
+	var returned []string
+	for i, configurationKey := range children(payload, "configurationKey") {
+		key := str(configurationKey, "key")
+		returned = append(returned, key)
+		if !slices.Contains(requested, key) {
+			violations = append(violations, Violation{
+				Field:   fmt.Sprintf("/configurationKey/%d/key", i),
+				Message: fmt.Sprintf("configuration key %s was not requested", key),
+			})
+		}
+	}
+
+	for i, key := range strs(payload, "unknownKey") {
+		returned = append(returned, key)
+		if !slices.Contains(requested, key) {
+			violations = append(violations, Violation{
+				Field:   fmt.Sprintf("/unknownKey/%d", i),
+				Message: fmt.Sprintf("unknown key %s was not requested", key),
+			})
+		}
+	}
+
+	for _, key := range requested {
+		if !slices.Contains(returned, key) {
+			violations = append(violations, Violation{
+				Message: fmt.Sprintf("requested key %s is neither in configurationKey nor in unknownKey", key),
+			})
+		}
+	}
+
+	return violations
+}
+
+// configurationKey returns the key of the catalog for the charging station.
+func configurationKey(ctx RuleContext, name string) (configkeys.Key, bool) {
+	if ctx.ConfigurationKeys == nil {
+		return configkeys.Key{}, false
+	}
+
+	return ctx.ConfigurationKeys.Key(ctx.Vendor, ctx.Model, name)
+}
+
+// checkConfigurationValues checks that changed and reported configuration values match the key of the catalog.
+func checkConfigurationValues(ctx RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	check := func(field string, object map[string]interface{}) {
+		value, found := object["value"].(string)
+		if !found {
+			return
+		}
+
+		name := str(object, "key")
+		if key, found := configurationKey(ctx, name); found {
+			if err := key.CheckValue(value); err != nil {
+				violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("%s: %s", name, err)})
+			}
+		}
+	}
+
+	if ctx.Action == "ChangeConfigurationRequest" {
+		check("/value", payload)
+	}
+
+	for i, configurationKey := range children(payload, "configurationKey") {
+		check(fmt.Sprintf("/configurationKey/%d/value", i), configurationKey)
+	}
+
+	return violations
+}
+
+// checkConfigurationAccess checks that read-only keys are not changed or reported as writable, and that write-only
+// keys are not reported.
+func checkConfigurationAccess(ctx RuleContext, payload map[string]interface{}) []Violation {
+	if ctx.Action == "ChangeConfigurationRequest" {
+		name := str(payload, "key")
+		if key, found := configurationKey(ctx, name); found && key.ReadOnly {
+			return []Violation{{Field: "/key", Message: fmt.Sprintf("configuration key %s is read-only", name)}}
+		}
+
+		return nil
+	}
+
+	var violations []Violation
+	for i, reported := range children(payload, "configurationKey") {
+		name := str(reported, "key")
+		key, found := configurationKey(ctx, name)
+		if !found {
+			continue
+		}
+
+		switch {
+		case key.WriteOnly:
+			violations = append(violations, Violation{
+				Field:   fmt.Sprintf("/configurationKey/%d", i),
+				Message: fmt.Sprintf("configuration key %s is write-only and must not be reported", name),
+			})
+		case key.ReadOnly && reported["readonly"] == false:
+			violations = append(violations, Violation{
+				Field:   fmt.Sprintf("/configurationKey/%d/readonly", i),
+				Message: fmt.Sprintf("configuration key %s is read-only, but reported as writable", name),
+			})
+		}
+	}
+
+	return violations
+}
+
+// checkConfigurationKeys checks that the configuration keys are in the catalog.
+func checkConfigurationKeys(ctx RuleContext, payload map[string]interface{}) []Violation {
+	if ctx.ConfigurationKeys == nil {
+		return nil
+	}
+
+	var violations []Violation
+	check := func(field, name string) {
+		if _, found := configurationKey(ctx, name); !found {
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("unknown configuration key %s", name)})
+		}
+	}
+
+	switch ctx.Action {
+	case "ChangeConfigurationRequest":
+		check("/key", str(payload, "key"))
+	case "GetConfigurationRequest":
+		for i, name := range strs(payload, "key") {
+			check(fmt.Sprintf("/key/%d", i), name)
+		}
+	case "GetConfigurationResponse":
+		for i, reported := range children(payload, "configurationKey") {
+			check(fmt.Sprintf("/configurationKey/%d/key", i), str(reported, "key"))
+		}
+	}
+
+	return violations
+}
+
+// expectStartTransaction expects a StartTransaction for the idTag (and connector, if any) of a RemoteStartTransaction.
+func expectStartTransaction(exchange Exchange) (followUp, bool) {
+	idTag := str(exchange.Request, "idTag")
+
+	return followUp{
+		action:      "StartTransaction",
+		description: fmt.Sprintf("StartTransaction for idTag %s", idTag),
+		matches: func(request map[string]interface{}) bool {
+			return str(request, "idTag") == idTag && sameNumber(exchange.Request, request, "connectorId")
+		},
+	}, true
+}
+
+// expectTriggeredMessage16 expects the message requested by a TriggerMessage, for the connector if one was given
+// and the message refers to a connector.
+func expectTriggeredMessage16(exchange Exchange) (followUp, bool) {
+	requestedMessage := str(exchange.Request, "requestedMessage")
+	if requestedMessage == "" {
+		return followUp{}, false
+	}
+
+	return followUp{
+		action:      requestedMessage,
+		description: requestedMessage,
+		matches: func(request map[string]interface{}) bool {
+			if _, found := request["connectorId"]; !found {
+				return true
+			}
+
+			return sameNumber(exchange.Request, request, "connectorId")
+		},
+	}, true
+}
+
+// checkTransactionConnectorId checks that a transaction is started on a specific connector.
+func checkTransactionConnectorId(_ RuleContext, payload map[string]interface{}) []Violation {
+	if connectorId, found := number(payload, "connectorId"); found && connectorId <= 0 {
+		return []Violation{{
+			Field:   "/connectorId",
+			Message: fmt.Sprintf("transactions must be started on a connector with an ID greater than 0, got %g", connectorId),
+		}}
+	}
+
+	return nil
+}
+
+// smartChargingMessages16 are the fields of the OCPP 1.6 smart charging messages.
+var smartChargingMessages16 = smartChargingMessages{
+	locationKey: "connectorId",
+	profileKey:  "csChargingProfiles",
+	remoteStart: "RemoteStartTransaction",
+	clearCriteria: func(request map[string]interface{}) smartcharging.ClearCriteria {
+		return smartcharging.ClearCriteria{
+			Id:         optionalInt(request, "id"),
+			Location:   optionalInt(request, "connectorId"),
+			Purpose:    optionalPurpose(request, "chargingProfilePurpose"),
+			StackLevel: optionalInt(request, "stackLevel"),
+		}
+	},
+	trackTransaction: func(exchange Exchange, transactions map[string]int) (int, bool) {
+		switch exchange.Action {
+		case "StartTransaction":
+			transactionId, found := number(exchange.Response, "transactionId")
+			connectorId, connectorFound := number(exchange.Request, "connectorId")
+			if found && connectorFound {
+				transactions[strconv.Itoa(int(transactionId))] = int(connectorId)
+			}
+		case "StopTransaction":
+			if transactionId, found := number(exchange.Request, "transactionId"); found {
+				connectorId, known := transactions[strconv.Itoa(int(transactionId))]
+				return connectorId, known
+			}
+		}
+
+		return 0, false
+	},
+	compositeSchedule: "chargingSchedule",
+}
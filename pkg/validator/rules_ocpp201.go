@@ -0,0 +1,591 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ChargePi/chargeflow/pkg/devicemodel"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/smartcharging"
+)
+
+const (
+	eventTypeStarted = "Started"
+	eventTypeEnded   = "Ended"
+)
+
+// triggeredActions are the actions of the messages a TriggerMessage can request, if they differ from the
+// requested message.
+var triggeredActions = map[string]string{
+	"SignChargingStationCertificate": "SignCertificate",
+	"SignV2GCertificate":             "SignCertificate",
+	"SignCombinedCertificate":        "SignCertificate",
+}
+
+// triggerReasonsNotStarting are the trigger reasons that can't start a transaction.
+var triggerReasonsNotStarting = []string{
+	"AbnormalCondition",
+	"ChargingRateChanged",
+	"Deauthorized",
+	"EnergyLimitReached",
+	"EVCommunicationLost",
+	"EVConnectTimeout",
+	"EVDeparted",
+	"MeterValueClock",
+	"MeterValuePeriodic",
+	"RemoteStop",
+	"ResetCommand",
+	"StopAuthorized",
+	"TimeLimitReached",
+	"TxResumed",
+	"UnlockCommand",
+}
+
+// triggerReasonsNotEnding are the trigger reasons that can't end a transaction.
+var triggerReasonsNotEnding = []string{
+	"Authorized",
+	"CablePluggedIn",
+	"ChargingRateChanged",
+	"MeterValueClock",
+	"MeterValuePeriodic",
+	"RemoteStart",
+	"TxResumed",
+}
+
+// deviceModelData are the fields of the device model requests holding the component variables they refer to.
+var deviceModelData = map[string]string{
+	"NotifyReportRequest":          "reportData",
+	"GetVariablesRequest":          "getVariableData",
+	"SetVariablesRequest":          "setVariableData",
+	"SetVariableMonitoringRequest": "setMonitoringData",
+}
+
+// ocpp201Rules returns the built-in rules for OCPP 2.0.1, which apply to OCPP 2.1 as well.
+func ocpp201Rules() []Rule {
+	versions := []ocpp.Version{ocpp.V20, ocpp.V21}
+
+	return []Rule{
+		{
+			ID:          "ocpp201.transaction-event-trigger-reason",
+			Description: "The triggerReason of a TransactionEvent can start or end a transaction, matching its eventType.",
+			Versions:    versions,
+			Actions:     []string{"TransactionEventRequest"},
+			Check:       checkTransactionEventTriggerReason,
+		},
+		{
+			ID:          "ocpp201.transaction-event-stopped-reason",
+			Description: "Only a TransactionEvent with the Ended eventType has a stoppedReason.",
+			Versions:    versions,
+			Actions:     []string{"TransactionEventRequest"},
+			Check:       checkTransactionEventStoppedReason,
+		},
+		{
+			ID:          "ocpp201.charging-schedule-periods",
+			Description: "The periods of a charging schedule start at 0 and are in strictly increasing order.",
+			Versions:    versions,
+			Check:       checkChargingSchedulePeriods,
+		},
+		{
+			ID:          "ocpp201.stack-level",
+			Description: "Stack levels of charging profiles are not negative.",
+			Versions:    versions,
+			Check:       checkStackLevel,
+		},
+		{
+			ID:          "ocpp201.charging-profile-evse",
+			Description: "A ChargingStationMaxProfile is only set on EVSE 0 and a TxProfile only on a specific EVSE.",
+			Versions:    versions,
+			Actions:     []string{"SetChargingProfileRequest"},
+			Check:       checkProfileLocation("evseId", "chargingProfile", "ChargingStationMaxProfile"),
+		},
+		{
+			ID:          "ocpp201.charging-profile-transaction-id",
+			Description: "Only charging profiles with the TxProfile purpose have a transactionId, and a TxProfile that is set has one.",
+			Versions:    versions,
+			Check:       checkTransactionIdPurpose201,
+		},
+		{
+			ID:          "ocpp201.request-start-profile-purpose",
+			Description: "The charging profile of a RequestStartTransaction is a TxProfile.",
+			Versions:    versions,
+			Actions:     []string{"RequestStartTransactionRequest"},
+			Check:       checkStartProfilePurpose,
+		},
+		{
+			ID:          "ocpp201.charging-profile-validity",
+			Description: "The validFrom of a charging profile is before its validTo.",
+			Versions:    versions,
+			Check:       checkChargingProfileValidity,
+		},
+		{
+			ID:          "ocpp201.charging-profile-schedule",
+			Description: "Charging schedules match the kind and recurrency of their profile, and their periods start within their duration.",
+			Versions:    versions,
+			Check:       checkChargingProfileSchedule(true),
+		},
+		{
+			ID:          "ocpp201.charging-schedule-phases",
+			Description: "The numberPhases of charging schedule periods is between 1 and 3, a phaseToUse is only set for a single phase, and per-phase values match the chargingRateUnit and numberPhases.",
+			Versions:    versions,
+			Check:       checkChargingSchedulePhases,
+		},
+		{
+			ID:          "ocpp201.evse-connector",
+			Description: "Connectors are numbered from 1 and belong to a specific EVSE, not to EVSE 0.",
+			Versions:    versions,
+			Check:       checkEvseConnector,
+		},
+		{
+			ID:          "ocpp201.id-token-cache-expiry",
+			Description: "The cacheExpiryDateTime of an accepted idTokenInfo is in the future at the time of validation.",
+			Versions:    versions,
+			Optional:    true,
+			Check:       checkAuthorizationExpiry("idTokenInfo", "cacheExpiryDateTime"),
+		},
+		{
+			ID:          "ocpp201.set-variables-results",
+			Description: "A SetVariables response has exactly one setVariableResult for every requested variable.",
+			Versions:    versions,
+			Actions:     []string{"SetVariablesResponse"},
+			Check:       checkVariableResults("setVariableData", "setVariableResult"),
+		},
+		{
+			ID:          "ocpp201.get-variables-results",
+			Description: "A GetVariables response has exactly one getVariableResult for every requested variable.",
+			Versions:    versions,
+			Actions:     []string{"GetVariablesResponse"},
+			Check:       checkVariableResults("getVariableData", "getVariableResult"),
+		},
+		{
+			ID:          "ocpp201.device-model-values",
+			Description: "Variable values in NotifyReport, SetVariables and GetVariables match the dataType and valuesList of the variable.",
+			Versions:    versions,
+			Actions:     []string{"NotifyReportRequest", "SetVariablesRequest", "GetVariablesResponse"},
+			Check:       checkDeviceModelValues,
+		},
+		{
+			ID:          "ocpp201.device-model-names",
+			Description: "Device model messages only refer to components and variables of the device model catalog.",
+			Versions:    versions,
+			Actions:     []string{"NotifyReportRequest", "GetVariablesRequest", "SetVariablesRequest", "SetVariableMonitoringRequest"},
+			Optional:    true,
+			Check:       checkDeviceModelNames,
+		},
+		{
+			ID:          "ocpp201.certificates",
+			Description: "Certificates and CSRs are valid X.509 structures, and chains are in order.",
+			Versions:    versions,
+			Actions:     []string{"SignCertificateRequest", "CertificateSignedRequest", "InstallCertificateRequest", "AuthorizeRequest"},
+			Optional:    true,
+			Check:       checkCertificates,
+		},
+		{
+			ID:          "ocpp201.certificate-expiry",
+			Description: "Certificates are valid at the time of validation.",
+			Versions:    versions,
+			Actions:     []string{"CertificateSignedRequest", "InstallCertificateRequest", "AuthorizeRequest"},
+			Optional:    true,
+			Check:       checkCertificateExpiry,
+		},
+		{
+			ID:          "ocpp201.certificate-hash-data",
+			Description: "Certificate hash data is well-formed and matches the known certificates and the certificate of an Authorize.",
+			Versions:    versions,
+			Actions: []string{
+				"AuthorizeRequest",
+				"CustomerInformationRequest",
+				"DeleteCertificateRequest",
+				"GetCertificateChainStatusRequest",
+				"GetCertificateStatusRequest",
+				"GetInstalledCertificateIdsResponse",
+				"SignCertificateRequest",
+			},
+			Optional: true,
+			Check:    checkCertificateHashData,
+		},
+		{
+			ID:          "ocpp201.iso15118-exi",
+			Description: "The EXI streams of Get15118EVCertificate are base64 encoded and start with an EXI header.",
+			Versions:    versions,
+			Actions:     []string{"Get15118EVCertificateRequest", "Get15118EVCertificateResponse"},
+			Optional:    true,
+			Check:       checkExiStreams,
+		},
+		{
+			ID:            "ocpp201.request-start-transaction",
+			Description:   "An accepted RequestStartTransaction is followed by a TransactionEvent with its remoteStartId.",
+			Versions:      versions,
+			CheckSequence: checkFollowedBy("RequestStartTransaction", expectTransactionEvent),
+		},
+		{
+			ID:            "ocpp201.composite-schedule",
+			Description:   "GetCompositeSchedule responses match the composite schedule simulated from the charging profiles that were set.",
+			Versions:      versions,
+			CheckSequence: checkCompositeSchedule(smartChargingMessages201),
+		},
+		{
+			ID:            "ocpp201.trigger-message",
+			Description:   "An accepted TriggerMessage is followed by the requested message.",
+			Versions:      versions,
+			CheckSequence: checkFollowedBy("TriggerMessage", expectTriggeredMessage201),
+		},
+	}
+}
+
+// checkTransactionEventTriggerReason checks that the trigger reason can start or end a transaction.
+func checkTransactionEventTriggerReason(_ RuleContext, payload map[string]interface{}) []Violation {
+	eventType := str(payload, "eventType")
+	triggerReason := str(payload, "triggerReason")
+
+	var notAllowed []string
+	switch eventType {
+	case eventTypeStarted:
+		notAllowed = triggerReasonsNotStarting
+	case eventTypeEnded:
+		notAllowed = triggerReasonsNotEnding
+	}
+
+	if slices.Contains(notAllowed, triggerReason) {
+		return []Violation{{
+			Field:   "/triggerReason",
+			Message: fmt.Sprintf("triggerReason %s is not allowed for a TransactionEvent with eventType %s", triggerReason, eventType),
+		}}
+	}
+
+	return nil
+}
+
+// checkTransactionEventStoppedReason checks that only the last TransactionEvent of a transaction has a stoppedReason.
+func checkTransactionEventStoppedReason(_ RuleContext, payload map[string]interface{}) []Violation {
+	eventType := str(payload, "eventType")
+	if _, found := child(payload, "transactionInfo")["stoppedReason"]; found && eventType != eventTypeEnded {
+		return []Violation{{
+			Field:   "/transactionInfo/stoppedReason",
+			Message: fmt.Sprintf("stoppedReason may only be set when the eventType is %s, not %s", eventTypeEnded, eventType),
+		}}
+	}
+
+	return nil
+}
+
+// checkTransactionIdPurpose201 extends checkTransactionIdPurpose: a TxProfile that is set must refer to a transaction.
+func checkTransactionIdPurpose201(ctx RuleContext, payload map[string]interface{}) []Violation {
+	violations := checkTransactionIdPurpose(ctx, payload)
+
+	profile := child(payload, "chargingProfile")
+	if _, found := profile["transactionId"]; ctx.Action == "SetChargingProfileRequest" && str(profile, "chargingProfilePurpose") == txProfile && !found {
+		violations = append(violations, Violation{
+			Field:   "/chargingProfile/transactionId",
+			Message: fmt.Sprintf("a %s must have a transactionId", txProfile),
+		})
+	}
+
+	return violations
+}
+
+// checkEvseConnector checks that connectors are numbered from 1 and that they belong to a specific EVSE.
+func checkEvseConnector(_ RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	check := func(path, evseKey string, object map[string]interface{}) {
+		connectorId, found := number(object, "connectorId")
+		if !found {
+			return
+		}
+
+		if connectorId <= 0 {
+			violations = append(violations, Violation{
+				Field:   path + "/connectorId",
+				Message: fmt.Sprintf("connectors are numbered from 1, got connectorId %g", connectorId),
+			})
+		}
+
+		if evseId, found := number(object, evseKey); found && evseId <= 0 {
+			violations = append(violations, Violation{
+				Field:   path + "/" + evseKey,
+				Message: fmt.Sprintf("connector %g must belong to an EVSE with an ID greater than 0, got %g", connectorId, evseId),
+			})
+		}
+	}
+
+	walkObjects(payload, "", func(path string, object map[string]interface{}) {
+		// Either an EVSE object ({"id": 1, "connectorId": 1}) or an object with evseId and connectorId fields
+		if strings.HasSuffix(path, "/evse") {
+			check(path, "id", object)
+		} else {
+			check(path, "evseId", object)
+		}
+	})
+
+	return violations
+}
+
+// checkVariableResults returns a check that a response has exactly one result for every requested variable, e.g.
+// a setVariableResult for every setVariableData of the SetVariables request.
+func checkVariableResults(dataKey, resultKey string) func(RuleContext, map[string]interface{}) []Violation {
+	return func(ctx RuleContext, payload map[string]interface{}) []Violation {
+		if ctx.Request == nil {
+			return nil
+		}
+
+		var remaining []string
+		for _, data := range children(ctx.Request, dataKey) {
+			remaining = append(remaining, variableReference(data))
+		}
+
+		var violations []Violation
+		for i, result := range children(payload, resultKey) {
+			reference := variableReference(result)
+			index := slices.Index(remaining, reference)
+			if index < 0 {
+				violations = append(violations, Violation{
+					Field:   fmt.Sprintf("/%s/%d", resultKey, i),
+					Message: fmt.Sprintf("%s for %s does not match a requested variable", resultKey, reference),
+				})
+				continue
+			}
+
+			remaining = slices.Delete(remaining, index, index+1)
+		}
+
+		for _, reference := range remaining {
+			violations = append(violations, Violation{
+				Field:   "/" + resultKey,
+				Message: fmt.Sprintf("%s is missing for the requested variable %s", resultKey, reference),
+			})
+		}
+
+		return violations
+	}
+}
+
+// variableReference returns a readable reference to the component variable and attribute of a variable request
+// or result, e.g. "EVSE[1]/Power/Actual".
+func variableReference(object map[string]interface{}) string {
+	attributeType := str(object, "attributeType")
+	if attributeType == "" {
+		attributeType = "Actual"
+	}
+
+	return variableName(object) + "/" + attributeType
+}
+
+// variableName returns a readable reference to the component variable of a device model object, e.g. "EVSE[1]/Power".
+func variableName(object map[string]interface{}) string {
+	var b strings.Builder
+
+	component := child(object, "component")
+	b.WriteString(str(component, "name"))
+	if instance := str(component, "instance"); instance != "" {
+		b.WriteString("." + instance)
+	}
+
+	if evse := child(component, "evse"); evse != nil {
+		id, _ := number(evse, "id")
+		_, _ = fmt.Fprintf(&b, "[%g", id)
+		if connectorId, found := number(evse, "connectorId"); found {
+			_, _ = fmt.Fprintf(&b, ",%g", connectorId)
+		}
+		b.WriteString("]")
+	}
+
+	variable := child(object, "variable")
+	b.WriteString("/" + str(variable, "name"))
+	if instance := str(variable, "instance"); instance != "" {
+		b.WriteString("." + instance)
+	}
+
+	return b.String()
+}
+
+// checkDeviceModelValues checks that variable values match their data type and values list. Reported values are
+// checked against the variableCharacteristics of the report, other values against the device model catalog.
+func checkDeviceModelValues(ctx RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	check := func(field string, object map[string]interface{}, value string, characteristics map[string]interface{}) {
+		var err error
+		if characteristics != nil {
+			err = devicemodel.CheckValue(
+				devicemodel.DataType(str(characteristics, "dataType")),
+				splitList(str(characteristics, "valuesList")),
+				devicemodel.Limits{Min: optionalNumber(characteristics, "minLimit"), Max: optionalNumber(characteristics, "maxLimit")},
+				value,
+			)
+		} else if variable, found := catalogVariable(ctx, object); found {
+			err = variable.CheckValue(value)
+		}
+
+		if err != nil {
+			violations = append(violations, Violation{Field: field, Message: fmt.Sprintf("%s: %s", variableName(object), err)})
+		}
+	}
+
+	switch ctx.Action {
+	case "NotifyReportRequest":
+		for i, data := range children(payload, "reportData") {
+			for j, attribute := range children(data, "variableAttribute") {
+				if value, found := attribute["value"].(string); found {
+					check(fmt.Sprintf("/reportData/%d/variableAttribute/%d/value", i, j), data, value, child(data, "variableCharacteristics"))
+				}
+			}
+		}
+	case "SetVariablesRequest":
+		for i, data := range children(payload, "setVariableData") {
+			if value, found := data["attributeValue"].(string); found {
+				check(fmt.Sprintf("/setVariableData/%d/attributeValue", i), data, value, nil)
+			}
+		}
+	case "GetVariablesResponse":
+		for i, result := range children(payload, "getVariableResult") {
+			if value, found := result["attributeValue"].(string); found && str(result, "attributeStatus") == "Accepted" {
+				check(fmt.Sprintf("/getVariableResult/%d/attributeValue", i), result, value, nil)
+			}
+		}
+	}
+
+	return violations
+}
+
+// checkDeviceModelNames checks that device model requests refer to known components and variables, and that
+// reported variables have the data type of the catalog.
+func checkDeviceModelNames(ctx RuleContext, payload map[string]interface{}) []Violation {
+	key, found := deviceModelData[ctx.Action]
+	if !found || ctx.DeviceModel == nil {
+		return nil
+	}
+
+	var violations []Violation
+	for i, data := range children(payload, key) {
+		path := fmt.Sprintf("/%s/%d", key, i)
+		component := str(child(data, "component"), "name")
+		if !ctx.DeviceModel.HasComponent(component) {
+			violations = append(violations, Violation{
+				Field:   path + "/component/name",
+				Message: fmt.Sprintf("unknown component %s", component),
+			})
+			continue
+		}
+
+		variable, found := catalogVariable(ctx, data)
+		if !found {
+			violations = append(violations, Violation{
+				Field:   path + "/variable/name",
+				Message: fmt.Sprintf("unknown variable %s of component %s", str(child(data, "variable"), "name"), component),
+			})
+			continue
+		}
+
+		dataType := str(child(data, "variableCharacteristics"), "dataType")
+		if dataType != "" && devicemodel.DataType(dataType) != variable.DataType {
+			violations = append(violations, Violation{
+				Field:   path + "/variableCharacteristics/dataType",
+				Message: fmt.Sprintf("%s has the dataType %s, expected %s", variableName(data), dataType, variable.DataType),
+			})
+		}
+	}
+
+	return violations
+}
+
+// catalogVariable returns the variable of the device model catalog a device model object refers to.
+func catalogVariable(ctx RuleContext, object map[string]interface{}) (devicemodel.Variable, bool) {
+	if ctx.DeviceModel == nil {
+		return devicemodel.Variable{}, false
+	}
+
+	return ctx.DeviceModel.Variable(str(child(object, "component"), "name"), str(child(object, "variable"), "name"))
+}
+
+// splitList splits a comma-separated valuesList, e.g. "Never,OncePerTransaction".
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+
+	values := strings.Split(list, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	return values
+}
+
+// optionalNumber returns the numeric value of a field, or nil if it's missing.
+func optionalNumber(object map[string]interface{}, key string) *float64 {
+	if n, found := number(object, key); found {
+		return &n
+	}
+
+	return nil
+}
+
+// expectTransactionEvent expects a TransactionEvent with the remoteStartId of a RequestStartTransaction, unless
+// the response shows the transaction had already started.
+func expectTransactionEvent(exchange Exchange) (followUp, bool) {
+	if _, found := exchange.Response["transactionId"]; found {
+		return followUp{}, false
+	}
+
+	remoteStartId, found := number(exchange.Request, "remoteStartId")
+	if !found {
+		return followUp{}, false
+	}
+
+	return followUp{
+		action:      "TransactionEvent",
+		description: fmt.Sprintf("TransactionEvent with remoteStartId %g", remoteStartId),
+		matches: func(request map[string]interface{}) bool {
+			id, found := number(child(request, "transactionInfo"), "remoteStartId")
+			return found && id == remoteStartId
+		},
+	}, true
+}
+
+// expectTriggeredMessage201 expects the message requested by a TriggerMessage.
+func expectTriggeredMessage201(exchange Exchange) (followUp, bool) {
+	requestedMessage := str(exchange.Request, "requestedMessage")
+	if requestedMessage == "" {
+		return followUp{}, false
+	}
+
+	action, found := triggeredActions[requestedMessage]
+	if !found {
+		action = requestedMessage
+	}
+
+	return followUp{
+		action:      action,
+		description: action,
+		matches:     func(map[string]interface{}) bool { return true },
+	}, true
+}
+
+// smartChargingMessages201 are the fields of the OCPP 2.0.1 smart charging messages.
+var smartChargingMessages201 = smartChargingMessages{
+	locationKey: "evseId",
+	profileKey:  "chargingProfile",
+	remoteStart: "RequestStartTransaction",
+	clearCriteria: func(request map[string]interface{}) smartcharging.ClearCriteria {
+		criteria := child(request, "chargingProfileCriteria")
+		return smartcharging.ClearCriteria{
+			Id:         optionalInt(request, "chargingProfileId"),
+			Location:   optionalInt(criteria, "evseId"),
+			Purpose:    optionalPurpose(criteria, "chargingProfilePurpose"),
+			StackLevel: optionalInt(criteria, "stackLevel"),
+		}
+	},
+	trackTransaction: func(exchange Exchange, transactions map[string]int) (int, bool) {
+		if exchange.Action != transactionEventAction {
+			return 0, false
+		}
+
+		transactionId := str(child(exchange.Request, "transactionInfo"), "transactionId")
+		if evseId, found := number(child(exchange.Request, "evse"), "id"); found && transactionId != "" {
+			transactions[transactionId] = int(evseId)
+		}
+
+		evseId, known := transactions[transactionId]
+		return evseId, known && str(exchange.Request, "eventType") == eventTypeEnded
+	},
+	compositeSchedule: "schedule",
+}
@@ -0,0 +1,348 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ChargePi/chargeflow/pkg/smartcharging"
+)
+
+// chargingProfileKindKey is the field identifying charging profile objects.
+const chargingProfileKindKey = "chargingProfileKind"
+
+// scheduleObject is a charging schedule along with its JSON pointer.
+type scheduleObject struct {
+	path     string
+	schedule map[string]interface{}
+}
+
+// profileSchedules returns the charging schedules of a profile: a single schedule in OCPP 1.6, and a list of
+// schedules in OCPP 2.0.1.
+func profileSchedules(path string, profile map[string]interface{}) []scheduleObject {
+	if schedule := child(profile, "chargingSchedule"); schedule != nil {
+		return []scheduleObject{{path: path + "/chargingSchedule", schedule: schedule}}
+	}
+
+	var schedules []scheduleObject
+	for i, schedule := range children(profile, "chargingSchedule") {
+		schedules = append(schedules, scheduleObject{path: fmt.Sprintf("%s/chargingSchedule/%d", path, i), schedule: schedule})
+	}
+
+	return schedules
+}
+
+// checkChargingProfileValidity checks that the validFrom of charging profiles is before their validTo.
+func checkChargingProfileValidity(_ RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	walkObjects(payload, "", func(path string, object map[string]interface{}) {
+		if _, ok := object[chargingProfileKindKey]; !ok {
+			return
+		}
+
+		validFrom, err := time.Parse(time.RFC3339, str(object, "validFrom"))
+		if err != nil {
+			return
+		}
+
+		validTo, err := time.Parse(time.RFC3339, str(object, "validTo"))
+		if err != nil || validTo.After(validFrom) {
+			return
+		}
+
+		violations = append(violations, Violation{
+			Field:   path + "/validTo",
+			Message: fmt.Sprintf("validTo %s is not after validFrom %s", validTo.Format(time.RFC3339), validFrom.Format(time.RFC3339)),
+		})
+	})
+
+	return violations
+}
+
+// checkChargingProfileSchedule returns a check that the schedules of charging profiles match their kind: Recurring
+// profiles have a recurrencyKind and a startSchedule, Relative profiles no startSchedule, recurring schedules don't
+// last longer than their recurrency, and schedule periods start within the duration of the schedule. OCPP 2.0.1
+// requires a startSchedule for Absolute profiles as well.
+func checkChargingProfileSchedule(absoluteStart bool) func(RuleContext, map[string]interface{}) []Violation {
+	return func(_ RuleContext, payload map[string]interface{}) []Violation {
+		var violations []Violation
+		walkObjects(payload, "", func(path string, profile map[string]interface{}) {
+			kind := smartcharging.Kind(str(profile, chargingProfileKindKey))
+			if kind == "" {
+				return
+			}
+
+			recurrency := smartcharging.Recurrency(str(profile, "recurrencyKind"))
+			switch {
+			case kind == smartcharging.Recurring && recurrency == "":
+				violations = append(violations, Violation{
+					Field:   path + "/" + chargingProfileKindKey,
+					Message: "a Recurring charging profile requires a recurrencyKind",
+				})
+			case kind != smartcharging.Recurring && recurrency != "":
+				violations = append(violations, Violation{
+					Field:   path + "/recurrencyKind",
+					Message: fmt.Sprintf("recurrencyKind is only allowed for Recurring charging profiles, not for %s ones", kind),
+				})
+			}
+
+			for _, s := range profileSchedules(path, profile) {
+				violations = append(violations, checkScheduleTiming(kind, recurrency, absoluteStart, s)...)
+			}
+		})
+
+		return violations
+	}
+}
+
+// checkScheduleTiming checks the startSchedule, duration and periods of a schedule of a profile of the kind.
+func checkScheduleTiming(kind smartcharging.Kind, recurrency smartcharging.Recurrency, absoluteStart bool, s scheduleObject) []Violation {
+	var violations []Violation
+
+	_, hasStart := s.schedule["startSchedule"]
+	switch {
+	case kind == smartcharging.Relative && hasStart:
+		violations = append(violations, Violation{
+			Field:   s.path + "/startSchedule",
+			Message: "a Relative charging profile can't have a startSchedule",
+		})
+	case (kind == smartcharging.Recurring || kind == smartcharging.Absolute && absoluteStart) && !hasStart:
+		violations = append(violations, Violation{
+			Field:   s.path,
+			Message: fmt.Sprintf("the schedule of a %s charging profile requires a startSchedule", kind),
+		})
+	}
+
+	duration, found := number(s.schedule, "duration")
+	if !found {
+		return violations
+	}
+
+	if interval := recurrency.Interval().Seconds(); kind == smartcharging.Recurring && interval > 0 && duration > interval {
+		violations = append(violations, Violation{
+			Field:   s.path + "/duration",
+			Message: fmt.Sprintf("a %s recurring schedule lasts at most %g seconds, got %g", recurrency, interval, duration),
+		})
+	}
+
+	for i, period := range children(s.schedule, "chargingSchedulePeriod") {
+		if startPeriod, found := number(period, "startPeriod"); found && startPeriod >= duration {
+			violations = append(violations, Violation{
+				Field:   fmt.Sprintf("%s/chargingSchedulePeriod/%d/startPeriod", s.path, i),
+				Message: fmt.Sprintf("charging schedule period %d starts at %g, after the end of the schedule (%g)", i, startPeriod, duration),
+			})
+		}
+	}
+
+	return violations
+}
+
+// perPhaseValues are the values of a charging schedule period that can be given for each phase (OCPP 2.1). The value
+// itself is then the value of phase L1.
+var perPhaseValues = []string{"limit", "dischargeLimit", "setpoint"}
+
+// checkChargingSchedulePhases checks that the numberPhases of charging schedule periods is between 1 and 3, that
+// a phaseToUse is only set for a single phase, and that per-phase values match the chargingRateUnit and numberPhases.
+// Limits in A are per phase and limits in W are the sum of all phases, unless values are given for L2 and L3 as well,
+// in which case the limit is the limit of L1. In W, the L2 and L3 values therefore have to be given together, and
+// neither unit can have per-phase values when charging on a single phase.
+func checkChargingSchedulePhases(_ RuleContext, payload map[string]interface{}) []Violation {
+	var violations []Violation
+	walkObjects(payload, "", func(path string, schedule map[string]interface{}) {
+		unit := smartcharging.Unit(str(schedule, "chargingRateUnit"))
+
+		for i, period := range children(schedule, "chargingSchedulePeriod") {
+			field := fmt.Sprintf("%s/chargingSchedulePeriod/%d", path, i)
+
+			numberPhases, found := number(period, "numberPhases")
+			if found && (numberPhases < 1 || numberPhases > 3) {
+				violations = append(violations, Violation{
+					Field:   field + "/numberPhases",
+					Message: fmt.Sprintf("numberPhases must be between 1 and 3, got %g", numberPhases),
+				})
+			}
+
+			if _, ok := period["phaseToUse"]; ok && numberPhases != 1 {
+				violations = append(violations, Violation{
+					Field:   field + "/phaseToUse",
+					Message: "phaseToUse may only be set when numberPhases is 1",
+				})
+			}
+
+			for _, value := range perPhaseValues {
+				_, hasL2 := period[value+"_L2"]
+				_, hasL3 := period[value+"_L3"]
+
+				switch {
+				case (hasL2 || hasL3) && found && numberPhases == 1:
+					violations = append(violations, Violation{
+						Field:   field + "/numberPhases",
+						Message: fmt.Sprintf("%s_L2 and %s_L3 can't be set when charging on a single phase", value, value),
+					})
+				case unit == smartcharging.Watt && hasL2 != hasL3:
+					violations = append(violations, Violation{
+						Field:   field,
+						Message: fmt.Sprintf("%s_L2 and %s_L3 must be set together in W, as %s then applies to L1 instead of all phases", value, value, value),
+					})
+				}
+			}
+		}
+	})
+
+	return violations
+}
+
+// smartChargingMessages are the fields of the smart charging messages of an OCPP version.
+type smartChargingMessages struct {
+	// locationKey is the field of the connector or EVSE, e.g. "connectorId".
+	locationKey string
+	// profileKey is the field of the charging profile of a SetChargingProfile request, e.g. "csChargingProfiles".
+	profileKey string
+	// remoteStart is the action starting a transaction remotely, e.g. "RemoteStartTransaction".
+	remoteStart string
+	// clearCriteria returns the criteria of a ClearChargingProfile request.
+	clearCriteria func(request map[string]interface{}) smartcharging.ClearCriteria
+	// trackTransaction keeps the connector or EVSE of the transactions started with the exchange by their ID, and
+	// returns the connector or EVSE of the transaction that ended with it, if any.
+	trackTransaction func(exchange Exchange, transactions map[string]int) (int, bool)
+	// compositeSchedule is the field of the composite schedule of a GetCompositeSchedule response.
+	compositeSchedule string
+}
+
+// checkCompositeSchedule returns a sequence check that simulates the composite schedule from the charging profiles
+// set, cleared and ended with the transactions, and compares it with the schedules of GetCompositeSchedule responses.
+func checkCompositeSchedule(messages smartChargingMessages) func(RuleContext, []Exchange) map[string][]Violation {
+	return func(_ RuleContext, exchanges []Exchange) map[string][]Violation {
+		violations := make(map[string][]Violation)
+		station := smartcharging.NewStation()
+		transactions := make(map[string]int)
+
+		for _, exchange := range exchanges {
+			if location, ended := messages.trackTransaction(exchange, transactions); ended {
+				station.EndTransaction(location)
+				continue
+			}
+
+			if str(exchange.Response, "status") != accepted {
+				continue
+			}
+
+			switch exchange.Action {
+			case "SetChargingProfile":
+				setProfile(station, exchange.Request, messages.locationKey, messages.profileKey)
+			case messages.remoteStart:
+				setProfile(station, exchange.Request, messages.locationKey, "chargingProfile")
+			case "ClearChargingProfile":
+				station.Clear(messages.clearCriteria(exchange.Request))
+			case "GetCompositeSchedule":
+				if station.Len() == 0 {
+					// Without known profiles, all limits are the charging station's own
+					continue
+				}
+
+				found := compareCompositeSchedule(station, exchange, messages.locationKey, messages.compositeSchedule)
+				if len(found) > 0 {
+					violations[exchange.MessageId] = append(violations[exchange.MessageId], found...)
+				}
+			}
+		}
+
+		return violations
+	}
+}
+
+// setProfile installs the charging profile of the request on its connector or EVSE.
+func setProfile(station *smartcharging.Station, request map[string]interface{}, locationKey, profileKey string) {
+	location, found := number(request, locationKey)
+	profile := child(request, profileKey)
+	if !found || profile == nil {
+		return
+	}
+
+	parsed, err := smartcharging.ParseProfile(profile)
+	if err != nil {
+		return
+	}
+
+	station.Set(int(location), parsed)
+}
+
+// compareCompositeSchedule compares the composite schedule of a GetCompositeSchedule response with the simulated one.
+func compareCompositeSchedule(station *smartcharging.Station, exchange Exchange, locationKey, scheduleKey string) []Violation {
+	location, found := number(exchange.Request, locationKey)
+	duration, durationFound := number(exchange.Request, "duration")
+	scheduleObject := child(exchange.Response, scheduleKey)
+	if !found || !durationFound || scheduleObject == nil {
+		return nil
+	}
+
+	reported, err := smartcharging.ParseSchedule(scheduleObject)
+	if err != nil {
+		return nil
+	}
+
+	// The start of an OCPP 1.6 composite schedule can be set on the response as well
+	if start, err := time.Parse(time.RFC3339, str(exchange.Response, "scheduleStart")); err == nil && reported.StartSchedule == nil {
+		reported.StartSchedule = &start
+	}
+
+	start, ok := reported.Start()
+	if !ok {
+		return nil
+	}
+
+	var violations []Violation
+	unit := smartcharging.Unit(str(exchange.Request, "chargingRateUnit"))
+	switch {
+	case unit != "" && reported.ChargingRateUnit != "" && unit != reported.ChargingRateUnit:
+		violations = append(violations, Violation{
+			Field:   "/" + scheduleKey + "/chargingRateUnit",
+			Message: fmt.Sprintf("the composite schedule was requested in %s, but reported in %s", unit, reported.ChargingRateUnit),
+		})
+	case unit == "":
+		unit = reported.ChargingRateUnit
+	}
+
+	expected := station.Composite(int(location), start, time.Duration(duration)*time.Second, unit)
+	for _, discrepancy := range smartcharging.Compare(expected, reported, unit) {
+		reportedLimit := "no limit was reported"
+		if discrepancy.Reported != nil {
+			reportedLimit = fmt.Sprintf("%s %s was reported", formatLimit(*discrepancy.Reported), unit)
+		}
+
+		violations = append(violations, Violation{
+			Field: "/" + scheduleKey,
+			Message: fmt.Sprintf("from %s until %s the simulated composite limit is %s %s, but %s",
+				discrepancy.Start.UTC().Format(time.RFC3339), discrepancy.End.UTC().Format(time.RFC3339),
+				formatLimit(discrepancy.Expected), unit, reportedLimit),
+		})
+	}
+
+	return violations
+}
+
+// formatLimit formats a limit with at most one decimal.
+func formatLimit(limit float64) string {
+	return strconv.FormatFloat(math.Round(limit*10)/10, 'f', -1, 64)
+}
+
+// optionalInt returns a pointer to the integer value of a field, or nil if it's missing.
+func optionalInt(object map[string]interface{}, key string) *int {
+	if n, found := number(object, key); found {
+		i := int(n)
+		return &i
+	}
+
+	return nil
+}
+
+// optionalPurpose returns a pointer to the charging profile purpose of a field, or nil if it's missing.
+func optionalPurpose(object map[string]interface{}, key string) *smartcharging.Purpose {
+	if purpose := str(object, key); purpose != "" {
+		p := smartcharging.Purpose(purpose)
+		return &p
+	}
+
+	return nil
+}
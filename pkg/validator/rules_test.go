@@ -0,0 +1,756 @@
+package validator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kaptinlin/jsonschema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	mock_schema_registry "github.com/ChargePi/chargeflow/gen/mocks/pkg/schema_registry"
+	"github.com/ChargePi/chargeflow/pkg/certificates"
+	"github.com/ChargePi/chargeflow/pkg/configkeys"
+	"github.com/ChargePi/chargeflow/pkg/devicemodel"
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/schema_registry"
+)
+
+type rulesTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (s *rulesTestSuite) SetupSuite() {
+	s.now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+// rule returns the built-in rule with the ID.
+func (s *rulesTestSuite) rule(id string) Rule {
+	for _, rule := range BuiltinRules() {
+		if rule.ID == id {
+			return rule
+		}
+	}
+
+	s.FailNow("rule not found", id)
+	return Rule{}
+}
+
+// check checks the payload against a single built-in rule and returns the violations.
+func (s *rulesTestSuite) check(id, action string, payload map[string]interface{}) []Violation {
+	return s.rule(id).Check(RuleContext{Action: action, Now: s.now}, payload)
+}
+
+func (s *rulesTestSuite) TestChargingSchedulePeriods() {
+	payload := map[string]interface{}{
+		"chargingProfile": map[string]interface{}{
+			"chargingSchedule": []interface{}{
+				map[string]interface{}{
+					"chargingSchedulePeriod": []interface{}{
+						map[string]interface{}{"startPeriod": float64(0), "limit": float64(16)},
+						map[string]interface{}{"startPeriod": float64(600), "limit": float64(10)},
+					},
+				},
+				map[string]interface{}{
+					"chargingSchedulePeriod": []interface{}{
+						map[string]interface{}{"startPeriod": float64(60)},
+						map[string]interface{}{"startPeriod": float64(60)},
+					},
+				},
+			},
+		},
+	}
+
+	violations := s.check("ocpp201.charging-schedule-periods", "SetChargingProfileRequest", payload)
+	s.Equal([]Violation{
+		{
+			Field:   "/chargingProfile/chargingSchedule/1/chargingSchedulePeriod/0/startPeriod",
+			Message: "the first charging schedule period must start at 0, but starts at 60",
+		},
+		{
+			Field:   "/chargingProfile/chargingSchedule/1/chargingSchedulePeriod/1/startPeriod",
+			Message: "charging schedule period 1 starts at 60, which is not after the previous period (60)",
+		},
+	}, violations)
+}
+
+func (s *rulesTestSuite) TestStackLevel() {
+	s.Empty(s.check("ocpp16.stack-level", "SetChargingProfileRequest", map[string]interface{}{
+		"csChargingProfiles": map[string]interface{}{"stackLevel": float64(0)},
+	}))
+
+	s.Equal([]Violation{{Field: "/csChargingProfiles/stackLevel", Message: "stackLevel must not be negative, got -1"}},
+		s.check("ocpp16.stack-level", "SetChargingProfileRequest", map[string]interface{}{
+			"csChargingProfiles": map[string]interface{}{"stackLevel": float64(-1)},
+		}),
+	)
+}
+
+func (s *rulesTestSuite) TestChargingProfileLocation() {
+	profile := func(location float64, purpose string) map[string]interface{} {
+		return map[string]interface{}{
+			"connectorId":        location,
+			"csChargingProfiles": map[string]interface{}{"chargingProfilePurpose": purpose},
+		}
+	}
+
+	s.Empty(s.check("ocpp16.charging-profile-connector", "SetChargingProfileRequest", profile(0, "ChargePointMaxProfile")))
+	s.Empty(s.check("ocpp16.charging-profile-connector", "SetChargingProfileRequest", profile(1, "TxProfile")))
+	s.Empty(s.check("ocpp16.charging-profile-connector", "SetChargingProfileRequest", profile(0, "TxDefaultProfile")))
+	s.Len(s.check("ocpp16.charging-profile-connector", "SetChargingProfileRequest", profile(1, "ChargePointMaxProfile")), 1)
+	s.Len(s.check("ocpp16.charging-profile-connector", "SetChargingProfileRequest", profile(0, "TxProfile")), 1)
+
+	s.Equal([]Violation{{Field: "/evseId", Message: "a ChargingStationMaxProfile can only be set with evseId 0, got 2"}},
+		s.check("ocpp201.charging-profile-evse", "SetChargingProfileRequest", map[string]interface{}{
+			"evseId":          float64(2),
+			"chargingProfile": map[string]interface{}{"chargingProfilePurpose": "ChargingStationMaxProfile"},
+		}),
+	)
+}
+
+func (s *rulesTestSuite) TestChargingProfileTransactionId() {
+	s.Len(s.check("ocpp16.charging-profile-transaction-id", "SetChargingProfileRequest", map[string]interface{}{
+		"csChargingProfiles": map[string]interface{}{"chargingProfilePurpose": "TxDefaultProfile", "transactionId": float64(1)},
+	}), 1)
+
+	s.Empty(s.check("ocpp16.charging-profile-transaction-id", "SetChargingProfileRequest", map[string]interface{}{
+		"csChargingProfiles": map[string]interface{}{"chargingProfilePurpose": "TxProfile"},
+	}))
+
+	s.Equal([]Violation{{Field: "/chargingProfile/transactionId", Message: "a TxProfile must have a transactionId"}},
+		s.check("ocpp201.charging-profile-transaction-id", "SetChargingProfileRequest", map[string]interface{}{
+			"chargingProfile": map[string]interface{}{"chargingProfilePurpose": "TxProfile"},
+		}),
+	)
+
+	// A TxProfile of a remote start is set before the transaction exists
+	s.Empty(s.check("ocpp201.charging-profile-transaction-id", "RequestStartTransactionRequest", map[string]interface{}{
+		"chargingProfile": map[string]interface{}{"chargingProfilePurpose": "TxProfile"},
+	}))
+}
+
+func (s *rulesTestSuite) TestStartProfilePurpose() {
+	s.Equal([]Violation{{
+		Field:   "/chargingProfile/chargingProfilePurpose",
+		Message: "the charging profile of a remote start must be a TxProfile, not a TxDefaultProfile",
+	}}, s.check("ocpp16.remote-start-profile-purpose", "RemoteStartTransactionRequest", map[string]interface{}{
+		"chargingProfile": map[string]interface{}{"chargingProfilePurpose": "TxDefaultProfile"},
+	}))
+
+	s.Empty(s.check("ocpp201.request-start-profile-purpose", "RequestStartTransactionRequest", map[string]interface{}{"idToken": map[string]interface{}{}}))
+}
+
+func (s *rulesTestSuite) TestConnectorId() {
+	s.Len(s.check("ocpp16.connector-id", "StartTransactionRequest", map[string]interface{}{"connectorId": float64(0)}), 1)
+	s.Empty(s.check("ocpp16.connector-id", "StartTransactionRequest", map[string]interface{}{"connectorId": float64(1)}))
+}
+
+func (s *rulesTestSuite) TestEvseConnector() {
+	s.Equal([]Violation{{Field: "/evse/id", Message: "connector 1 must belong to an EVSE with an ID greater than 0, got 0"}},
+		s.check("ocpp201.evse-connector", "ChangeAvailabilityRequest", map[string]interface{}{
+			"evse": map[string]interface{}{"id": float64(0), "connectorId": float64(1)},
+		}),
+	)
+
+	s.Equal([]Violation{{Field: "/connectorId", Message: "connectors are numbered from 1, got connectorId 0"}},
+		s.check("ocpp201.evse-connector", "StatusNotificationRequest", map[string]interface{}{
+			"evseId":      float64(1),
+			"connectorId": float64(0),
+		}),
+	)
+
+	s.Empty(s.check("ocpp201.evse-connector", "ChangeAvailabilityRequest", map[string]interface{}{
+		"evse": map[string]interface{}{"id": float64(0)},
+	}))
+}
+
+func (s *rulesTestSuite) TestTransactionEvent() {
+	event := func(eventType, triggerReason string) map[string]interface{} {
+		return map[string]interface{}{
+			"eventType":       eventType,
+			"triggerReason":   triggerReason,
+			"transactionInfo": map[string]interface{}{"transactionId": "1"},
+		}
+	}
+
+	s.Empty(s.check("ocpp201.transaction-event-trigger-reason", "TransactionEventRequest", event("Started", "CablePluggedIn")))
+	s.Empty(s.check("ocpp201.transaction-event-trigger-reason", "TransactionEventRequest", event("Updated", "MeterValuePeriodic")))
+	s.Empty(s.check("ocpp201.transaction-event-trigger-reason", "TransactionEventRequest", event("Ended", "EVDeparted")))
+	s.Equal([]Violation{{Field: "/triggerReason", Message: "triggerReason StopAuthorized is not allowed for a TransactionEvent with eventType Started"}},
+		s.check("ocpp201.transaction-event-trigger-reason", "TransactionEventRequest", event("Started", "StopAuthorized")),
+	)
+	s.Len(s.check("ocpp201.transaction-event-trigger-reason", "TransactionEventRequest", event("Ended", "MeterValuePeriodic")), 1)
+
+	updated := event("Updated", "EVDeparted")
+	updated["transactionInfo"] = map[string]interface{}{"transactionId": "1", "stoppedReason": "EVDisconnected"}
+	s.Equal([]Violation{{Field: "/transactionInfo/stoppedReason", Message: "stoppedReason may only be set when the eventType is Ended, not Updated"}},
+		s.check("ocpp201.transaction-event-stopped-reason", "TransactionEventRequest", updated),
+	)
+}
+
+func (s *rulesTestSuite) TestAuthorizationExpiry() {
+	payload := func(status, expiry string) map[string]interface{} {
+		return map[string]interface{}{
+			"idTokenInfo": map[string]interface{}{"status": status, "cacheExpiryDateTime": expiry},
+		}
+	}
+
+	s.Empty(s.check("ocpp201.id-token-cache-expiry", "AuthorizeResponse", payload("Accepted", "2024-01-02T00:00:00Z")))
+	s.Empty(s.check("ocpp201.id-token-cache-expiry", "AuthorizeResponse", payload("Expired", "2023-12-31T00:00:00Z")))
+	s.Equal([]Violation{{
+		Field:   "/idTokenInfo/cacheExpiryDateTime",
+		Message: "idTokenInfo is Accepted, but expired at 2023-12-31T00:00:00Z",
+	}}, s.check("ocpp201.id-token-cache-expiry", "AuthorizeResponse", payload("Accepted", "2023-12-31T00:00:00Z")))
+}
+
+func (s *rulesTestSuite) TestGetConfigurationKeys() {
+	check := func(request, response map[string]interface{}) []Violation {
+		return s.rule("ocpp16.get-configuration-keys").Check(RuleContext{Action: "GetConfigurationResponse", Request: request}, response)
+	}
+
+	response := map[string]interface{}{
+		"configurationKey": []interface{}{
+			map[string]interface{}{"key": "HeartbeatInterval", "readonly": false, "value": "300"},
+			map[string]interface{}{"key": "MeterValueSampleInterval", "readonly": false, "value": "60"},
+		},
+		"unknownKey": []interface{}{"AcmeKey"},
+	}
+
+	// All keys were requested
+	s.Empty(check(map[string]interface{}{}, response))
+	s.Empty(check(map[string]interface{}{"key": []interface{}{"AcmeKey", "HeartbeatInterval", "MeterValueSampleInterval"}}, response))
+
+	s.Equal([]Violation{
+		{Field: "/configurationKey/1/key", Message: "configuration key MeterValueSampleInterval was not requested"},
+		{Field: "/unknownKey/0", Message: "unknown key AcmeKey was not requested"},
+		{Message: "requested key ConnectionTimeOut is neither in configurationKey nor in unknownKey"},
+	}, check(map[string]interface{}{"key": []interface{}{"HeartbeatInterval", "ConnectionTimeOut"}}, response))
+}
+
+func (s *rulesTestSuite) TestConfiguration() {
+	catalog := configkeys.Standard()
+	catalog.Add("ACME", "", configkeys.Key{Name: "AcmeLedMode", Type: configkeys.String, Values: []string{"On", "Off"}})
+	catalog.Add("ACME", "Wallbox", configkeys.Key{Name: "HeartbeatInterval", Type: configkeys.Integer, ReadOnly: true})
+	check := func(id string, octx ocpp.OcppContext, action string, payload map[string]interface{}) []Violation {
+		return s.rule(id).Check(RuleContext{OcppContext: octx, Action: action, ConfigurationKeys: catalog}, payload)
+	}
+	acme := ocpp.OcppContext{Vendor: "ACME", Model: "Wallbox"}
+
+	s.Empty(check("ocpp16.configuration-values", ocpp.OcppContext{}, "ChangeConfigurationRequest", map[string]interface{}{"key": "HeartbeatInterval", "value": "300"}))
+	s.Equal([]Violation{
+		{Field: "/value", Message: `HeartbeatInterval: "5m" is not a valid integer`},
+	}, check("ocpp16.configuration-values", ocpp.OcppContext{}, "ChangeConfigurationRequest", map[string]interface{}{"key": "HeartbeatInterval", "value": "5m"}))
+	s.Equal([]Violation{
+		{Field: "/configurationKey/0/value", Message: "LightIntensity: 150 is greater than the maximum 100"},
+		{Field: "/configurationKey/1/value", Message: `SupportedFeatureProfiles: "Billing" is not one of Core,FirmwareManagement,LocalAuthListManagement,Reservation,SmartCharging,RemoteTrigger`},
+		{Field: "/configurationKey/3/value", Message: `AcmeLedMode: "Blink" is not one of On,Off`},
+	}, check("ocpp16.configuration-values", acme, "GetConfigurationResponse", map[string]interface{}{
+		"configurationKey": []interface{}{
+			map[string]interface{}{"key": "LightIntensity", "readonly": false, "value": "150"},
+			map[string]interface{}{"key": "SupportedFeatureProfiles", "readonly": true, "value": "Core,Billing"},
+			map[string]interface{}{"key": "VendorKey", "readonly": false, "value": "anything"},
+			map[string]interface{}{"key": "AcmeLedMode", "readonly": false, "value": "Blink"},
+		},
+	}))
+
+	// Vendor/model-specific keys take precedence
+	s.Empty(check("ocpp16.configuration-read-only", ocpp.OcppContext{Vendor: "ACME"}, "ChangeConfigurationRequest", map[string]interface{}{"key": "HeartbeatInterval", "value": "300"}))
+	s.Equal([]Violation{
+		{Field: "/key", Message: "configuration key HeartbeatInterval is read-only"},
+	}, check("ocpp16.configuration-read-only", acme, "ChangeConfigurationRequest", map[string]interface{}{"key": "HeartbeatInterval", "value": "300"}))
+	s.Equal([]Violation{
+		{Field: "/configurationKey/0/readonly", Message: "configuration key NumberOfConnectors is read-only, but reported as writable"},
+		{Field: "/configurationKey/2", Message: "configuration key AuthorizationKey is write-only and must not be reported"},
+	}, check("ocpp16.configuration-read-only", ocpp.OcppContext{}, "GetConfigurationResponse", map[string]interface{}{
+		"configurationKey": []interface{}{
+			map[string]interface{}{"key": "NumberOfConnectors", "readonly": false, "value": "2"},
+			map[string]interface{}{"key": "AuthorizeRemoteTxRequests", "readonly": true, "value": "true"},
+			map[string]interface{}{"key": "AuthorizationKey", "readonly": false},
+		},
+	}))
+
+	s.Equal([]Violation{
+		{Field: "/key/1", Message: "unknown configuration key AcmeLedMode"},
+	}, check("ocpp16.configuration-keys", ocpp.OcppContext{}, "GetConfigurationRequest", map[string]interface{}{"key": []interface{}{"HeartbeatInterval", "AcmeLedMode"}}))
+	s.Empty(check("ocpp16.configuration-keys", acme, "GetConfigurationRequest", map[string]interface{}{"key": []interface{}{"HeartbeatInterval", "AcmeLedMode"}}))
+}
+
+func (s *rulesTestSuite) TestVariableResults() {
+	request := map[string]interface{}{
+		"setVariableData": []interface{}{
+			map[string]interface{}{
+				"attributeValue": "300",
+				"component":      map[string]interface{}{"name": "OCPPCommCtrlr"},
+				"variable":       map[string]interface{}{"name": "HeartbeatInterval"},
+			},
+			map[string]interface{}{
+				"attributeType":  "Target",
+				"attributeValue": "11000",
+				"component":      map[string]interface{}{"name": "EVSE", "evse": map[string]interface{}{"id": float64(1)}},
+				"variable":       map[string]interface{}{"name": "Power"},
+			},
+		},
+	}
+	check := func(response map[string]interface{}) []Violation {
+		return s.rule("ocpp201.set-variables-results").Check(RuleContext{Action: "SetVariablesResponse", Request: request}, response)
+	}
+
+	s.Empty(check(map[string]interface{}{
+		"setVariableResult": []interface{}{
+			map[string]interface{}{
+				"attributeType":   "Target",
+				"attributeStatus": "Accepted",
+				"component":       map[string]interface{}{"name": "EVSE", "evse": map[string]interface{}{"id": float64(1)}},
+				"variable":        map[string]interface{}{"name": "Power"},
+			},
+			map[string]interface{}{
+				"attributeStatus": "Accepted",
+				"component":       map[string]interface{}{"name": "OCPPCommCtrlr"},
+				"variable":        map[string]interface{}{"name": "HeartbeatInterval"},
+			},
+		},
+	}))
+
+	s.Equal([]Violation{
+		{Field: "/setVariableResult/0", Message: "setVariableResult for EVSE[2]/Power/Target does not match a requested variable"},
+		{Field: "/setVariableResult", Message: "setVariableResult is missing for the requested variable OCPPCommCtrlr/HeartbeatInterval/Actual"},
+		{Field: "/setVariableResult", Message: "setVariableResult is missing for the requested variable EVSE[1]/Power/Target"},
+	}, check(map[string]interface{}{
+		"setVariableResult": []interface{}{
+			map[string]interface{}{
+				"attributeType":   "Target",
+				"attributeStatus": "Accepted",
+				"component":       map[string]interface{}{"name": "EVSE", "evse": map[string]interface{}{"id": float64(2)}},
+				"variable":        map[string]interface{}{"name": "Power"},
+			},
+		},
+	}))
+}
+
+func (s *rulesTestSuite) TestDeviceModelValues() {
+	check := func(action string, payload map[string]interface{}) []Violation {
+		return s.rule("ocpp201.device-model-values").Check(RuleContext{Action: action, DeviceModel: devicemodel.Standard()}, payload)
+	}
+
+	s.Empty(check("SetVariablesRequest", map[string]interface{}{
+		"setVariableData": []interface{}{
+			map[string]interface{}{
+				"attributeValue": "300",
+				"component":      map[string]interface{}{"name": "OCPPCommCtrlr"},
+				"variable":       map[string]interface{}{"name": "HeartbeatInterval"},
+			},
+			map[string]interface{}{
+				"attributeValue": "EVConnected,Authorized",
+				"component":      map[string]interface{}{"name": "TxCtrlr"},
+				"variable":       map[string]interface{}{"name": "TxStartPoint"},
+			},
+			map[string]interface{}{
+				"attributeValue": "anything",
+				"component":      map[string]interface{}{"name": "AcmeCtrlr"},
+				"variable":       map[string]interface{}{"name": "Mode"},
+			},
+		},
+	}))
+
+	s.Equal([]Violation{
+		{Field: "/setVariableData/0/attributeValue", Message: `OCPPCommCtrlr/HeartbeatInterval: "5m" is not a valid integer`},
+		{Field: "/setVariableData/1/attributeValue", Message: `TxCtrlr/TxStartPoint: "Plugged" is not one of ParkingBayOccupancy,EVConnected,Authorized,DataSigned,PowerPathClosed,EnergyTransfer`},
+	}, check("SetVariablesRequest", map[string]interface{}{
+		"setVariableData": []interface{}{
+			map[string]interface{}{
+				"attributeValue": "5m",
+				"component":      map[string]interface{}{"name": "OCPPCommCtrlr"},
+				"variable":       map[string]interface{}{"name": "HeartbeatInterval"},
+			},
+			map[string]interface{}{
+				"attributeValue": "EVConnected,Plugged",
+				"component":      map[string]interface{}{"name": "TxCtrlr"},
+				"variable":       map[string]interface{}{"name": "TxStartPoint"},
+			},
+		},
+	}))
+
+	// Reported values are checked against the reported characteristics
+	s.Equal([]Violation{
+		{Field: "/reportData/0/variableAttribute/1/value", Message: "AcmeCtrlr/Level: 11 is greater than the maximum 10"},
+		{Field: "/reportData/1/variableAttribute/0/value", Message: `EVSE[1]/AvailabilityState: "Charging" is not one of Available,Occupied,Reserved,Unavailable,Faulted`},
+	}, check("NotifyReportRequest", map[string]interface{}{
+		"reportData": []interface{}{
+			map[string]interface{}{
+				"component": map[string]interface{}{"name": "AcmeCtrlr"},
+				"variable":  map[string]interface{}{"name": "Level"},
+				"variableAttribute": []interface{}{
+					map[string]interface{}{"type": "Actual", "value": "3"},
+					map[string]interface{}{"type": "MaxSet", "value": "11"},
+				},
+				"variableCharacteristics": map[string]interface{}{"dataType": "integer", "minLimit": float64(0), "maxLimit": float64(10)},
+			},
+			map[string]interface{}{
+				"component":         map[string]interface{}{"name": "EVSE", "evse": map[string]interface{}{"id": float64(1)}},
+				"variable":          map[string]interface{}{"name": "AvailabilityState"},
+				"variableAttribute": []interface{}{map[string]interface{}{"value": "Charging"}},
+			},
+		},
+	}))
+
+	// Only accepted results have a value
+	s.Equal([]Violation{
+		{Field: "/getVariableResult/0/attributeValue", Message: `AuthCtrlr/LocalPreAuthorize: "yes" is not a valid boolean, expected true or false`},
+	}, check("GetVariablesResponse", map[string]interface{}{
+		"getVariableResult": []interface{}{
+			map[string]interface{}{
+				"attributeStatus": "Accepted",
+				"attributeValue":  "yes",
+				"component":       map[string]interface{}{"name": "AuthCtrlr"},
+				"variable":        map[string]interface{}{"name": "LocalPreAuthorize"},
+			},
+			map[string]interface{}{
+				"attributeStatus": "Rejected",
+				"attributeValue":  "no",
+				"component":       map[string]interface{}{"name": "AuthCtrlr"},
+				"variable":        map[string]interface{}{"name": "Enabled"},
+			},
+		},
+	}))
+}
+
+func (s *rulesTestSuite) TestDeviceModelNames() {
+	catalog := devicemodel.Standard()
+	catalog.Add(devicemodel.Component{Name: "AcmeCtrlr", Variables: []devicemodel.Variable{{Name: "Mode", DataType: devicemodel.String}}})
+	check := func(action string, payload map[string]interface{}) []Violation {
+		return s.rule("ocpp201.device-model-names").Check(RuleContext{Action: action, DeviceModel: catalog}, payload)
+	}
+
+	s.Empty(check("GetVariablesRequest", map[string]interface{}{
+		"getVariableData": []interface{}{
+			map[string]interface{}{"component": map[string]interface{}{"name": "AcmeCtrlr"}, "variable": map[string]interface{}{"name": "Mode"}},
+			map[string]interface{}{"component": map[string]interface{}{"name": "AcmeCtrlr"}, "variable": map[string]interface{}{"name": "Available"}},
+		},
+	}))
+
+	s.Equal([]Violation{
+		{Field: "/setMonitoringData/0/component/name", Message: "unknown component OtherCtrlr"},
+		{Field: "/setMonitoringData/1/variable/name", Message: "unknown variable Speed of component EVSE"},
+	}, check("SetVariableMonitoringRequest", map[string]interface{}{
+		"setMonitoringData": []interface{}{
+			map[string]interface{}{"component": map[string]interface{}{"name": "OtherCtrlr"}, "variable": map[string]interface{}{"name": "Mode"}},
+			map[string]interface{}{"component": map[string]interface{}{"name": "EVSE"}, "variable": map[string]interface{}{"name": "Speed"}},
+		},
+	}))
+
+	s.Equal([]Violation{
+		{Field: "/reportData/0/variableCharacteristics/dataType", Message: "OCPPCommCtrlr/HeartbeatInterval has the dataType string, expected integer"},
+	}, check("NotifyReportRequest", map[string]interface{}{
+		"reportData": []interface{}{
+			map[string]interface{}{
+				"component":               map[string]interface{}{"name": "OCPPCommCtrlr"},
+				"variable":                map[string]interface{}{"name": "HeartbeatInterval"},
+				"variableCharacteristics": map[string]interface{}{"dataType": "string"},
+			},
+		},
+	}))
+}
+
+// certificate creates a certificate valid in the year 2024, self-signed if the parent is nil.
+func (s *rulesTestSuite) certificate(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	if template.NotAfter.IsZero() {
+		template.NotBefore = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		template.NotAfter = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	s.Require().NoError(err)
+
+	certificate, err := x509.ParseCertificate(der)
+	s.Require().NoError(err)
+	return certificate, key
+}
+
+func encodePEM(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func (s *rulesTestSuite) TestChargingProfiles() {
+	profile := func(kind, recurrency string, schedule map[string]interface{}) map[string]interface{} {
+		profile := map[string]interface{}{
+			"chargingProfileId":      float64(1),
+			"stackLevel":             float64(0),
+			"chargingProfilePurpose": "TxDefaultProfile",
+			"chargingProfileKind":    kind,
+			"chargingSchedule":       schedule,
+		}
+		if recurrency != "" {
+			profile["recurrencyKind"] = recurrency
+		}
+
+		return map[string]interface{}{"connectorId": float64(0), "csChargingProfiles": profile}
+	}
+	periods := []interface{}{
+		map[string]interface{}{"startPeriod": float64(0), "limit": 16.0},
+		map[string]interface{}{"startPeriod": float64(3600), "limit": 10.0},
+	}
+
+	s.Empty(s.check("ocpp16.charging-profile-schedule", "SetChargingProfileRequest", profile("Absolute", "", map[string]interface{}{"chargingRateUnit": "A", "chargingSchedulePeriod": periods})))
+	s.Empty(s.check("ocpp16.charging-profile-schedule", "SetChargingProfileRequest", profile("Recurring", "Daily", map[string]interface{}{
+		"startSchedule": "2024-01-01T00:00:00Z", "duration": float64(86400), "chargingRateUnit": "A", "chargingSchedulePeriod": periods,
+	})))
+	s.Equal([]Violation{
+		{Field: "/csChargingProfiles/chargingProfileKind", Message: "a Recurring charging profile requires a recurrencyKind"},
+		{Field: "/csChargingProfiles/chargingSchedule", Message: "the schedule of a Recurring charging profile requires a startSchedule"},
+	}, s.check("ocpp16.charging-profile-schedule", "SetChargingProfileRequest", profile("Recurring", "", map[string]interface{}{"chargingRateUnit": "A", "chargingSchedulePeriod": periods})))
+	s.Equal([]Violation{
+		{Field: "/csChargingProfiles/recurrencyKind", Message: "recurrencyKind is only allowed for Recurring charging profiles, not for Relative ones"},
+		{Field: "/csChargingProfiles/chargingSchedule/startSchedule", Message: "a Relative charging profile can't have a startSchedule"},
+		{Field: "/csChargingProfiles/chargingSchedule/chargingSchedulePeriod/1/startPeriod", Message: "charging schedule period 1 starts at 3600, after the end of the schedule (1800)"},
+	}, s.check("ocpp16.charging-profile-schedule", "SetChargingProfileRequest", profile("Relative", "Daily", map[string]interface{}{
+		"startSchedule": "2024-01-01T00:00:00Z", "duration": float64(1800), "chargingRateUnit": "A", "chargingSchedulePeriod": periods,
+	})))
+	s.Equal([]Violation{
+		{Field: "/csChargingProfiles/chargingSchedule/duration", Message: "a Daily recurring schedule lasts at most 86400 seconds, got 90000"},
+	}, s.check("ocpp16.charging-profile-schedule", "SetChargingProfileRequest", profile("Recurring", "Daily", map[string]interface{}{
+		"startSchedule": "2024-01-01T00:00:00Z", "duration": float64(90000), "chargingRateUnit": "A", "chargingSchedulePeriod": periods,
+	})))
+
+	// OCPP 2.0.1 requires a startSchedule for Absolute profiles, and has a list of schedules
+	s.Equal([]Violation{
+		{Field: "/chargingProfile/chargingSchedule/0", Message: "the schedule of a Absolute charging profile requires a startSchedule"},
+	}, s.check("ocpp201.charging-profile-schedule", "SetChargingProfileRequest", map[string]interface{}{
+		"evseId": float64(1),
+		"chargingProfile": map[string]interface{}{
+			"id": float64(1), "stackLevel": float64(0), "chargingProfilePurpose": "TxDefaultProfile", "chargingProfileKind": "Absolute",
+			"chargingSchedule": []interface{}{map[string]interface{}{"id": float64(1), "chargingRateUnit": "A", "chargingSchedulePeriod": periods}},
+		},
+	}))
+
+	validity := profile("Absolute", "", map[string]interface{}{"chargingRateUnit": "A", "chargingSchedulePeriod": periods})
+	validity["csChargingProfiles"].(map[string]interface{})["validFrom"] = "2024-01-02T00:00:00Z"
+	validity["csChargingProfiles"].(map[string]interface{})["validTo"] = "2024-01-01T00:00:00Z"
+	s.Equal([]Violation{
+		{Field: "/csChargingProfiles/validTo", Message: "validTo 2024-01-01T00:00:00Z is not after validFrom 2024-01-02T00:00:00Z"},
+	}, s.check("ocpp16.charging-profile-validity", "SetChargingProfileRequest", validity))
+
+	s.Equal([]Violation{
+		{Field: "/chargingProfile/chargingSchedule/0/chargingSchedulePeriod/0/numberPhases", Message: "numberPhases must be between 1 and 3, got 4"},
+		{Field: "/chargingProfile/chargingSchedule/0/chargingSchedulePeriod/0/phaseToUse", Message: "phaseToUse may only be set when numberPhases is 1"},
+	}, s.check("ocpp201.charging-schedule-phases", "SetChargingProfileRequest", map[string]interface{}{
+		"chargingProfile": map[string]interface{}{
+			"chargingSchedule": []interface{}{map[string]interface{}{
+				"chargingSchedulePeriod": []interface{}{
+					map[string]interface{}{"startPeriod": float64(0), "limit": 16.0, "numberPhases": float64(4), "phaseToUse": float64(1)},
+					map[string]interface{}{"startPeriod": float64(60), "limit": 16.0, "numberPhases": float64(1), "phaseToUse": float64(2)},
+				},
+			}},
+		},
+	}))
+
+	// Per-phase values depend on the charging rate unit and number of phases
+	s.Equal([]Violation{
+		{Field: "/chargingProfile/chargingSchedule/0/chargingSchedulePeriod/0", Message: "limit_L2 and limit_L3 must be set together in W, as limit then applies to L1 instead of all phases"},
+		{Field: "/chargingProfile/chargingSchedule/0/chargingSchedulePeriod/2/numberPhases", Message: "setpoint_L2 and setpoint_L3 can't be set when charging on a single phase"},
+	}, s.check("ocpp201.charging-schedule-phases", "SetChargingProfileRequest", map[string]interface{}{
+		"chargingProfile": map[string]interface{}{
+			"chargingSchedule": []interface{}{map[string]interface{}{
+				"chargingRateUnit": "W",
+				"chargingSchedulePeriod": []interface{}{
+					map[string]interface{}{"startPeriod": float64(0), "limit": 3680.0, "limit_L2": 3680.0},
+					map[string]interface{}{"startPeriod": float64(60), "limit": 3680.0, "limit_L2": 3680.0, "limit_L3": 3680.0},
+					map[string]interface{}{"startPeriod": float64(120), "setpoint": 3680.0, "setpoint_L2": 3680.0, "setpoint_L3": 3680.0, "numberPhases": float64(1)},
+				},
+			}},
+		},
+	}))
+
+	// In A, the limit is always per phase
+	s.Empty(s.check("ocpp201.charging-schedule-phases", "SetChargingProfileRequest", map[string]interface{}{
+		"chargingProfile": map[string]interface{}{
+			"chargingSchedule": []interface{}{map[string]interface{}{
+				"chargingRateUnit": "A",
+				"chargingSchedulePeriod": []interface{}{
+					map[string]interface{}{"startPeriod": float64(0), "limit": 16.0, "limit_L2": 10.0, "numberPhases": float64(3)},
+				},
+			}},
+		},
+	}))
+}
+
+func (s *rulesTestSuite) TestCertificates() {
+	root, rootKey := s.certificate(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ACME Root CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	leaf, _ := s.certificate(&x509.Certificate{SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "CP001"}}, root, rootKey)
+	expired, _ := s.certificate(&x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "CP002"},
+		NotBefore:    time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, root, rootKey)
+
+	chain := encodePEM("CERTIFICATE", leaf.Raw) + encodePEM("CERTIFICATE", root.Raw)
+	s.Empty(s.check("ocpp201.certificates", "CertificateSignedRequest", map[string]interface{}{"certificateChain": chain}))
+	s.Empty(s.check("ocpp16.certificates", "InstallCertificateRequest", map[string]interface{}{"certificate": base64.StdEncoding.EncodeToString(root.Raw)}))
+	s.Equal([]Violation{
+		{Field: "/certificate", Message: "certificate is not a valid X.509 certificate: neither PEM nor base64 encoded"},
+	}, s.check("ocpp201.certificates", "InstallCertificateRequest", map[string]interface{}{"certificate": "not a certificate"}))
+
+	violations := s.check("ocpp16.certificates", "CertificateSignedRequest", map[string]interface{}{
+		"certificateChain": encodePEM("CERTIFICATE", root.Raw) + encodePEM("CERTIFICATE", leaf.Raw),
+	})
+	s.Require().Len(violations, 1)
+	s.Contains(violations[0].Message, "certificate 1 of the certificateChain is not issued by certificate 2")
+
+	csrKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "CP001"}}, csrKey)
+	s.Require().NoError(err)
+	s.Empty(s.check("ocpp201.certificates", "SignCertificateRequest", map[string]interface{}{"csr": encodePEM("CERTIFICATE REQUEST", csr)}))
+	s.Equal([]Violation{
+		{Field: "/csr", Message: `csr is not a valid certificate signing request: unexpected PEM block "CERTIFICATE"`},
+	}, s.check("ocpp16.certificates", "SignCertificateRequest", map[string]interface{}{"csr": encodePEM("CERTIFICATE", leaf.Raw)}))
+
+	// Expiry
+	s.Empty(s.check("ocpp201.certificate-expiry", "CertificateSignedRequest", map[string]interface{}{"certificateChain": chain}))
+	s.Equal([]Violation{
+		{Field: "/certificate", Message: `certificate "CN=CP002" expired at 2023-01-01T00:00:00Z`},
+	}, s.check("ocpp201.certificate-expiry", "AuthorizeRequest", map[string]interface{}{"certificate": encodePEM("CERTIFICATE", expired.Raw)}))
+	s.Equal([]Violation{
+		{Field: "/certificate", Message: `certificate "CN=CP001" is not valid before 2024-01-01T00:00:00Z`},
+	}, s.rule("ocpp16.certificate-expiry").Check(RuleContext{Action: "InstallCertificateRequest", Now: s.now.AddDate(-1, 0, 0)}, map[string]interface{}{"certificate": encodePEM("CERTIFICATE", leaf.Raw)}))
+
+	// Hash data
+	hashData, err := certificates.NewHashData(certificates.SHA256, leaf, root)
+	s.Require().NoError(err)
+	object := func(hashData certificates.HashData) map[string]interface{} {
+		return map[string]interface{}{
+			"hashAlgorithm":  string(hashData.HashAlgorithm),
+			"issuerNameHash": hashData.IssuerNameHash,
+			"issuerKeyHash":  hashData.IssuerKeyHash,
+			"serialNumber":   hashData.SerialNumber,
+		}
+	}
+	wrongKey := hashData
+	wrongKey.IssuerKeyHash = hashData.IssuerNameHash
+
+	// Hash data of the certificates of an Authorize is checked against them
+	s.Empty(s.check("ocpp201.certificate-hash-data", "AuthorizeRequest", map[string]interface{}{
+		"certificate":                 chain,
+		"iso15118CertificateHashData": []interface{}{object(hashData)},
+	}))
+	s.Equal([]Violation{
+		{Field: "/iso15118CertificateHashData/0", Message: "issuerKeyHash does not match the key of CN=ACME Root CA, the issuer of certificate 2"},
+	}, s.check("ocpp201.certificate-hash-data", "AuthorizeRequest", map[string]interface{}{
+		"certificate":                 chain,
+		"iso15118CertificateHashData": []interface{}{object(wrongKey)},
+	}))
+
+	// Hash data is checked against the known certificates
+	known := certificates.NewPool(root, leaf)
+	checkKnown := func(id, action string, payload map[string]interface{}) []Violation {
+		return s.rule(id).Check(RuleContext{Action: action, Certificates: known}, payload)
+	}
+	s.Empty(s.check("ocpp16.certificate-hash-data", "DeleteCertificateRequest", map[string]interface{}{"certificateHashData": object(wrongKey)}))
+	s.Equal([]Violation{
+		{Field: "/certificateHashData", Message: "issuerKeyHash does not match the key of CN=ACME Root CA, the issuer of certificate 2"},
+	}, checkKnown("ocpp16.certificate-hash-data", "DeleteCertificateRequest", map[string]interface{}{"certificateHashData": object(wrongKey)}))
+	s.Equal([]Violation{
+		{Field: "/certificateHashDataChain/0/childCertificateHashData/0", Message: `serialNumber "0x02" is not hexadecimal`},
+	}, checkKnown("ocpp201.certificate-hash-data", "GetInstalledCertificateIdsResponse", map[string]interface{}{
+		"certificateHashDataChain": []interface{}{
+			map[string]interface{}{
+				"certificateType":          "V2GCertificateChain",
+				"certificateHashData":      object(hashData),
+				"childCertificateHashData": []interface{}{object(certificates.HashData{HashAlgorithm: certificates.SHA256, IssuerNameHash: hashData.IssuerNameHash, IssuerKeyHash: hashData.IssuerKeyHash, SerialNumber: "0x02"})},
+			},
+		},
+	}))
+}
+
+func (s *rulesTestSuite) TestExiStreams() {
+	s.Empty(s.check("ocpp201.iso15118-exi", "Get15118EVCertificateRequest", map[string]interface{}{"exiRequest": base64.StdEncoding.EncodeToString([]byte{0x80, 0x98, 0x02})}))
+	s.Empty(s.check("ocpp201.iso15118-exi", "Get15118EVCertificateResponse", map[string]interface{}{"exiResponse": base64.StdEncoding.EncodeToString([]byte("$EXI\x80\x98"))}))
+	s.Empty(s.check("ocpp201.iso15118-exi", "Get15118EVCertificateResponse", map[string]interface{}{"exiResponse": ""}))
+	s.Equal([]Violation{
+		{Field: "/exiRequest", Message: "exiRequest is not base64 encoded"},
+	}, s.check("ocpp201.iso15118-exi", "Get15118EVCertificateRequest", map[string]interface{}{"exiRequest": "not base64!"}))
+	s.Equal([]Violation{
+		{Field: "/exiResponse", Message: "exiResponse is not an EXI stream"},
+	}, s.check("ocpp201.iso15118-exi", "Get15118EVCertificateResponse", map[string]interface{}{"exiResponse": base64.StdEncoding.EncodeToString([]byte("<xml/>"))}))
+}
+
+func (s *rulesTestSuite) TestResolveRules() {
+	ids := func(rules []Rule) []string {
+		var ids []string
+		for _, rule := range rules {
+			ids = append(ids, rule.ID)
+		}
+		return ids
+	}
+
+	defaults := ids(resolveRules(options{}))
+	s.Contains(defaults, "ocpp16.stack-level")
+	s.NotContains(defaults, "ocpp16.id-tag-expiry")
+
+	custom := Rule{ID: "ocpp16.stack-level", Description: "Custom"}
+	rules := resolveRules(options{
+		rules:         []Rule{custom, {ID: "acme.meter-values"}},
+		enabledRules:  []string{"ocpp16.id-tag-expiry"},
+		disabledRules: []string{"ocpp16.connector-id"},
+	})
+
+	s.Contains(ids(rules), "ocpp16.id-tag-expiry")
+	s.Contains(ids(rules), "acme.meter-values")
+	s.NotContains(ids(rules), "ocpp16.connector-id")
+	s.Contains(rules, custom)
+}
+
+func (s *rulesTestSuite) TestValidateMessage_Rules() {
+	registry := mock_schema_registry.NewMockSchemaRegistry(s.T())
+	schemaFromCompiler, err := jsonschema.NewCompiler().Compile(permissiveSchema)
+	s.Require().NoError(err)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: ocpp.OcppContext{Version: ocpp.V16}, Action: "StartTransactionRequest"}).Return(schemaFromCompiler, true)
+
+	message := &ocpp.Call{
+		MessageTypeId: ocpp.CALL,
+		UniqueId:      uuid.NewString(),
+		Action:        "StartTransaction",
+		Payload:       map[string]interface{}{"connectorId": float64(0), "idTag": "abc"},
+	}
+
+	validator := NewValidator(zap.NewNop(), registry)
+	result, err := validator.ValidateMessage(ocpp.OcppContext{Version: ocpp.V16}, message)
+	s.Require().NoError(err)
+	s.False(result.IsValid())
+	s.Equal([]string{"ocpp16.connector-id: transactions must be started on a connector with an ID greater than 0, got 0"}, result.Errors())
+	s.Equal([]ErrorCategory{CategoryRule}, result.ErrorCategories())
+	s.Equal([]string{"/connectorId"}, result.Fields())
+
+	validator = NewValidator(zap.NewNop(), registry, WithDisabledRules("ocpp16.connector-id"))
+	result, err = validator.ValidateMessage(ocpp.OcppContext{Version: ocpp.V16}, message)
+	s.Require().NoError(err)
+	s.True(result.IsValid())
+}
+
+func TestRules(t *testing.T) {
+	suite.Run(t, new(rulesTestSuite))
+}
@@ -0,0 +1,163 @@
+package validator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/parser"
+)
+
+// Exchange is a request and its response, identified by the unique ID of the message.
+type Exchange struct {
+	MessageId string
+	// Action of the request.
+	Action  string
+	Request map[string]interface{}
+	// Response is the payload of the CALLRESULT, or nil if there is no response or the request failed with a CALLERROR.
+	Response map[string]interface{}
+}
+
+// ValidateSequence checks the rules spanning multiple messages (see Rule.CheckSequence) against the request/response
+// pairs of a file, ordered by the line of their request. It returns the results of the messages with violations
+// by message ID, to be reported on their responses.
+func (v *Validator) ValidateSequence(octx ocpp.OcppContext, results map[string]parser.RequestResponseResult) map[string]*ValidationResult {
+	return v.ValidateExchanges(octx, newExchanges(results))
+}
+
+// ValidateExchanges checks the rules spanning multiple messages against the exchanges, in the order they occurred.
+// It returns the results of the messages with violations by message ID.
+func (v *Validator) ValidateExchanges(octx ocpp.OcppContext, exchanges []Exchange) map[string]*ValidationResult {
+	validationResults := make(map[string]*ValidationResult)
+	ctx := RuleContext{
+		OcppContext:       octx,
+		Now:               v.now(),
+		DeviceModel:       v.deviceModel,
+		ConfigurationKeys: v.configurationKeys,
+		Certificates:      v.certificates,
+	}
+	for _, rule := range v.rules {
+		if rule.CheckSequence == nil || !rule.appliesToContext(octx) {
+			continue
+		}
+
+		violations := rule.CheckSequence(ctx, exchanges)
+		for _, messageId := range slices.Sorted(maps.Keys(violations)) {
+			for _, violation := range violations[messageId] {
+				v.logger.Debug("Rule violated", zap.String("rule", rule.ID), zap.String("messageId", messageId))
+
+				if validationResults[messageId] == nil {
+					validationResults[messageId] = NewValidationResult()
+				}
+
+				validationResults[messageId].AddCategorizedError(CategoryRule, fmt.Sprintf("%s: %s", rule.ID, violation.Message))
+				if violation.Field != "" {
+					validationResults[messageId].AddFields(violation.Field)
+				}
+			}
+		}
+	}
+
+	return validationResults
+}
+
+// NewExchange returns the exchange of a request/response pair. It returns false if the request is unknown.
+func NewExchange(messageId string, result parser.RequestResponseResult) (Exchange, bool) {
+	request, found := result.GetRequest()
+	if !found {
+		return Exchange{}, false
+	}
+
+	exchange := Exchange{MessageId: messageId, Action: request.GetAction()}
+	exchange.Request, _ = request.GetPayload().(map[string]interface{})
+	if response, found := result.GetResponse(); found && response.GetMessageTypeId() == ocpp.CALL_RESULT {
+		exchange.Response, _ = response.GetPayload().(map[string]interface{})
+	}
+
+	return exchange, true
+}
+
+// newExchanges returns the exchanges of the results with a request, ordered by the line of their request.
+func newExchanges(results map[string]parser.RequestResponseResult) []Exchange {
+	type entry struct {
+		line     int
+		exchange Exchange
+	}
+
+	entries := make([]entry, 0, len(results))
+	for messageId, result := range results {
+		exchange, found := NewExchange(messageId, result)
+		if !found {
+			continue
+		}
+
+		entries = append(entries, entry{line: result.Request.Line(), exchange: exchange})
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return cmp.Or(cmp.Compare(a.line, b.line), cmp.Compare(a.exchange.MessageId, b.exchange.MessageId))
+	})
+
+	exchanges := make([]Exchange, 0, len(entries))
+	for _, e := range entries {
+		exchanges = append(exchanges, e.exchange)
+	}
+
+	return exchanges
+}
+
+// followUp is a request expected to follow an accepted request, e.g. a StartTransaction following a RemoteStartTransaction.
+type followUp struct {
+	// action of the expected request.
+	action string
+	// description of the expected request, used in the violation message.
+	description string
+	// matches returns true if the request payload is the expected one.
+	matches func(request map[string]interface{}) bool
+}
+
+// checkFollowedBy returns a sequence check that every request of the action that was accepted is followed by
+// the expected request. The expectation returns false if no request is expected, e.g. because the response
+// shows the request was already handled.
+func checkFollowedBy(action string, expect func(exchange Exchange) (followUp, bool)) func(RuleContext, []Exchange) map[string][]Violation {
+	return func(_ RuleContext, exchanges []Exchange) map[string][]Violation {
+		violations := make(map[string][]Violation)
+		for i, exchange := range exchanges {
+			if exchange.Action != action || str(exchange.Response, "status") != accepted {
+				continue
+			}
+
+			expected, ok := expect(exchange)
+			if !ok {
+				continue
+			}
+
+			followed := slices.ContainsFunc(exchanges[i+1:], func(next Exchange) bool {
+				return next.Action == expected.action && expected.matches(next.Request)
+			})
+			if !followed {
+				violations[exchange.MessageId] = append(violations[exchange.MessageId], Violation{
+					Field:   "/status",
+					Message: fmt.Sprintf("%s was accepted, but no %s followed", action, expected.description),
+				})
+			}
+		}
+
+		return violations
+	}
+}
+
+// sameNumber returns true if the field is missing from the expected object, or has the same value in both objects.
+func sameNumber(expected, actual map[string]interface{}, key string) bool {
+	want, found := number(expected, key)
+	if !found {
+		return true
+	}
+
+	got, found := number(actual, key)
+	return found && got == want
+}
@@ -0,0 +1,168 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/ChargePi/chargeflow/pkg/ocpp"
+	"github.com/ChargePi/chargeflow/pkg/parser"
+)
+
+type sequenceTestSuite struct {
+	suite.Suite
+	results map[string]parser.RequestResponseResult
+	line    int
+}
+
+func (s *sequenceTestSuite) SetupTest() {
+	s.results = make(map[string]parser.RequestResponseResult)
+	s.line = 0
+}
+
+// exchange adds a request and its response (if any) to the results, on the next lines.
+func (s *sequenceTestSuite) exchange(messageId, action string, request, response map[string]interface{}) {
+	result := parser.NewRequestResponseResult()
+
+	s.line++
+	result.AddRequest(&ocpp.Call{MessageTypeId: ocpp.CALL, UniqueId: messageId, Action: action, Payload: request})
+	result.Request.SetOrigin("", s.line, "")
+
+	if response != nil {
+		s.line++
+		result.AddResponse(&ocpp.CallResult{MessageTypeId: ocpp.CALL_RESULT, UniqueId: messageId, Action: action, Payload: response})
+		result.Response.SetOrigin("", s.line, "")
+	}
+
+	s.results[messageId] = *result
+}
+
+func (s *sequenceTestSuite) validate(version ocpp.Version) map[string][]string {
+	results := NewValidator(zap.NewNop(), nil).ValidateSequence(ocpp.OcppContext{Version: version}, s.results)
+
+	errs := make(map[string][]string, len(results))
+	for messageId, result := range results {
+		s.Equal([]string{"/status"}, result.Fields())
+		errs[messageId] = result.Errors()
+	}
+
+	return errs
+}
+
+func (s *sequenceTestSuite) TestRemoteStartTransaction() {
+	accepted := map[string]interface{}{"status": "Accepted"}
+
+	s.exchange("1", "RemoteStartTransaction", map[string]interface{}{"idTag": "A", "connectorId": float64(1)}, accepted)
+	s.exchange("2", "RemoteStartTransaction", map[string]interface{}{"idTag": "B"}, accepted)
+	s.exchange("3", "RemoteStartTransaction", map[string]interface{}{"idTag": "C"}, map[string]interface{}{"status": "Rejected"})
+	s.exchange("4", "StartTransaction", map[string]interface{}{"idTag": "A", "connectorId": float64(2)}, nil)
+	s.exchange("5", "StartTransaction", map[string]interface{}{"idTag": "B", "connectorId": float64(2)}, nil)
+	// A StartTransaction before the remote start doesn't count
+	s.exchange("6", "RemoteStartTransaction", map[string]interface{}{"idTag": "B"}, accepted)
+
+	s.Equal(map[string][]string{
+		"1": {"ocpp16.remote-start-transaction: RemoteStartTransaction was accepted, but no StartTransaction for idTag A followed"},
+		"6": {"ocpp16.remote-start-transaction: RemoteStartTransaction was accepted, but no StartTransaction for idTag B followed"},
+	}, s.validate(ocpp.V16))
+}
+
+func (s *sequenceTestSuite) TestRequestStartTransaction() {
+	s.exchange("1", "RequestStartTransaction", map[string]interface{}{"remoteStartId": float64(1)}, map[string]interface{}{"status": "Accepted"})
+	s.exchange("2", "RequestStartTransaction", map[string]interface{}{"remoteStartId": float64(2)}, map[string]interface{}{"status": "Accepted"})
+	// The transaction had already started
+	s.exchange("3", "RequestStartTransaction", map[string]interface{}{"remoteStartId": float64(3)}, map[string]interface{}{"status": "Accepted", "transactionId": "tx-3"})
+	s.exchange("4", "TransactionEvent", map[string]interface{}{"eventType": "Started", "transactionInfo": map[string]interface{}{"remoteStartId": float64(1)}}, nil)
+
+	s.Equal(map[string][]string{
+		"2": {"ocpp201.request-start-transaction: RequestStartTransaction was accepted, but no TransactionEvent with remoteStartId 2 followed"},
+	}, s.validate(ocpp.V20))
+}
+
+func (s *sequenceTestSuite) TestTriggerMessage() {
+	accepted := map[string]interface{}{"status": "Accepted"}
+
+	s.exchange("1", "TriggerMessage", map[string]interface{}{"requestedMessage": "StatusNotification", "connectorId": float64(1)}, accepted)
+	s.exchange("2", "TriggerMessage", map[string]interface{}{"requestedMessage": "Heartbeat"}, accepted)
+	s.exchange("3", "TriggerMessage", map[string]interface{}{"requestedMessage": "BootNotification"}, map[string]interface{}{"status": "NotImplemented"})
+	s.exchange("4", "StatusNotification", map[string]interface{}{"connectorId": float64(2), "status": "Available"}, nil)
+	s.exchange("5", "Heartbeat", map[string]interface{}{}, nil)
+
+	s.Equal(map[string][]string{
+		"1": {"ocpp16.trigger-message: TriggerMessage was accepted, but no StatusNotification followed"},
+	}, s.validate(ocpp.V16))
+
+	s.SetupTest()
+	s.exchange("1", "TriggerMessage", map[string]interface{}{"requestedMessage": "SignChargingStationCertificate"}, accepted)
+	s.exchange("2", "TriggerMessage", map[string]interface{}{"requestedMessage": "LogStatusNotification"}, accepted)
+	s.exchange("3", "SignCertificate", map[string]interface{}{"csr": "csr"}, nil)
+
+	s.Equal(map[string][]string{
+		"2": {"ocpp201.trigger-message: TriggerMessage was accepted, but no LogStatusNotification followed"},
+	}, s.validate(ocpp.V21))
+}
+
+func (s *sequenceTestSuite) TestCompositeSchedule() {
+	accepted := map[string]interface{}{"status": "Accepted"}
+	period := func(startPeriod, limit float64) map[string]interface{} {
+		return map[string]interface{}{"startPeriod": startPeriod, "limit": limit}
+	}
+	composite := func(periods ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"status":        "Accepted",
+			"connectorId":   float64(1),
+			"scheduleStart": "2024-01-01T12:00:00Z",
+			"chargingSchedule": map[string]interface{}{
+				"duration":               float64(7200),
+				"chargingRateUnit":       "A",
+				"chargingSchedulePeriod": periods,
+			},
+		}
+	}
+	getComposite := map[string]interface{}{"connectorId": float64(1), "duration": float64(7200), "chargingRateUnit": "A"}
+
+	s.exchange("1", "SetChargingProfile", map[string]interface{}{
+		"connectorId": float64(0),
+		"csChargingProfiles": map[string]interface{}{
+			"chargingProfileId": float64(1), "stackLevel": float64(0), "chargingProfilePurpose": "ChargePointMaxProfile", "chargingProfileKind": "Absolute",
+			"chargingSchedule": map[string]interface{}{
+				"startSchedule":          "2024-01-01T12:00:00Z",
+				"chargingRateUnit":       "A",
+				"chargingSchedulePeriod": []interface{}{period(0, 32), period(3600, 16)},
+			},
+		},
+	}, accepted)
+	s.exchange("2", "SetChargingProfile", map[string]interface{}{
+		"connectorId": float64(1),
+		"csChargingProfiles": map[string]interface{}{
+			"chargingProfileId": float64(2), "stackLevel": float64(0), "chargingProfilePurpose": "TxDefaultProfile", "chargingProfileKind": "Relative",
+			"chargingSchedule": map[string]interface{}{"chargingRateUnit": "A", "chargingSchedulePeriod": []interface{}{period(0, 20)}},
+		},
+	}, accepted)
+	s.exchange("3", "GetCompositeSchedule", getComposite, composite(period(0, 20), period(3600, 16)))
+	s.exchange("4", "GetCompositeSchedule", getComposite, composite(period(0, 20)))
+	s.exchange("5", "ClearChargingProfile", map[string]interface{}{"id": float64(1)}, accepted)
+	s.exchange("6", "GetCompositeSchedule", getComposite, composite(period(0, 20)))
+
+	// The rule is enabled by default
+	validator := NewValidator(zap.NewNop(), nil)
+	results := validator.ValidateSequence(ocpp.OcppContext{Version: ocpp.V16}, s.results)
+
+	s.Len(results, 1)
+	s.Require().Contains(results, "4")
+	s.Equal([]string{"/chargingSchedule"}, results["4"].Fields())
+	s.Equal([]string{
+		"ocpp16.composite-schedule: from 2024-01-01T13:00:00Z until 2024-01-01T14:00:00Z the simulated composite limit is 16 A, but 20 A was reported",
+	}, results["4"].Errors())
+}
+
+func (s *sequenceTestSuite) TestDisabled() {
+	s.exchange("1", "TriggerMessage", map[string]interface{}{"requestedMessage": "Heartbeat"}, map[string]interface{}{"status": "Accepted"})
+
+	validator := NewValidator(zap.NewNop(), nil, WithDisabledRules("ocpp16.trigger-message"))
+	s.Empty(validator.ValidateSequence(ocpp.OcppContext{Version: ocpp.V16}, s.results))
+}
+
+func TestSequence(t *testing.T) {
+	suite.Run(t, new(sequenceTestSuite))
+}
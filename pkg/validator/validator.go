@@ -5,10 +5,18 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/kaptinlin/jsonschema"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/ChargePi/chargeflow/pkg/certificates"
+	"github.com/ChargePi/chargeflow/pkg/configkeys"
+	"github.com/ChargePi/chargeflow/pkg/devicemodel"
 	"github.com/ChargePi/chargeflow/pkg/ocmf"
 	"github.com/ChargePi/chargeflow/pkg/ocpp"
 	"github.com/ChargePi/chargeflow/pkg/schema_registry"
@@ -21,28 +29,79 @@ const (
 	// which carries an optional top-level "meterValue" array of the same shape as
 	// MeterValues.req rather than always requiring a dedicated MeterValues.req.
 	transactionEventAction = "TransactionEvent"
+	// dataTransferAction is the action of DataTransfer, whose data is validated against the schema registered for
+	// its vendorId and messageId, if any.
+	dataTransferAction = "DataTransfer"
 )
 
 var ErrCannotCastToCallError = errors.New("cannot cast message to CallError")
 
 type Validator struct {
-	logger   *zap.Logger
-	registry schema_registry.SchemaRegistry
+	logger            *zap.Logger
+	registry          schema_registry.SchemaRegistry
+	rules             []Rule
+	now               func() time.Time
+	securityExtension bool
+	deviceModel       *devicemodel.Catalog
+	configurationKeys *configkeys.Catalog
+	certificates      *certificates.Pool
 }
 
-func NewValidator(logger *zap.Logger, registry schema_registry.SchemaRegistry) *Validator {
+func NewValidator(logger *zap.Logger, registry schema_registry.SchemaRegistry, opts ...Option) *Validator {
+	config := options{
+		now:               time.Now,
+		securityExtension: true,
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.deviceModel == nil {
+		config.deviceModel = devicemodel.Standard()
+	}
+
+	if config.configurationKeys == nil {
+		config.configurationKeys = configkeys.Standard()
+	}
+
+	if config.certificates == nil {
+		config.certificates = certificates.NewPool()
+	}
+
 	return &Validator{
-		logger:   logger.Named("validator"),
-		registry: registry,
+		logger:            logger.Named("validator"),
+		registry:          registry,
+		rules:             resolveRules(config),
+		now:               config.now,
+		securityExtension: config.securityExtension,
+		deviceModel:       config.deviceModel,
+		configurationKeys: config.configurationKeys,
+		certificates:      config.certificates,
 	}
 }
 
 // ValidateMessage validates the message. It checks if the message has an action, a payload, and a unique ID.
-// It also validates the payload against the schema for the given OCPP version.
+// It also validates the payload against the schema for the given OCPP version and checks the semantic rules.
 // When OcppContext.Vendor and/or OcppContext.Model are non-empty the registry attempts a
 // vendor/model-specific schema first, falling back to the base OCPP spec schema.
 func (v *Validator) ValidateMessage(octx ocpp.OcppContext, message ocpp.Message) (*ValidationResult, error) {
-	logger := v.logger.With(zap.String("vendor", octx.Vendor), zap.String("model", octx.Model), zap.String("action", message.GetAction()))
+	return v.validateMessage(octx, message, nil)
+}
+
+// ValidateResponse validates a response like ValidateMessage, but gives the rules access to the payload of
+// the request the response answers, e.g. to compare fields of the request and the response.
+func (v *Validator) ValidateResponse(octx ocpp.OcppContext, request, response ocpp.Message) (*ValidationResult, error) {
+	if request == nil {
+		return v.validateMessage(octx, response, nil)
+	}
+
+	return v.validateMessage(octx, response, request.GetPayload())
+}
+
+func (v *Validator) validateMessage(octx ocpp.OcppContext, message ocpp.Message, requestPayload interface{}) (*ValidationResult, error) {
+	action := message.GetAction()
+	logger := v.logger.With(zap.String("vendor", octx.Vendor), zap.String("model", octx.Model), zap.String("action", action))
 	logger.Info("Validating message")
 
 	result := NewValidationResult()
@@ -50,16 +109,20 @@ func (v *Validator) ValidateMessage(octx ocpp.OcppContext, message ocpp.Message)
 	// Check if a message has a unique ID
 	uniqueId := message.GetUniqueId()
 	if uniqueId == "" {
-		result.AddError(uniqueIdEmptyErr)
+		result.AddCategorizedError(CategoryMessage, uniqueIdEmptyErr)
 	}
 
 	payload := message.GetPayload()
 
+	if !v.securityExtension && octx.Version.Features().SecurityExtension && ocpp.IsSecurityExtensionAction(action) {
+		result.AddCategorizedError(CategoryUnsupportedAction, fmt.Sprintf("%s is part of the OCPP %s Security Extension, which is disabled", action, octx.Version.Name()))
+		return result, nil
+	}
+
 	switch message.GetMessageTypeId() {
 	case ocpp.CALL:
-		action := message.GetAction()
 		if action == "" {
-			result.AddError(actionEmptyErr)
+			result.AddCategorizedError(CategoryMessage, actionEmptyErr)
 			break
 		}
 
@@ -68,7 +131,11 @@ func (v *Validator) ValidateMessage(octx ocpp.OcppContext, message ocpp.Message)
 			return result, errors.Wrap(err, "unable to validate message payload")
 		}
 
+		v.checkRules(octx, action+"Request", payload, nil, result)
+
 		switch {
+		case action == dataTransferAction:
+			v.validateDataTransferData(octx, action+"Request", payload, payload, result)
 		case octx.Version == ocpp.V16 && action == meterValuesAction:
 			v.validateOCMFSampledValues(payload, result)
 		case octx.Version == ocpp.V20 && (action == meterValuesAction || action == transactionEventAction):
@@ -76,14 +143,13 @@ func (v *Validator) ValidateMessage(octx ocpp.OcppContext, message ocpp.Message)
 		}
 
 	case ocpp.SEND:
-		if octx.Version != ocpp.V21 {
-			result.AddError("SEND messages are only supported in OCPP 2.1")
+		if !octx.Version.Features().Send {
+			result.AddCategorizedError(CategoryMessageType, "SEND messages are only supported in OCPP 2.1")
 			return result, nil
 		}
 
-		action := message.GetAction()
 		if action == "" {
-			result.AddError(actionEmptyErr)
+			result.AddCategorizedError(CategoryMessage, actionEmptyErr)
 			break
 		}
 
@@ -92,10 +158,11 @@ func (v *Validator) ValidateMessage(octx ocpp.OcppContext, message ocpp.Message)
 			return result, errors.Wrap(err, "unable to validate message payload")
 		}
 
+		v.checkRules(octx, action+"Request", payload, nil, result)
+
 	case ocpp.CALL_RESULT:
-		action := message.GetAction()
 		if action == "" {
-			result.AddError(actionEmptyErr)
+			result.AddCategorizedError(CategoryMessage, actionEmptyErr)
 		}
 
 		err := v.validatePayload(octx, payload, action+"Response", result)
@@ -103,19 +170,28 @@ func (v *Validator) ValidateMessage(octx ocpp.OcppContext, message ocpp.Message)
 			return result, errors.Wrap(err, "unable to validate message payload")
 		}
 
+		if action != "" {
+			v.checkRules(octx, action+"Response", payload, requestPayload, result)
+		}
+
+		if action == dataTransferAction {
+			v.validateDataTransferData(octx, action+"Response", payload, requestPayload, result)
+		}
+
 	case ocpp.CALL_ERROR:
 		callError, ok := message.(*ocpp.CallError)
 		if !ok {
 			return result, ErrCannotCastToCallError
 		}
 
-		if !ocpp.IsErrorCodeValid(callError.ErrorCode) {
-			result.AddError(fmt.Sprintf("invalid error code: %s", callError.ErrorCode))
+		// SOAP faults don't use the OCPP-J error codes
+		if !octx.Version.Features().Soap && !ocpp.IsErrorCodeValid(callError.ErrorCode) {
+			result.AddCategorizedError(CategoryErrorCode, fmt.Sprintf("invalid error code: %s", callError.ErrorCode))
 		}
 
 	case ocpp.CALL_RESULT_ERROR:
-		if octx.Version != ocpp.V21 {
-			result.AddError("CALL_RESULT_ERROR messages are only supported in OCPP 2.1")
+		if !octx.Version.Features().CallResultError {
+			result.AddCategorizedError(CategoryMessageType, "CALL_RESULT_ERROR messages are only supported in OCPP 2.1")
 			return result, nil
 		}
 
@@ -125,7 +201,7 @@ func (v *Validator) ValidateMessage(octx ocpp.OcppContext, message ocpp.Message)
 		}
 
 		if !ocpp.IsErrorCodeValid(callError.ErrorCode) {
-			result.AddError(fmt.Sprintf("invalid error code: %s", callError.ErrorCode))
+			result.AddCategorizedError(CategoryErrorCode, fmt.Sprintf("invalid error code: %s", callError.ErrorCode))
 		}
 	}
 
@@ -139,7 +215,7 @@ func (v *Validator) validatePayload(
 	validationResults *ValidationResult,
 ) error {
 	if payload == nil {
-		validationResults.AddError(payloadEmptyErr)
+		validationResults.AddCategorizedError(CategoryMessage, payloadEmptyErr)
 		return nil
 	}
 
@@ -148,19 +224,115 @@ func (v *Validator) validatePayload(
 		Action:      action,
 	})
 	if !found {
-		return errors.Errorf("no schema found for action %s in OCPP version %s", action, octx.Version)
+		return errors.Errorf("no schema found for action %s in OCPP version %s", action, octx.Version.Name())
 	}
 
 	evaluationResult := schema.Validate(payload)
 	if !evaluationResult.IsValid() {
-		for _, evaluationError := range evaluationResult.Errors {
-			validationResults.AddError(evaluationError.Error())
+		for _, keyword := range slices.Sorted(maps.Keys(evaluationResult.Errors)) {
+			validationResults.AddCategorizedError(ErrorCategory(keyword), evaluationResult.Errors[keyword].Error())
 		}
+
+		validationResults.AddFields(failingFields(evaluationResult, "")...)
 	}
 
 	return nil
 }
 
+// validateDataTransferData validates the data of a DataTransfer against the schema registered for the vendorId and
+// messageId of the request, falling back to the schema of the vendorId. Data sent as a string is parsed as JSON.
+// DataTransfers without a registered data schema are not checked.
+func (v *Validator) validateDataTransferData(
+	octx ocpp.OcppContext,
+	action string,
+	payload, request interface{},
+	validationResults *ValidationResult,
+) {
+	object, _ := payload.(map[string]interface{})
+	requestObject, _ := request.(map[string]interface{})
+	vendorId, messageId := str(requestObject, "vendorId"), str(requestObject, "messageId")
+
+	data, found := object["data"]
+	if !found || vendorId == "" {
+		return
+	}
+
+	schema, found := v.registry.GetSchema(context.Background(), schema_registry.GetSchemaRequest{
+		OcppContext: octx,
+		Action:      action,
+		VendorId:    vendorId,
+		MessageId:   messageId,
+	})
+	if !found && messageId != "" {
+		schema, found = v.registry.GetSchema(context.Background(), schema_registry.GetSchemaRequest{
+			OcppContext: octx,
+			Action:      action,
+			VendorId:    vendorId,
+		})
+	}
+
+	if !found {
+		return
+	}
+
+	if raw, isString := data.(string); isString {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			validationResults.AddCategorizedError(CategoryDataTransfer, fmt.Sprintf("data is not valid JSON: %s", err))
+			validationResults.AddFields("/data")
+			return
+		}
+	}
+
+	evaluationResult := schema.Validate(data)
+	if !evaluationResult.IsValid() {
+		for _, keyword := range slices.Sorted(maps.Keys(evaluationResult.Errors)) {
+			validationResults.AddCategorizedError(CategoryDataTransfer, fmt.Sprintf("data: %s", evaluationResult.Errors[keyword].Error()))
+		}
+
+		validationResults.AddFields(failingFields(evaluationResult, "/data")...)
+	}
+}
+
+// failingFields returns the JSON pointers of the fields that failed validation. Missing required properties
+// are reported at the location they are expected at. Instance locations of nested results are relative
+// to their parent, so they are joined with the parent's location.
+func failingFields(result *jsonschema.EvaluationResult, parent string) []string {
+	location := parent + result.InstanceLocation
+
+	var fields []string
+	hasInvalidDetails := false
+	for _, detail := range result.Details {
+		if detail.IsValid() {
+			continue
+		}
+
+		hasInvalidDetails = true
+		fields = append(fields, failingFields(detail, location)...)
+	}
+
+	for _, keyword := range slices.Sorted(maps.Keys(result.Errors)) {
+		evaluationError := result.Errors[keyword]
+		if keyword == "required" {
+			properties, found := evaluationError.Params["property"]
+			if !found {
+				properties = evaluationError.Params["properties"]
+			}
+
+			for _, property := range strings.Split(fmt.Sprint(properties), ",") {
+				fields = append(fields, location+"/"+strings.Trim(strings.TrimSpace(property), "'"))
+			}
+			continue
+		}
+
+		// Errors of nested fields are already reported by the details
+		if !hasInvalidDetails {
+			fields = append(fields, location)
+		}
+	}
+
+	return fields
+}
+
 // decodeMeterValuesPayload re-decodes a generically-parsed payload carrying a top-level
 // "meterValue" array (MeterValues.req, and OCPP 2.0.1/2.1's TransactionEvent.req) into
 // OCPP's own MeterValue/SampledValue/SignedMeterValue types so callers can work with
@@ -230,7 +402,7 @@ func (v *Validator) validateOCMFSignedMeterValues(payload interface{}, validatio
 			raw, err := base64.StdEncoding.DecodeString(signed.SignedMeterData)
 			if err != nil {
 				logger.Warn("signedMeterValue.signedMeterData is declared as OCMF but is not valid base64", zap.Error(err))
-				validationResults.AddError(fmt.Sprintf("signedMeterValue.signedMeterData is declared as OCMF (encodingMethod) but is not valid base64: %s", err))
+				validationResults.AddCategorizedError(CategoryOCMF, fmt.Sprintf("signedMeterValue.signedMeterData is declared as OCMF (encodingMethod) but is not valid base64: %s", err))
 				continue
 			}
 
@@ -245,14 +417,14 @@ func (v *Validator) validateOCMFRecord(logger *zap.Logger, record string, valida
 	evaluationResult, err := ocmf.Validate(record)
 	if err != nil {
 		logger.Warn("OCMF record could not be parsed", zap.Error(err))
-		validationResults.AddError(fmt.Sprintf("sampledValue contains an OCMF record that could not be parsed: %s", err))
+		validationResults.AddCategorizedError(CategoryOCMF, fmt.Sprintf("sampledValue contains an OCMF record that could not be parsed: %s", err))
 		return
 	}
 
 	if !evaluationResult.IsValid() {
 		logger.Debug("OCMF record failed schema validation", zap.Int("errors", len(evaluationResult.Errors)))
-		for _, evaluationError := range evaluationResult.Errors {
-			validationResults.AddError(fmt.Sprintf("OCMF: %s", evaluationError.Error()))
+		for _, keyword := range slices.Sorted(maps.Keys(evaluationResult.Errors)) {
+			validationResults.AddCategorizedError(CategoryOCMF, fmt.Sprintf("OCMF: %s", evaluationResult.Errors[keyword].Error()))
 		}
 		return
 	}
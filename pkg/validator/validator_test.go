@@ -639,6 +639,131 @@ func (s *validatorTestSuite) TestValidateMessage_MeterValuesOCMF() {
 	}
 }
 
+func (s *validatorTestSuite) TestValidateMessage_FailingFields() {
+	nestedSchema := []byte(`{
+		"type": "object",
+		"properties": {
+			"idTag": {"type": "string", "maxLength": 20},
+			"idTagInfo": {
+				"type": "object",
+				"properties": {"status": {"type": "string", "enum": ["Accepted", "Blocked"]}},
+				"required": ["status", "expiryDate"]
+			}
+		},
+		"required": ["connectorId", "idTag"]
+	}`)
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(s.T())
+	schemaFromCompiler, err := s.compiler.Compile(nestedSchema)
+	s.Require().NoError(err)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: ocpp.OcppContext{Version: ocpp.V16}, Action: "AuthorizeResponse"}).Return(schemaFromCompiler, true)
+
+	validator := NewValidator(s.logger, registry)
+
+	result, err := validator.ValidateMessage(ocpp.OcppContext{Version: ocpp.V16}, &ocpp.CallResult{
+		MessageTypeId: ocpp.CALL_RESULT,
+		UniqueId:      uuid.NewString(),
+		Action:        "Authorize",
+		Payload: map[string]interface{}{
+			"idTag":     "this-id-tag-is-way-too-long",
+			"idTagInfo": map[string]interface{}{"status": "Unknown"},
+		},
+	})
+	s.Require().NoError(err)
+	s.False(result.IsValid())
+	s.ElementsMatch([]string{"/idTag", "/idTagInfo/status", "/idTagInfo/expiryDate", "/connectorId"}, result.Fields())
+}
+
+func (s *validatorTestSuite) TestValidateMessage_SecurityExtension() {
+	schemaFromCompiler, err := s.compiler.Compile([]byte(`{"type": "object"}`))
+	s.Require().NoError(err)
+
+	message := &ocpp.Call{
+		MessageTypeId: ocpp.CALL,
+		UniqueId:      uuid.NewString(),
+		Action:        "SignCertificate",
+		Payload:       map[string]interface{}{"csr": "csr"},
+	}
+
+	registry := mock_schema_registry.NewMockSchemaRegistry(s.T())
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: ocpp.OcppContext{Version: ocpp.V16}, Action: "SignCertificateRequest"}).Return(schemaFromCompiler, true)
+
+	result, err := NewValidator(s.logger, registry).ValidateMessage(ocpp.OcppContext{Version: ocpp.V16}, message)
+	s.Require().NoError(err)
+	s.True(result.IsValid())
+
+	// The schema registry is not queried if the Security Extension is disabled
+	validator := NewValidator(s.logger, mock_schema_registry.NewMockSchemaRegistry(s.T()), WithSecurityExtension(false))
+	result, err = validator.ValidateMessage(ocpp.OcppContext{Version: ocpp.V16}, message)
+	s.Require().NoError(err)
+	s.False(result.IsValid())
+	s.Equal([]string{"SignCertificate is part of the OCPP 1.6 Security Extension, which is disabled"}, result.Errors())
+	s.Equal([]ErrorCategory{CategoryUnsupportedAction}, result.ErrorCategories())
+}
+
+func (s *validatorTestSuite) TestValidateMessage_DataTransfer() {
+	payloadSchema, err := s.compiler.Compile([]byte(`{"type": "object"}`))
+	s.Require().NoError(err)
+	dataSchema, err := s.compiler.Compile([]byte(`{"type": "object", "properties": {"level": {"type": "integer"}}, "required": ["level"]}`))
+	s.Require().NoError(err)
+
+	octx := ocpp.OcppContext{Version: ocpp.V16}
+	registry := mock_schema_registry.NewMockSchemaRegistry(s.T())
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest"}).Return(payloadSchema, true)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferResponse"}).Return(payloadSchema, true)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.acme", MessageId: "SetLevel"}).Return(dataSchema, true)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferResponse", VendorId: "com.acme", MessageId: "SetLevel"}).Return(nil, false)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferResponse", VendorId: "com.acme"}).Return(dataSchema, true)
+	registry.EXPECT().GetSchema(mock.Anything, schema_registry.GetSchemaRequest{OcppContext: octx, Action: "DataTransferRequest", VendorId: "com.other"}).Return(nil, false)
+	validator := NewValidator(s.logger, registry)
+
+	request := func(vendorId, messageId, data string) *ocpp.Call {
+		return &ocpp.Call{
+			MessageTypeId: ocpp.CALL,
+			UniqueId:      uuid.NewString(),
+			Action:        "DataTransfer",
+			Payload:       map[string]interface{}{"vendorId": vendorId, "messageId": messageId, "data": data},
+		}
+	}
+
+	result, err := validator.ValidateMessage(octx, request("com.acme", "SetLevel", `{"level": 3}`))
+	s.Require().NoError(err)
+	s.True(result.IsValid())
+
+	result, err = validator.ValidateMessage(octx, request("com.acme", "SetLevel", `{"level": "high"}`))
+	s.Require().NoError(err)
+	s.False(result.IsValid())
+	s.Equal([]ErrorCategory{CategoryDataTransfer}, result.ErrorCategories())
+	s.Equal([]string{"/data/level"}, result.Fields())
+
+	result, err = validator.ValidateMessage(octx, request("com.acme", "SetLevel", "level=3"))
+	s.Require().NoError(err)
+	s.Require().Len(result.Errors(), 1)
+	s.Contains(result.Errors()[0], "data is not valid JSON")
+
+	// DataTransfers without a data schema are not checked
+	result, err = validator.ValidateMessage(octx, &ocpp.Call{
+		MessageTypeId: ocpp.CALL,
+		UniqueId:      uuid.NewString(),
+		Action:        "DataTransfer",
+		Payload:       map[string]interface{}{"vendorId": "com.other", "data": "level=3"},
+	})
+	s.Require().NoError(err)
+	s.True(result.IsValid())
+
+	// Responses are checked with the vendorId and messageId of the request, falling back to the schema of the vendor
+	response := &ocpp.CallResult{
+		MessageTypeId: ocpp.CALL_RESULT,
+		UniqueId:      uuid.NewString(),
+		Action:        "DataTransfer",
+		Payload:       map[string]interface{}{"status": "Accepted", "data": `{}`},
+	}
+	result, err = validator.ValidateResponse(octx, request("com.acme", "SetLevel", `{"level": 3}`), response)
+	s.Require().NoError(err)
+	s.False(result.IsValid())
+	s.Equal([]string{"/data/level"}, result.Fields())
+}
+
 func TestValidator(t *testing.T) {
 	suite.Run(t, new(validatorTestSuite))
 }